@@ -3,17 +3,34 @@ package main
 import (
 	"app/internal/application"
 	"fmt"
+	"os"
+	"time"
 )
 
 func main() {
 	// env
-	// ...
+	authCfg := application.ConfigAuth{
+		APIKeysFilePath:         os.Getenv("AUTH_API_KEYS_FILE"),
+		JWTHMACSecretFilePath:   os.Getenv("AUTH_JWT_HMAC_SECRET_FILE"),
+		JWTRSAPublicKeyFilePath: os.Getenv("AUTH_JWT_RSA_PUBLIC_KEY_FILE"),
+		JWKSFilePath:            os.Getenv("AUTH_JWKS_FILE"),
+		JWTIssuer:               os.Getenv("AUTH_JWT_ISSUER"),
+		JWTAudience:             os.Getenv("AUTH_JWT_AUDIENCE"),
+		RequireForReads:         os.Getenv("AUTH_REQUIRE_FOR_READS") == "true",
+		PolicyFilePath:          os.Getenv("AUTH_POLICY_FILE"),
+	}
 
 	// app
 	// - config
 	cfg := &application.ConfigServerChi{
-		ServerAddress: ":8080",
-		LoaderFilePath: "docs/db/vehicles_100.json",
+		ServerAddress:               ":8080",
+		LoaderFilePath:              "docs/db/vehicles_100.json",
+		AuditFilePath:               os.Getenv("AUDIT_LOG_FILE"),
+		AliasesFilePath:             os.Getenv("ALIASES_FILE"),
+		RegistrationFormatsFilePath: os.Getenv("REGISTRATION_FORMATS_FILE"),
+		PurgeRetention:              30 * 24 * time.Hour,
+		RequestTimeout:              5 * time.Second,
+		Auth:                        authCfg,
 	}
 	app := application.NewServerChi(cfg)
 	// - run
@@ -21,4 +38,4 @@ func main() {
 		fmt.Println(err)
 		return
 	}
-}
\ No newline at end of file
+}
@@ -1,11 +1,21 @@
 package application
 
 import (
+	"app/internal"
+	"app/internal/auth"
 	"app/internal/handler"
 	"app/internal/loader"
+	appmiddleware "app/internal/middleware"
+	"app/internal/normalize"
+	"app/internal/registration"
 	"app/internal/repository"
 	"app/internal/service"
+	"app/internal/vin"
+	"context"
+	"log"
 	"net/http"
+	"sort"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -17,13 +27,73 @@ type ConfigServerChi struct {
 	ServerAddress string
 	// LoaderFilePath is the path to the file that contains the vehicles
 	LoaderFilePath string
+	// AuditFilePath is the path to the JSON lines file of the audit log (empty keeps it in memory)
+	AuditFilePath string
+	// AliasesFilePath is the path to the JSON file with the alias tables of the attributes (empty uses the default ones)
+	AliasesFilePath string
+	// RegistrationFormatsFilePath is the path to the JSON file with the plate formats by country (empty uses the default ones)
+	RegistrationFormatsFilePath string
+	// PurgeRetention is how long soft deleted vehicles stay in the trash before being hard deleted (0 disables the purge)
+	PurgeRetention time.Duration
+	// PurgeInterval is how often the purge job runs (defaults to one hour)
+	PurgeInterval time.Duration
+	// IdempotencyTTL is how long the responses of requests with an Idempotency-Key are remembered
+	IdempotencyTTL time.Duration
+	// RequestTimeout is the maximum duration of a request before its context is cancelled (0 means no timeout)
+	RequestTimeout time.Duration
+	// Auth is the configuration of the authentication
+	Auth ConfigAuth
+	// RateLimit is the configuration of the rate limiting
+	RateLimit ConfigRateLimit
+}
+
+// ConfigRateLimit is a struct that represents the configuration of the rate limiting
+type ConfigRateLimit struct {
+	// Default is the limit of the routes without a specific one
+	Default internal.RateLimit
+	// Routes is a map of limits by route, as "METHOD /pattern" (e.g. "POST /vehicles/batch")
+	Routes map[string]internal.RateLimit
+}
+
+// ConfigAuth is a struct that represents the configuration of the authentication.
+// Writes always require credentials; with no source configured every write is rejected
+type ConfigAuth struct {
+	// APIKeysFilePath is the path to the JSON file with the static api keys
+	APIKeysFilePath string
+	// JWTHMACSecretFilePath is the path to the file with the HS256 secret
+	JWTHMACSecretFilePath string
+	// JWTRSAPublicKeyFilePath is the path to the PEM file with the RS256 public key
+	JWTRSAPublicKeyFilePath string
+	// JWKSFilePath is the path to a local JWKS file
+	JWKSFilePath string
+	// JWTIssuer is the expected issuer of the tokens
+	JWTIssuer string
+	// JWTAudience is the expected audience of the tokens
+	JWTAudience string
+	// RequireForReads rejects anonymous reads too
+	RequireForReads bool
+	// PolicyFilePath is the path to the JSON file with the roles and their permissions (empty uses the default policy)
+	PolicyFilePath string
 }
 
 // NewServerChi is a function that returns a new instance of ServerChi
 func NewServerChi(cfg *ConfigServerChi) *ServerChi {
 	// default values
 	defaultConfig := &ConfigServerChi{
-		ServerAddress: ":8080",
+		ServerAddress:  ":8080",
+		PurgeInterval:  time.Hour,
+		IdempotencyTTL: 24 * time.Hour,
+		RateLimit: ConfigRateLimit{
+			Default: internal.RateLimit{Requests: 120, Period: time.Minute},
+			Routes: map[string]internal.RateLimit{
+				"POST /vehicles/batch":                         {Requests: 10, Period: time.Minute},
+				"GET /vehicles/average_speed/brand/{brand}":    {Requests: 30, Period: time.Minute},
+				"GET /vehicles/average_capacity/brand/{brand}": {Requests: 30, Period: time.Minute},
+				"GET /vehicles/dimensions":                     {Requests: 30, Period: time.Minute},
+				"GET /vehicles/stats":                          {Requests: 30, Period: time.Minute},
+				"GET /vehicles/stats/histogram":                {Requests: 30, Period: time.Minute},
+			},
+		},
 	}
 	if cfg != nil {
 		if cfg.ServerAddress != "" {
@@ -32,11 +102,48 @@ func NewServerChi(cfg *ConfigServerChi) *ServerChi {
 		if cfg.LoaderFilePath != "" {
 			defaultConfig.LoaderFilePath = cfg.LoaderFilePath
 		}
+		if cfg.AuditFilePath != "" {
+			defaultConfig.AuditFilePath = cfg.AuditFilePath
+		}
+		if cfg.AliasesFilePath != "" {
+			defaultConfig.AliasesFilePath = cfg.AliasesFilePath
+		}
+		if cfg.RegistrationFormatsFilePath != "" {
+			defaultConfig.RegistrationFormatsFilePath = cfg.RegistrationFormatsFilePath
+		}
+		if cfg.PurgeRetention > 0 {
+			defaultConfig.PurgeRetention = cfg.PurgeRetention
+		}
+		if cfg.PurgeInterval > 0 {
+			defaultConfig.PurgeInterval = cfg.PurgeInterval
+		}
+		if cfg.IdempotencyTTL > 0 {
+			defaultConfig.IdempotencyTTL = cfg.IdempotencyTTL
+		}
+		if cfg.RequestTimeout > 0 {
+			defaultConfig.RequestTimeout = cfg.RequestTimeout
+		}
+		defaultConfig.Auth = cfg.Auth
+		if cfg.RateLimit.Default.Requests > 0 {
+			defaultConfig.RateLimit.Default = cfg.RateLimit.Default
+		}
+		for route, limit := range cfg.RateLimit.Routes {
+			defaultConfig.RateLimit.Routes[route] = limit
+		}
 	}
 
 	return &ServerChi{
-		serverAddress:  defaultConfig.ServerAddress,
-		loaderFilePath: defaultConfig.LoaderFilePath,
+		serverAddress:               defaultConfig.ServerAddress,
+		loaderFilePath:              defaultConfig.LoaderFilePath,
+		auditFilePath:               defaultConfig.AuditFilePath,
+		aliasesFilePath:             defaultConfig.AliasesFilePath,
+		registrationFormatsFilePath: defaultConfig.RegistrationFormatsFilePath,
+		purgeRetention:              defaultConfig.PurgeRetention,
+		purgeInterval:               defaultConfig.PurgeInterval,
+		idempotencyTTL:              defaultConfig.IdempotencyTTL,
+		requestTimeout:              defaultConfig.RequestTimeout,
+		authConfig:                  defaultConfig.Auth,
+		rateLimit:                   defaultConfig.RateLimit,
 	}
 }
 
@@ -46,6 +153,24 @@ type ServerChi struct {
 	serverAddress string
 	// loaderFilePath is the path to the file that contains the vehicles
 	loaderFilePath string
+	// auditFilePath is the path to the file of the audit log
+	auditFilePath string
+	// aliasesFilePath is the path to the file of the alias tables
+	aliasesFilePath string
+	// registrationFormatsFilePath is the path to the file of the plate formats
+	registrationFormatsFilePath string
+	// purgeRetention is how long soft deleted vehicles stay in the trash
+	purgeRetention time.Duration
+	// purgeInterval is how often the purge job runs
+	purgeInterval time.Duration
+	// idempotencyTTL is how long the idempotency keys are remembered
+	idempotencyTTL time.Duration
+	// requestTimeout is the maximum duration of a request
+	requestTimeout time.Duration
+	// authConfig is the configuration of the authentication
+	authConfig ConfigAuth
+	// rateLimit is the configuration of the rate limiting
+	rateLimit ConfigRateLimit
 }
 
 // Run is a method that runs the application
@@ -57,37 +182,359 @@ func (a *ServerChi) Run() (err error) {
 	if err != nil {
 		return
 	}
+	// - normalizer: the loaded vehicles are normalized in id order, so the first display value of a key wins
+	var aliases map[string]map[string]string
+	if a.aliasesFilePath != "" {
+		aliases, err = normalize.LoadAliasesJSONFile(a.aliasesFilePath)
+		if err != nil {
+			return
+		}
+	}
+	nm := normalize.NewAliasTable(aliases)
+	ids := make([]int, 0, len(db))
+	for id := range db {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		db[id] = nm.Vehicle(db[id])
+	}
+	// - identifiers: plates and VINs are unique from now on, the duplicates already loaded are only reported
+	var formats map[string][]string
+	if a.registrationFormatsFilePath != "" {
+		formats, err = registration.LoadFormatsJSONFile(a.registrationFormatsFilePath)
+		if err != nil {
+			return
+		}
+	}
+	rv, err := registration.NewPlateFormats(formats)
+	if err != nil {
+		return
+	}
+	plates, vins := make(map[string][]int), make(map[string][]int)
+	for _, id := range ids {
+		if key := internal.NormalizePlate(db[id].Registration); key != "" {
+			plates[key] = append(plates[key], id)
+		}
+		if key := internal.NormalizeVIN(db[id].VIN); key != "" {
+			vins[key] = append(vins[key], id)
+		}
+	}
+	for plate, owners := range plates {
+		if len(owners) > 1 {
+			log.Printf("registration: %q shared by vehicles %v", plate, owners)
+		}
+	}
+	for number, owners := range vins {
+		if len(owners) > 1 {
+			log.Printf("vin: %q shared by vehicles %v", number, owners)
+		}
+	}
 	// - repository
 	rp := repository.NewVehicleMap(db)
+	rpCatalog := repository.NewCatalogMap(catalogs(db))
+	rpBrand := repository.NewBrandMap(registry(db))
+	rpOwner := repository.NewOwnerMap(nil)
+	rpDriver := repository.NewDriverMap(nil)
+	rpAssignment := repository.NewAssignmentMap()
+	rpMaintenance := repository.NewMaintenanceMap()
+	rpOdometer := repository.NewOdometerMap()
+	rpReservation := repository.NewReservationMap()
+	var rpAudit internal.AuditRepository = repository.NewAuditSlice(nil)
+	if a.auditFilePath != "" {
+		var rpAuditFile *repository.AuditJSONFile
+		rpAuditFile, err = repository.NewAuditJSONFile(a.auditFilePath)
+		if err != nil {
+			return
+		}
+		defer rpAuditFile.Close()
+		rpAudit = rpAuditFile
+	}
 	// - service
-	sv := service.NewVehicleDefault(rp)
+	rf := service.NewReferences()
+	sv := service.NewVehicleDefault(rp, rpAudit, nm, rpCatalog, rpBrand, rv, vin.NewDecoder(nil), rf, rpAssignment, rpReservation, rpMaintenance, rpOdometer)
+	svCatalog := service.NewCatalogDefault(rpCatalog, rp, nm, rf)
+	svBrand := service.NewBrandDefault(rpBrand, rp, sv, nm, rf)
+	svAudit := service.NewAuditDefault(rpAudit)
+	// the assignments refer to the owners and drivers apart from the vehicle writes
+	rfPeople := service.NewReferences()
+	svOwner := service.NewOwnerDefault(rpOwner, rpAssignment, rfPeople)
+	svDriver := service.NewDriverDefault(rpDriver, rpAssignment, rfPeople)
+	svAssignment := service.NewAssignmentDefault(rpAssignment, rp, rpOwner, rpDriver, rfPeople)
+	svMaintenance := service.NewMaintenanceDefault(rpMaintenance, rp, rpOdometer)
+	svOdometer := service.NewOdometerDefault(rpOdometer, rp, svMaintenance)
+	svReservation := service.NewReservationDefault(rpReservation, rp, sv)
+	// - jobs
+	if a.purgeRetention > 0 {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go a.purge(ctx, sv)
+	}
 	// - handler
-	hd := handler.NewVehicleDefault(sv)
+	hd := handler.NewVehicleDefault(sv, svAssignment)
+	hdAudit := handler.NewAuditDefault(svAudit)
+	hdCatalog := handler.NewCatalogDefault(svCatalog)
+	hdBrand := handler.NewBrandDefault(svBrand)
+	hdOwner := handler.NewOwnerDefault(svOwner)
+	hdDriver := handler.NewDriverDefault(svDriver)
+	hdAssignment := handler.NewAssignmentDefault(svAssignment)
+	hdMaintenance := handler.NewMaintenanceDefault(svMaintenance)
+	hdOdometer := handler.NewOdometerDefault(svOdometer)
+	hdReservation := handler.NewReservationDefault(svReservation)
+	// - authentication
+	au, err := a.authenticator()
+	if err != nil {
+		return
+	}
+	mwAuthn := appmiddleware.NewAuthentication(au, a.authConfig.RequireForReads)
+	// - authorization
+	var roles map[string][]string
+	if a.authConfig.PolicyFilePath != "" {
+		roles, err = auth.LoadPolicyJSONFile(a.authConfig.PolicyFilePath)
+		if err != nil {
+			return
+		}
+	}
+	mwAuthz := appmiddleware.NewAuthorization(auth.NewRBAC(roles))
+	read := mwAuthz.Require(internal.PermissionVehiclesRead)
+	readMaintenance := mwAuthz.Require(internal.PermissionMaintenanceRead)
+	writeMaintenance := mwAuthz.Require(internal.PermissionMaintenanceWrite)
+	readReservations := mwAuthz.Require(internal.PermissionReservationsRead)
+	writeReservations := mwAuthz.Require(internal.PermissionReservationsWrite)
+	// - rate limit
+	mwRate := appmiddleware.NewRateLimit(a.rateLimit.Default, a.rateLimit.Routes)
+	// - idempotency
+	mwIdempotency := appmiddleware.NewIdempotency(repository.NewIdempotencyMap(), a.idempotencyTTL)
 	// router
 	rt := chi.NewRouter()
 	// - middlewares
+	rt.Use(appmiddleware.RequestID)
 	rt.Use(middleware.Logger)
 	rt.Use(middleware.Recoverer)
+	if a.requestTimeout > 0 {
+		rt.Use(timeout(a.requestTimeout))
+	}
+	rt.Use(mwAuthn.Handler)
+	rt.Use(appmiddleware.Units)
 	// - endpoints
 	rt.Route("/vehicles", func(rt chi.Router) {
+		rt.Use(appmiddleware.IncludeDeleted)
 		// - GET /vehicles
-		rt.Get("/", hd.GetAll())
-		rt.Post("/", hd.CreateVehicle())
-		rt.Get("/color/{color}/year/{year}", hd.FindByColorAndYear())
-		rt.Get("/brand/{brand}/between/{start_year}/{end_year}", hd.FindByBrandAndYearRate())
-		rt.Get("/average_speed/brand/{brand}", hd.FindVelocityAverageByBrand())
-		rt.Post("/batch", hd.CreateVehicles())
-		rt.Put("/{id}/update_speed", hd.UpdateMaxSpeed())
-		rt.Get("/fuel_type/{type}", hd.FindVehiclesByFuelType())
-		rt.Delete("/{id}", hd.Delete())
-		rt.Get("/transmission/{type}", hd.FindVehiculesByTransmissionType())
-		rt.Put("/{id}/update_fuel", hd.UpdateFuelType())
-		rt.Get("/average_capacity/brand/{brand}", hd.AverageBrandCapacity())
-		rt.Get("/dimensions", hd.FindVehiclesByDimension())
-		rt.Get("/weight", hd.FindVehiclesByWeightRate())
+		rt.With(read, mwRate.Handler).Get("/", hd.GetAll())
+		rt.With(mwAuthz.Require(internal.PermissionVehiclesCreate), mwRate.Handler, mwIdempotency.Handler).Post("/", hd.CreateVehicle())
+		rt.With(read, mwRate.Handler).Get("/color/{color}/year/{year}", hd.FindByColorAndYear())
+		rt.With(read, mwRate.Handler).Get("/brand/{brand}/between/{start_year}/{end_year}", hd.FindByBrandAndYearRate())
+		rt.With(read, mwRate.Handler).Get("/average_speed/brand/{brand}", hd.FindVelocityAverageByBrand())
+		rt.With(mwAuthz.Require(internal.PermissionVehiclesBatch), mwRate.Handler, mwIdempotency.Handler).Post("/batch", hd.CreateVehicles())
+		rt.With(mwAuthz.Require(internal.PermissionVehiclesUpdateSpeed), mwRate.Handler).Put("/{id}/update_speed", hd.UpdateMaxSpeed())
+		rt.With(read, mwRate.Handler).Get("/fuel_type/{type}", hd.FindVehiclesByFuelType())
+		rt.With(mwAuthz.Require(internal.PermissionVehiclesDelete), mwRate.Handler).Delete("/{id}", hd.Delete())
+		rt.With(read, mwRate.Handler).Get("/transmission/{type}", hd.FindVehiculesByTransmissionType())
+		rt.With(mwAuthz.Require(internal.PermissionVehiclesUpdateFuel), mwRate.Handler).Put("/{id}/update_fuel", hd.UpdateFuelType())
+		rt.With(read, mwRate.Handler).Get("/average_capacity/brand/{brand}", hd.AverageBrandCapacity())
+		rt.With(read, mwRate.Handler).Get("/dimensions", hd.FindVehiclesByDimension())
+		rt.With(read, mwRate.Handler).Get("/weight", hd.FindVehiclesByWeightRate())
+		rt.With(mwAuthz.Require(internal.PermissionVehiclesBulkUpdate), mwRate.Handler).Post("/bulk-update", hd.BulkUpdate())
+		rt.With(mwAuthz.Require(internal.PermissionVehiclesBulkDelete), mwRate.Handler).Post("/bulk-delete", hd.BulkDelete())
+		rt.With(read, mwRate.Handler).Get("/stats", hd.Stats())
+		rt.With(read, mwRate.Handler).Get("/stats/histogram", hd.Histogram())
+		rt.With(read, mwRate.Handler).Get("/trash", hd.Trash())
+		rt.With(read, mwRate.Handler).Get("/search", hd.Search())
+		rt.With(readReservations, mwRate.Handler).Get("/available", hdReservation.Available())
+		rt.With(read, mwRate.Handler).Get("/registration/{plate}", hd.FindByRegistration())
+		rt.With(read, mwRate.Handler).Get("/vin/{vin}", hd.FindByVIN())
+		rt.With(mwAuthz.Require(internal.PermissionVehiclesDelete), mwRate.Handler).Post("/{id}/restore", hd.Restore())
+		rt.With(read, mwRate.Handler).Get("/{id}", hd.GetByID())
+		rt.With(read, mwRate.Handler).Get("/{id}/history", hd.History())
+		rt.With(mwAuthz.Require(internal.PermissionVehiclesTransition), mwRate.Handler).Post("/{id}/transitions", hd.Transition())
+		rt.With(mwAuthz.Require(internal.PermissionAuditRead), mwRate.Handler).Get("/{id}/audit", hdAudit.GetByVehicle())
+		rt.With(mwAuthz.Require(internal.PermissionPeopleRead), mwRate.Handler).Get("/{id}/assignments", hdAssignment.GetByVehicle())
+		rt.With(mwAuthz.Require(internal.PermissionAssignmentsWrite), mwRate.Handler, mwIdempotency.Handler).Post("/{id}/assignments", hdAssignment.Create())
+		rt.With(mwAuthz.Require(internal.PermissionAssignmentsWrite), mwRate.Handler).Post("/{id}/assignments/{assignment_id}/end", hdAssignment.End())
+		rt.With(readMaintenance, mwRate.Handler).Get("/{id}/maintenance", hdMaintenance.GetByVehicle())
+		rt.With(writeMaintenance, mwRate.Handler, mwIdempotency.Handler).Post("/{id}/maintenance/records", hdMaintenance.CreateRecord())
+		rt.With(writeMaintenance, mwRate.Handler).Delete("/{id}/maintenance/records/{record_id}", hdMaintenance.DeleteRecord())
+		rt.With(writeMaintenance, mwRate.Handler, mwIdempotency.Handler).Post("/{id}/maintenance/schedules", hdMaintenance.CreateSchedule())
+		rt.With(writeMaintenance, mwRate.Handler).Delete("/{id}/maintenance/schedules/{schedule_id}", hdMaintenance.DeleteSchedule())
+		rt.With(readMaintenance, mwRate.Handler).Get("/{id}/odometer", hdOdometer.GetByVehicle())
+		rt.With(writeMaintenance, mwRate.Handler, mwIdempotency.Handler).Post("/{id}/odometer", hdOdometer.Create())
+		rt.With(readMaintenance, mwRate.Handler).Get("/{id}/usage", hdOdometer.Usage())
+		rt.With(readReservations, mwRate.Handler).Get("/{id}/reservations", hdReservation.GetByVehicle())
+		rt.With(writeReservations, mwRate.Handler, mwIdempotency.Handler).Post("/{id}/reservations", hdReservation.Create())
+		rt.With(writeReservations, mwRate.Handler).Post("/{id}/reservations/{reservation_id}/cancel", hdReservation.Cancel())
+		rt.With(readReservations, mwRate.Handler).Get("/{id}/reservations.ics", hdReservation.Calendar())
+	})
+	rt.Route("/maintenance", func(rt chi.Router) {
+		// - GET /maintenance/due
+		rt.With(readMaintenance, mwRate.Handler).Get("/due", hdMaintenance.GetDue())
+	})
+	rt.Route("/vin", func(rt chi.Router) {
+		// - GET /vin/decode/{vin}
+		rt.With(read, mwRate.Handler).Get("/decode/{vin}", hd.DecodeVINByPath())
+		// - POST /vin/decode
+		rt.With(read, mwRate.Handler).Post("/decode", hd.DecodeVIN())
+	})
+	rt.Route("/audit", func(rt chi.Router) {
+		// - GET /audit
+		rt.With(mwAuthz.Require(internal.PermissionAuditRead), mwRate.Handler).Get("/", hdAudit.GetAll())
+	})
+	rt.Route("/catalogs/{catalog}", func(rt chi.Router) {
+		// - /catalogs/{fuel_types|transmissions|colors}
+		readCatalogs := mwAuthz.Require(internal.PermissionCatalogsRead)
+		writeCatalogs := mwAuthz.Require(internal.PermissionCatalogsWrite)
+		rt.With(readCatalogs, mwRate.Handler).Get("/", hdCatalog.GetAll())
+		rt.With(writeCatalogs, mwRate.Handler).Post("/", hdCatalog.Create())
+		rt.With(readCatalogs, mwRate.Handler).Get("/{value}", hdCatalog.GetByValue())
+		rt.With(writeCatalogs, mwRate.Handler).Put("/{value}", hdCatalog.Update())
+		rt.With(writeCatalogs, mwRate.Handler).Delete("/{value}", hdCatalog.Delete())
+	})
+	rt.Route("/brands", func(rt chi.Router) {
+		// - /brands and /brands/{brand}/models
+		readBrands := mwAuthz.Require(internal.PermissionBrandsRead)
+		writeBrands := mwAuthz.Require(internal.PermissionBrandsWrite)
+		rt.With(readBrands, mwRate.Handler).Get("/", hdBrand.GetAll())
+		rt.With(writeBrands, mwRate.Handler).Post("/", hdBrand.Create())
+		rt.With(readBrands, mwRate.Handler).Get("/{brand}", hdBrand.GetByName())
+		rt.With(writeBrands, mwRate.Handler).Put("/{brand}", hdBrand.Update())
+		rt.With(writeBrands, mwRate.Handler).Delete("/{brand}", hdBrand.Delete())
+		rt.With(readBrands, mwRate.Handler).Get("/{brand}/models", hdBrand.GetModels())
+		rt.With(writeBrands, mwRate.Handler).Post("/{brand}/models", hdBrand.CreateModel())
+		rt.With(readBrands, mwRate.Handler).Get("/{brand}/models/{model}", hdBrand.GetModel())
+		rt.With(writeBrands, mwRate.Handler).Put("/{brand}/models/{model}", hdBrand.UpdateModel())
+		rt.With(writeBrands, mwRate.Handler).Delete("/{brand}/models/{model}", hdBrand.DeleteModel())
+		rt.With(read, mwRate.Handler).Get("/{brand}/models/{model}/vehicles", hdBrand.GetModelVehicles())
+		rt.With(mwAuthz.Require(internal.PermissionVehiclesCreate), mwRate.Handler, mwIdempotency.Handler).Post("/{brand}/models/{model}/vehicles", hdBrand.CreateVehicle())
+	})
+	readPeople := mwAuthz.Require(internal.PermissionPeopleRead)
+	writePeople := mwAuthz.Require(internal.PermissionPeopleWrite)
+	rt.Route("/owners", func(rt chi.Router) {
+		// - /owners, their vehicles are listed with GET /vehicles?owner_id=
+		rt.With(readPeople, mwRate.Handler).Get("/", hdOwner.GetAll())
+		rt.With(writePeople, mwRate.Handler, mwIdempotency.Handler).Post("/", hdOwner.Create())
+		rt.With(readPeople, mwRate.Handler).Get("/{id}", hdOwner.GetByID())
+		rt.With(writePeople, mwRate.Handler).Put("/{id}", hdOwner.Update())
+		rt.With(writePeople, mwRate.Handler).Delete("/{id}", hdOwner.Delete())
+		rt.With(readPeople, mwRate.Handler).Get("/{id}/assignments", hdAssignment.GetByPerson(internal.AssignmentOwner))
+	})
+	rt.Route("/drivers", func(rt chi.Router) {
+		// - /drivers, their vehicles are listed with GET /vehicles?driver_id=
+		rt.With(readPeople, mwRate.Handler).Get("/", hdDriver.GetAll())
+		rt.With(writePeople, mwRate.Handler, mwIdempotency.Handler).Post("/", hdDriver.Create())
+		rt.With(readPeople, mwRate.Handler).Get("/{id}", hdDriver.GetByID())
+		rt.With(writePeople, mwRate.Handler).Put("/{id}", hdDriver.Update())
+		rt.With(writePeople, mwRate.Handler).Delete("/{id}", hdDriver.Delete())
+		rt.With(readPeople, mwRate.Handler).Get("/{id}/assignments", hdAssignment.GetByPerson(internal.AssignmentDriver))
 	})
 
 	// run server
 	err = http.ListenAndServe(a.serverAddress, rt)
 	return
 }
+
+// authenticator is a method that builds the authenticator from the configuration
+func (a *ServerChi) authenticator() (au internal.Authenticator, err error) {
+	var authenticators []internal.Authenticator
+
+	// - api keys
+	if a.authConfig.APIKeysFilePath != "" {
+		var keys map[string]internal.Principal
+		keys, err = auth.LoadAPIKeysJSONFile(a.authConfig.APIKeysFilePath)
+		if err != nil {
+			return
+		}
+		authenticators = append(authenticators, auth.NewAPIKeyMap(keys))
+	}
+
+	// - jwt
+	keys := auth.NewJWTKeys()
+	if a.authConfig.JWTHMACSecretFilePath != "" {
+		if err = keys.LoadHMACSecretFile(a.authConfig.JWTHMACSecretFilePath); err != nil {
+			return
+		}
+	}
+	if a.authConfig.JWTRSAPublicKeyFilePath != "" {
+		if err = keys.LoadRSAPublicKeyFile(a.authConfig.JWTRSAPublicKeyFilePath); err != nil {
+			return
+		}
+	}
+	if a.authConfig.JWKSFilePath != "" {
+		if err = keys.LoadJWKSFile(a.authConfig.JWKSFilePath); err != nil {
+			return
+		}
+	}
+	if !keys.Empty() {
+		authenticators = append(authenticators, auth.NewJWTBearer(auth.ConfigJWTBearer{
+			Keys:     keys,
+			Issuer:   a.authConfig.JWTIssuer,
+			Audience: a.authConfig.JWTAudience,
+			Leeway:   30 * time.Second,
+		}))
+	}
+
+	au = auth.NewChain(authenticators...)
+	return
+}
+
+// catalogs is a function that builds the initial catalogs from the standard values and the ones of the loaded vehicles
+func catalogs(db map[int]internal.Vehicle) map[internal.CatalogKind][]internal.CatalogEntry {
+	entries := map[internal.CatalogKind][]internal.CatalogEntry{
+		internal.CatalogFuelTypes: {
+			{Value: "gasoline"}, {Value: "diesel"}, {Value: "biodiesel"}, {Value: "electric"}, {Value: "hybrid"},
+		},
+		internal.CatalogTransmissions: {
+			{Value: "automatic"}, {Value: "manual"}, {Value: "semi-automatic"},
+		},
+	}
+	for _, v := range db {
+		entries[internal.CatalogFuelTypes] = append(entries[internal.CatalogFuelTypes], internal.CatalogEntry{Value: v.FuelType})
+		entries[internal.CatalogTransmissions] = append(entries[internal.CatalogTransmissions], internal.CatalogEntry{Value: v.Transmission})
+		entries[internal.CatalogColors] = append(entries[internal.CatalogColors], internal.CatalogEntry{Value: v.Color})
+	}
+	return entries
+}
+
+// registry is a function that builds the initial brand and model registry from the loaded vehicles,
+// without default specs
+func registry(db map[int]internal.Vehicle) (brands []internal.Brand, models []internal.VehicleModel) {
+	for _, v := range db {
+		brands = append(brands, internal.Brand{Name: v.Brand})
+		models = append(models, internal.VehicleModel{Brand: v.Brand, Name: v.Model})
+	}
+	return
+}
+
+// purge is a method that periodically hard deletes the vehicles that stayed in the trash longer than the retention
+func (a *ServerChi) purge(ctx context.Context, sv internal.VehicleService) {
+	// the changes are recorded in the audit log on behalf of the system
+	ctx = internal.ContextWithPrincipal(ctx, internal.Principal{Subject: "system"})
+
+	ticker := time.NewTicker(a.purgeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := sv.PurgeDeleted(ctx, a.purgeRetention)
+			switch {
+			case err != nil:
+				log.Printf("purge: %d vehicles purged before failing: %v", n, err)
+			case n > 0:
+				log.Printf("purge: %d vehicles purged", n)
+			}
+		}
+	}
+}
+
+// timeout is a middleware that sets a deadline on the request context.
+// Handlers are in charge of answering 504 when the deadline is exceeded.
+func timeout(d time.Duration) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrAssignmentNotFound = errors.New("assignment not found")
+	ErrAssignmentOverlap  = errors.New("assignment overlaps another one of the vehicle")
+	ErrInvalidAssignment  = errors.New("invalid assignment")
+)
+
+// AssignmentRole is the role a person plays for a vehicle
+type AssignmentRole string
+
+const (
+	// AssignmentOwner is the role of the owner of a vehicle
+	AssignmentOwner AssignmentRole = "owner"
+	// AssignmentDriver is the role of the driver of a vehicle
+	AssignmentDriver AssignmentRole = "driver"
+)
+
+// Assignment is a struct that represents a person in charge of a vehicle over a period of time.
+// A vehicle has at most one person of each role at any moment
+type Assignment struct {
+	// Id is the unique identifier of the assignment, given by the repository
+	Id int
+	// Role is the role of the person
+	Role AssignmentRole
+	// VehicleID is the id of the vehicle
+	VehicleID int
+	// PersonID is the id of the owner or the driver, depending on the role
+	PersonID int
+	// From is the moment the assignment takes effect
+	From time.Time
+	// To is the moment the assignment ends, excluded (nil while open-ended)
+	To *time.Time
+}
+
+// ActiveAt is a method that checks if the assignment is in effect at the given moment
+func (a Assignment) ActiveAt(t time.Time) bool {
+	return !t.Before(a.From) && (a.To == nil || t.Before(*a.To))
+}
+
+// Overlaps is a method that checks if two assignments are in effect at some common moment
+func (a Assignment) Overlaps(b Assignment) bool {
+	return (a.To == nil || b.From.Before(*a.To)) && (b.To == nil || a.From.Before(*b.To))
+}
+
+// AssignmentFilter is a struct that represents criteria over the people in charge of vehicles at a moment.
+// Nil pointers mean the criteria is not applied
+type AssignmentFilter struct {
+	// OwnerID is the owner of the vehicles
+	OwnerID *int
+	// DriverID is the driver of the vehicles
+	DriverID *int
+	// Unassigned keeps only the vehicles without driver
+	Unassigned bool
+	// At is the moment the assignments are evaluated (zero means now)
+	At time.Time
+}
+
+// Empty is a method that returns true when the filter has no criteria
+func (f AssignmentFilter) Empty() bool {
+	return f.OwnerID == nil && f.DriverID == nil && !f.Unassigned
+}
+
+// AssignmentRepository is an interface that represents a repository of assignments
+type AssignmentRepository interface {
+	// FindByVehicle returns the assignments of a vehicle with the given role (all of them when empty), oldest first
+	FindByVehicle(ctx context.Context, vehicleID int, role AssignmentRole) (a []Assignment, err error)
+	// FindByPerson returns the assignments of an owner or a driver, oldest first
+	FindByPerson(ctx context.Context, role AssignmentRole, personID int) (a []Assignment, err error)
+	// FindActive returns the assignments of a role in effect at a moment by vehicle id
+	FindActive(ctx context.Context, role AssignmentRole, at time.Time) (a map[int]Assignment, err error)
+	// Create adds an assignment with the next id. The open-ended assignment it takes over is ended
+	// when the new one starts, any other overlap is rejected
+	Create(ctx context.Context, assignment Assignment) (a Assignment, ended *Assignment, err error)
+	// End sets the end of an assignment
+	End(ctx context.Context, id int, at time.Time) (a Assignment, err error)
+	VehicleDependentRepository
+}
+
+// AssignmentService is an interface that represents a service of assignments
+type AssignmentService interface {
+	// Assign puts a person in charge of a vehicle, taking over the current one of the same role
+	Assign(ctx context.Context, assignment Assignment) (a Assignment, ended *Assignment, err error)
+	// End ends an assignment of a vehicle at the given moment (zero means now)
+	End(ctx context.Context, vehicleID int, id int, at time.Time) (a Assignment, err error)
+	// FindByVehicle returns the history of assignments of a vehicle with the given role (all of them when empty)
+	FindByVehicle(ctx context.Context, vehicleID int, role AssignmentRole) (a []Assignment, err error)
+	// FindByPerson returns the history of assignments of an owner or a driver
+	FindByPerson(ctx context.Context, role AssignmentRole, personID int) (a []Assignment, err error)
+	// FilterVehicles returns the vehicles that meet the criteria of the filter
+	FilterVehicles(ctx context.Context, v map[int]Vehicle, filter AssignmentFilter) (r map[int]Vehicle, err error)
+}
@@ -0,0 +1,64 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrAuditFailed = errors.New("the change could not be recorded in the audit log")
+)
+
+// Audit actions
+const (
+	AuditActionCreate         = "create"
+	AuditActionUpdateMaxSpeed = "update_max_speed"
+	AuditActionUpdateFuelType = "update_fuel_type"
+	AuditActionBulkUpdate     = "bulk_update"
+	AuditActionUpsert         = "upsert"
+	AuditActionDelete         = "delete"
+	AuditActionRestore        = "restore"
+	AuditActionPurge          = "purge"
+	AuditActionTransition     = "transition"
+)
+
+// AuditEntry is a struct that represents a mutation of a vehicle
+type AuditEntry struct {
+	// Id is the sequence number of the entry
+	Id int
+	// VehicleID is the identifier of the vehicle changed
+	VehicleID int
+	// Action is the mutation performed
+	Action string
+	// Actor is the subject of the caller ("anonymous" when unauthenticated)
+	Actor string
+	// RequestID is the identifier of the request that made the change
+	RequestID string
+	// Timestamp is the moment of the change
+	Timestamp time.Time
+	// Reason is the reason given for the change (empty when none is required)
+	Reason string
+	// Before is the vehicle before the change (nil on create)
+	Before *Vehicle
+	// After is the vehicle after the change (nil on delete)
+	After *Vehicle
+}
+
+// AuditRepository is an interface that represents an append-only audit log
+type AuditRepository interface {
+	// Append adds entries to the log, setting their Id
+	Append(ctx context.Context, entries ...AuditEntry) (err error)
+	// FindAll returns the entries between from and to (zero values mean unbounded)
+	FindAll(ctx context.Context, from, to time.Time) (e []AuditEntry, err error)
+	// FindByVehicle returns the entries of a vehicle between from and to (zero values mean unbounded)
+	FindByVehicle(ctx context.Context, vehicleID int, from, to time.Time) (e []AuditEntry, err error)
+}
+
+// AuditService is an interface that represents the service to query the audit log
+type AuditService interface {
+	// FindAll returns the entries between from and to
+	FindAll(ctx context.Context, from, to time.Time) (e []AuditEntry, err error)
+	// FindByVehicle returns the entries of a vehicle between from and to
+	FindByVehicle(ctx context.Context, vehicleID int, from, to time.Time) (e []AuditEntry, err error)
+}
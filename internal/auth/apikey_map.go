@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"app/internal"
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"os"
+)
+
+// HeaderAPIKey is the header where the api key is expected
+const HeaderAPIKey = "X-API-Key"
+
+// APIKeyJSON is a struct that represents an api key in JSON format
+type APIKeyJSON struct {
+	Key     string   `json:"key"`
+	Subject string   `json:"subject"`
+	Roles   []string `json:"roles"`
+}
+
+// LoadAPIKeysJSONFile is a function that loads the api keys from a JSON file
+func LoadAPIKeysJSONFile(path string) (keys map[string]internal.Principal, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	var keysJSON []APIKeyJSON
+	if err = json.NewDecoder(file).Decode(&keysJSON); err != nil {
+		return
+	}
+
+	keys = make(map[string]internal.Principal)
+	for _, k := range keysJSON {
+		if k.Key == "" {
+			continue
+		}
+		keys[k.Key] = internal.Principal{
+			Subject: k.Subject,
+			Roles:   k.Roles,
+			Method:  "api_key",
+		}
+	}
+	return
+}
+
+// NewAPIKeyMap is a function that returns a new instance of APIKeyMap
+func NewAPIKeyMap(keys map[string]internal.Principal) *APIKeyMap {
+	// default keys
+	defaultKeys := make(map[string]internal.Principal)
+	if keys != nil {
+		defaultKeys = keys
+	}
+	return &APIKeyMap{keys: defaultKeys}
+}
+
+// APIKeyMap is a struct that authenticates requests with static api keys
+type APIKeyMap struct {
+	// keys is a map of api keys and their principal
+	keys map[string]internal.Principal
+}
+
+// Authenticate is a method that authenticates the request with the X-API-Key header
+func (a *APIKeyMap) Authenticate(r *http.Request) (p internal.Principal, err error) {
+	key := r.Header.Get(HeaderAPIKey)
+	if key == "" {
+		err = internal.ErrUnauthenticated
+		return
+	}
+
+	// constant time comparison against every key
+	found := false
+	for k, v := range a.keys {
+		if subtle.ConstantTimeCompare([]byte(k), []byte(key)) == 1 {
+			p = v
+			found = true
+		}
+	}
+	if !found {
+		err = internal.ErrInvalidCredential
+		return
+	}
+	p.Method = "api_key"
+	return
+}
@@ -0,0 +1,30 @@
+package auth
+
+import (
+	"app/internal"
+	"errors"
+	"net/http"
+)
+
+// NewChain is a function that returns a new instance of Chain
+func NewChain(authenticators ...internal.Authenticator) *Chain {
+	return &Chain{authenticators: authenticators}
+}
+
+// Chain is a struct that tries several authenticators in order
+type Chain struct {
+	// authenticators are the authenticators to try
+	authenticators []internal.Authenticator
+}
+
+// Authenticate is a method that returns the principal of the first authenticator that finds credentials
+func (c *Chain) Authenticate(r *http.Request) (p internal.Principal, err error) {
+	for _, a := range c.authenticators {
+		p, err = a.Authenticate(r)
+		if !errors.Is(err, internal.ErrUnauthenticated) {
+			return
+		}
+	}
+	err = internal.ErrUnauthenticated
+	return
+}
@@ -0,0 +1,201 @@
+package auth
+
+import (
+	"app/internal"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ConfigJWTBearer is a struct that represents the configuration for JWTBearer
+type ConfigJWTBearer struct {
+	// Keys are the keys used to verify the signatures
+	Keys *JWTKeys
+	// Issuer is the expected "iss" claim (empty means not checked)
+	Issuer string
+	// Audience is the expected "aud" claim (empty means not checked)
+	Audience string
+	// Leeway is the clock skew tolerated when checking "exp" and "nbf"
+	Leeway time.Duration
+}
+
+// NewJWTBearer is a function that returns a new instance of JWTBearer
+func NewJWTBearer(cfg ConfigJWTBearer) *JWTBearer {
+	keys := cfg.Keys
+	if keys == nil {
+		keys = NewJWTKeys()
+	}
+	return &JWTBearer{
+		keys:     keys,
+		issuer:   cfg.Issuer,
+		audience: cfg.Audience,
+		leeway:   cfg.Leeway,
+		now:      time.Now,
+	}
+}
+
+// JWTBearer is a struct that authenticates requests with HS256/RS256 bearer tokens
+type JWTBearer struct {
+	// keys are the keys used to verify the signatures
+	keys *JWTKeys
+	// issuer is the expected issuer
+	issuer string
+	// audience is the expected audience
+	audience string
+	// leeway is the clock skew tolerated
+	leeway time.Duration
+	// now returns the current time
+	now func() time.Time
+}
+
+// jwtHeader is the header of a token
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// jwtClaims are the claims of a token that are taken into account
+type jwtClaims struct {
+	Subject   string          `json:"sub"`
+	Issuer    string          `json:"iss"`
+	Audience  json.RawMessage `json:"aud"`
+	ExpiresAt *float64        `json:"exp"`
+	NotBefore *float64        `json:"nbf"`
+	Roles     []string        `json:"roles"`
+}
+
+// Authenticate is a method that authenticates the request with the Authorization: Bearer header
+func (a *JWTBearer) Authenticate(r *http.Request) (p internal.Principal, err error) {
+	authorization := r.Header.Get("Authorization")
+	scheme, token, found := strings.Cut(authorization, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		err = internal.ErrUnauthenticated
+		return
+	}
+
+	claims, err := a.verify(strings.TrimSpace(token))
+	if err != nil {
+		return
+	}
+
+	p = internal.Principal{
+		Subject: claims.Subject,
+		Roles:   claims.Roles,
+		Method:  "jwt",
+	}
+	return
+}
+
+// verify is a method that checks the signature and the registered claims of a token
+func (a *JWTBearer) verify(token string) (claims jwtClaims, err error) {
+	err = internal.ErrInvalidCredential
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return
+	}
+
+	// header
+	headerBytes, e := base64.RawURLEncoding.DecodeString(parts[0])
+	if e != nil {
+		return
+	}
+	var header jwtHeader
+	if e := json.Unmarshal(headerBytes, &header); e != nil {
+		return
+	}
+
+	// signature
+	signature, e := base64.RawURLEncoding.DecodeString(parts[2])
+	if e != nil {
+		return
+	}
+	signed := []byte(parts[0] + "." + parts[1])
+	digest := sha256.Sum256(signed)
+
+	switch header.Alg {
+	case "HS256":
+		secret, ok := a.keys.hmac[header.Kid]
+		if !ok {
+			if secret, ok = a.keys.hmac[""]; !ok {
+				return
+			}
+		}
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(signed)
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return
+		}
+	case "RS256":
+		key, ok := a.keys.rsa[header.Kid]
+		if !ok {
+			if key, ok = a.keys.rsa[""]; !ok {
+				return
+			}
+		}
+		if e := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); e != nil {
+			return
+		}
+	default:
+		// "none" and any other algorithm are rejected
+		return
+	}
+
+	// claims
+	payload, e := base64.RawURLEncoding.DecodeString(parts[1])
+	if e != nil {
+		return
+	}
+	if e := json.Unmarshal(payload, &claims); e != nil {
+		return
+	}
+
+	// a token without expiration would be valid forever once leaked
+	now := a.now()
+	if claims.ExpiresAt == nil || now.After(unixTime(*claims.ExpiresAt).Add(a.leeway)) {
+		return
+	}
+	if claims.NotBefore != nil && now.Add(a.leeway).Before(unixTime(*claims.NotBefore)) {
+		return
+	}
+	if a.issuer != "" && claims.Issuer != a.issuer {
+		return
+	}
+	if a.audience != "" && !audienceContains(claims.Audience, a.audience) {
+		return
+	}
+	if claims.Subject == "" {
+		return
+	}
+
+	err = nil
+	return
+}
+
+// unixTime converts a NumericDate to a time
+func unixTime(seconds float64) time.Time {
+	return time.Unix(int64(seconds), 0)
+}
+
+// audienceContains checks the "aud" claim, which may be a string or an array of strings
+func audienceContains(raw json.RawMessage, audience string) bool {
+	var single string
+	if err := json.Unmarshal(raw, &single); err == nil {
+		return single == audience
+	}
+	var many []string
+	if err := json.Unmarshal(raw, &many); err == nil {
+		for _, aud := range many {
+			if aud == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
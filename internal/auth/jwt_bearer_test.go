@@ -0,0 +1,176 @@
+package auth
+
+import (
+	"app/internal"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// b64 encodes in base64url without padding
+func b64(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+
+// token returns a token with the given header and claims, signed by sign
+func token(t *testing.T, header, claims map[string]any, sign func(signed []byte) []byte) string {
+	t.Helper()
+	h, _ := json.Marshal(header)
+	c, _ := json.Marshal(claims)
+	signed := b64(h) + "." + b64(c)
+	return signed + "." + b64(sign([]byte(signed)))
+}
+
+// hs256 returns a HS256 signer with the secret
+func hs256(secret []byte) func([]byte) []byte {
+	return func(signed []byte) []byte {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(signed)
+		return mac.Sum(nil)
+	}
+}
+
+// rs256 returns a RS256 signer with the key
+func rs256(key *rsa.PrivateKey) func([]byte) []byte {
+	return func(signed []byte) []byte {
+		digest := sha256.Sum256(signed)
+		signature, _ := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+		return signature
+	}
+}
+
+func TestJWTBearer_Authenticate(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	secret := []byte("hs-secret")
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwksKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the keys are loaded from files, as configured
+	dir := t.TempDir()
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: x509.MarshalPKCS1PublicKey(&rsaKey.PublicKey)})
+	jwks, _ := json.Marshal(map[string]any{"keys": []JWKJSON{
+		{Kty: "RSA", Kid: "rsa-1", N: b64(jwksKey.N.Bytes()), E: b64(big.NewInt(int64(jwksKey.E)).Bytes())},
+		{Kty: "oct", Kid: "oct-1", K: b64([]byte("oct-secret"))},
+	}})
+	files := map[string][]byte{"secret": secret, "public.pem": pemBytes, "jwks.json": jwks}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	keys := NewJWTKeys()
+	for _, load := range []error{
+		keys.LoadHMACSecretFile(filepath.Join(dir, "secret")),
+		keys.LoadRSAPublicKeyFile(filepath.Join(dir, "public.pem")),
+		keys.LoadJWKSFile(filepath.Join(dir, "jwks.json")),
+	} {
+		if load != nil {
+			t.Fatal(load)
+		}
+	}
+	au := NewJWTBearer(ConfigJWTBearer{Keys: keys, Issuer: "fleet", Audience: "vehicles", Leeway: time.Minute})
+	au.now = func() time.Time { return now }
+
+	claims := func(change func(c map[string]any)) map[string]any {
+		c := map[string]any{"sub": "alice", "iss": "fleet", "aud": "vehicles", "exp": now.Add(time.Hour).Unix(), "roles": []string{"admin"}}
+		if change != nil {
+			change(c)
+		}
+		return c
+	}
+	hs := map[string]any{"alg": "HS256"}
+	rs := map[string]any{"alg": "RS256"}
+
+	cases := []struct {
+		name    string
+		header  string
+		wantErr error
+	}{
+		{"HS256", "Bearer " + token(t, hs, claims(nil), hs256(secret)), nil},
+		{"RS256", "Bearer " + token(t, rs, claims(nil), rs256(rsaKey)), nil},
+		{"RS256 from the JWKS", "Bearer " + token(t, map[string]any{"alg": "RS256", "kid": "rsa-1"}, claims(nil), rs256(jwksKey)), nil},
+		{"HS256 from the JWKS", "Bearer " + token(t, map[string]any{"alg": "HS256", "kid": "oct-1"}, claims(nil), hs256([]byte("oct-secret"))), nil},
+		{"audience in a list", "Bearer " + token(t, hs, claims(func(c map[string]any) { c["aud"] = []string{"other", "vehicles"} }), hs256(secret)), nil},
+		{"expired within the leeway", "Bearer " + token(t, hs, claims(func(c map[string]any) { c["exp"] = now.Add(-30 * time.Second).Unix() }), hs256(secret)), nil},
+		{"no credentials", "", internal.ErrUnauthenticated},
+		{"other scheme", "Basic YWxpY2U6c2VjcmV0", internal.ErrUnauthenticated},
+		{"malformed", "Bearer abc.def", internal.ErrInvalidCredential},
+		{"wrong secret", "Bearer " + token(t, hs, claims(nil), hs256([]byte("other"))), internal.ErrInvalidCredential},
+		{"wrong key", "Bearer " + token(t, rs, claims(nil), rs256(jwksKey)), internal.ErrInvalidCredential},
+		{"alg none", "Bearer " + token(t, map[string]any{"alg": "none"}, claims(nil), func([]byte) []byte { return nil }), internal.ErrInvalidCredential},
+		// the public key is known to anyone, it must not be accepted as a HMAC secret
+		{"alg confusion with the PEM", "Bearer " + token(t, map[string]any{"alg": "HS256", "kid": "missing"}, claims(nil), hs256(pemBytes)), internal.ErrInvalidCredential},
+		{"alg confusion with the JWK", "Bearer " + token(t, map[string]any{"alg": "HS256", "kid": "rsa-1"}, claims(nil), hs256(jwksKey.N.Bytes())), internal.ErrInvalidCredential},
+		{"expired", "Bearer " + token(t, hs, claims(func(c map[string]any) { c["exp"] = now.Add(-time.Hour).Unix() }), hs256(secret)), internal.ErrInvalidCredential},
+		{"no expiration", "Bearer " + token(t, hs, claims(func(c map[string]any) { delete(c, "exp") }), hs256(secret)), internal.ErrInvalidCredential},
+		{"not yet valid", "Bearer " + token(t, hs, claims(func(c map[string]any) { c["nbf"] = now.Add(time.Hour).Unix() }), hs256(secret)), internal.ErrInvalidCredential},
+		{"other issuer", "Bearer " + token(t, hs, claims(func(c map[string]any) { c["iss"] = "other" }), hs256(secret)), internal.ErrInvalidCredential},
+		{"other audience", "Bearer " + token(t, hs, claims(func(c map[string]any) { c["aud"] = "other" }), hs256(secret)), internal.ErrInvalidCredential},
+		{"no subject", "Bearer " + token(t, hs, claims(func(c map[string]any) { delete(c, "sub") }), hs256(secret)), internal.ErrInvalidCredential},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/vehicles", nil)
+			if c.header != "" {
+				req.Header.Set("Authorization", c.header)
+			}
+			p, err := au.Authenticate(req)
+			if !errors.Is(err, c.wantErr) {
+				t.Fatalf("Authenticate() error = %v, want %v", err, c.wantErr)
+			}
+			if err == nil && (p.Subject != "alice" || p.Method != "jwt" || len(p.Roles) != 1 || p.Roles[0] != "admin") {
+				t.Fatalf("Authenticate() = %+v", p)
+			}
+		})
+	}
+}
+
+func TestChain_Authenticate(t *testing.T) {
+	au := NewChain(
+		NewAPIKeyMap(map[string]internal.Principal{"k1": {Subject: "alice", Method: "api_key"}}),
+		NewJWTBearer(ConfigJWTBearer{}),
+	)
+
+	cases := []struct {
+		name    string
+		apiKey  string
+		bearer  string
+		wantErr error
+	}{
+		{"api key", "k1", "", nil},
+		{"unknown api key", "k2", "", internal.ErrInvalidCredential},
+		{"invalid token after no api key", "", "Bearer abc", internal.ErrInvalidCredential},
+		{"no credentials", "", "", internal.ErrUnauthenticated},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/vehicles", nil)
+			if c.apiKey != "" {
+				req.Header.Set(HeaderAPIKey, c.apiKey)
+			}
+			if c.bearer != "" {
+				req.Header.Set("Authorization", c.bearer)
+			}
+			if _, err := au.Authenticate(req); !errors.Is(err, c.wantErr) {
+				t.Fatalf("Authenticate() error = %v, want %v", err, c.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"strings"
+)
+
+var (
+	ErrInvalidKeyFile = errors.New("invalid key file")
+)
+
+// NewJWTKeys is a function that returns a new empty instance of JWTKeys
+func NewJWTKeys() *JWTKeys {
+	return &JWTKeys{
+		hmac: make(map[string][]byte),
+		rsa:  make(map[string]*rsa.PublicKey),
+	}
+}
+
+// JWTKeys is a struct that holds the keys used to verify JWT signatures, indexed by key id.
+// Keys loaded from a single file are stored with an empty key id and used when a token has no kid
+type JWTKeys struct {
+	// hmac are the secrets for HS256
+	hmac map[string][]byte
+	// rsa are the public keys for RS256
+	rsa map[string]*rsa.PublicKey
+}
+
+// LoadHMACSecretFile is a method that loads a HS256 secret from a file
+func (k *JWTKeys) LoadHMACSecretFile(path string) (err error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	secret := strings.TrimSpace(string(b))
+	if secret == "" {
+		return ErrInvalidKeyFile
+	}
+	k.hmac[""] = []byte(secret)
+	return
+}
+
+// LoadRSAPublicKeyFile is a method that loads a RS256 public key from a PEM file
+func (k *JWTKeys) LoadRSAPublicKeyFile(path string) (err error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return ErrInvalidKeyFile
+	}
+
+	var key *rsa.PublicKey
+	switch block.Type {
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		var pub any
+		pub, err = x509.ParsePKIXPublicKey(block.Bytes)
+		if err == nil {
+			var ok bool
+			if key, ok = pub.(*rsa.PublicKey); !ok {
+				err = ErrInvalidKeyFile
+			}
+		}
+	}
+	if err != nil {
+		return
+	}
+	k.rsa[""] = key
+	return
+}
+
+// JWKJSON is a struct that represents a JSON Web Key
+type JWKJSON struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	// RSA
+	N string `json:"n"`
+	E string `json:"e"`
+	// oct
+	K string `json:"k"`
+}
+
+// LoadJWKSFile is a method that loads RSA and oct keys from a JWKS file
+func (k *JWTKeys) LoadJWKSFile(path string) (err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	var set struct {
+		Keys []JWKJSON `json:"keys"`
+	}
+	if err = json.NewDecoder(file).Decode(&set); err != nil {
+		return
+	}
+
+	for _, jwk := range set.Keys {
+		switch jwk.Kty {
+		case "RSA":
+			n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+			if err != nil {
+				return ErrInvalidKeyFile
+			}
+			e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+			if err != nil || len(e) == 0 {
+				return ErrInvalidKeyFile
+			}
+			k.rsa[jwk.Kid] = &rsa.PublicKey{
+				N: new(big.Int).SetBytes(n),
+				E: int(new(big.Int).SetBytes(e).Int64()),
+			}
+		case "oct":
+			secret, err := base64.RawURLEncoding.DecodeString(jwk.K)
+			if err != nil || len(secret) == 0 {
+				return ErrInvalidKeyFile
+			}
+			k.hmac[jwk.Kid] = secret
+		}
+	}
+	return
+}
+
+// Empty is a method that returns true when no key has been loaded
+func (k *JWTKeys) Empty() bool {
+	return len(k.hmac) == 0 && len(k.rsa) == 0
+}
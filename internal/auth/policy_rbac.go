@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"app/internal"
+	"encoding/json"
+	"os"
+	"strings"
+)
+
+// PolicyJSON is a struct that represents a role based policy in JSON format.
+// Permissions may use "*" or a "<resource>:*" wildcard
+type PolicyJSON struct {
+	Roles map[string][]string `json:"roles"`
+}
+
+// DefaultPolicy is the policy used when no policy file is configured
+func DefaultPolicy() map[string][]string {
+	return map[string][]string{
+		internal.RoleAnonymous: {internal.PermissionVehiclesRead, internal.PermissionCatalogsRead, internal.PermissionBrandsRead},
+		"viewer":               {internal.PermissionVehiclesRead, internal.PermissionCatalogsRead, internal.PermissionBrandsRead, internal.PermissionPeopleRead, internal.PermissionMaintenanceRead, internal.PermissionReservationsRead},
+		"fleet-operator": {
+			internal.PermissionVehiclesRead,
+			internal.PermissionCatalogsRead,
+			internal.PermissionBrandsRead,
+			internal.PermissionPeopleRead,
+			internal.PermissionAssignmentsWrite,
+			internal.PermissionMaintenanceRead,
+			internal.PermissionMaintenanceWrite,
+			internal.PermissionReservationsRead,
+			internal.PermissionReservationsWrite,
+			internal.PermissionVehiclesCreate,
+			internal.PermissionVehiclesBatch,
+			internal.PermissionVehiclesUpdateSpeed,
+			internal.PermissionVehiclesTransition,
+		},
+		"admin": {"*"},
+	}
+}
+
+// LoadPolicyJSONFile is a function that loads the roles and their permissions from a JSON file
+func LoadPolicyJSONFile(path string) (roles map[string][]string, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	var policy PolicyJSON
+	if err = json.NewDecoder(file).Decode(&policy); err != nil {
+		return
+	}
+	roles = policy.Roles
+	return
+}
+
+// NewRBAC is a function that returns a new instance of RBAC
+func NewRBAC(roles map[string][]string) *RBAC {
+	// default roles
+	defaultRoles := DefaultPolicy()
+	if roles != nil {
+		defaultRoles = roles
+	}
+
+	grants := make(map[string]map[string]struct{})
+	for role, permissions := range defaultRoles {
+		grants[role] = make(map[string]struct{})
+		for _, permission := range permissions {
+			grants[role][permission] = struct{}{}
+		}
+	}
+	return &RBAC{grants: grants}
+}
+
+// RBAC is a struct that implements a role based Authorizer
+type RBAC struct {
+	// grants is a set of permissions by role
+	grants map[string]map[string]struct{}
+}
+
+// Authorize is a method that checks if any role of the principal grants the permission
+func (a *RBAC) Authorize(p internal.Principal, permission string) (err error) {
+	roles := p.Roles
+	if p.Subject == "" {
+		roles = []string{internal.RoleAnonymous}
+	}
+
+	resource, _, _ := strings.Cut(permission, ":")
+	for _, role := range roles {
+		permissions, ok := a.grants[role]
+		if !ok {
+			continue
+		}
+		for _, candidate := range []string{permission, resource + ":*", "*"} {
+			if _, ok := permissions[candidate]; ok {
+				return nil
+			}
+		}
+	}
+	return internal.ErrForbidden
+}
@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"app/internal"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRBAC_Authorize(t *testing.T) {
+	az := NewRBAC(nil)
+	custom := NewRBAC(map[string][]string{"mechanic": {"maintenance:*"}})
+
+	cases := []struct {
+		name       string
+		az         *RBAC
+		principal  internal.Principal
+		permission string
+		wantErr    error
+	}{
+		{"anonymous reads", az, internal.Principal{}, internal.PermissionVehiclesRead, nil},
+		{"anonymous creates", az, internal.Principal{}, internal.PermissionVehiclesCreate, internal.ErrForbidden},
+		// the roles claimed without a subject are not trusted
+		{"anonymous with roles", az, internal.Principal{Roles: []string{"admin"}}, internal.PermissionVehiclesDelete, internal.ErrForbidden},
+		{"viewer reads people", az, internal.Principal{Subject: "v", Roles: []string{"viewer"}}, internal.PermissionPeopleRead, nil},
+		{"viewer creates", az, internal.Principal{Subject: "v", Roles: []string{"viewer"}}, internal.PermissionVehiclesCreate, internal.ErrForbidden},
+		{"operator creates", az, internal.Principal{Subject: "o", Roles: []string{"fleet-operator"}}, internal.PermissionVehiclesCreate, nil},
+		{"operator deletes", az, internal.Principal{Subject: "o", Roles: []string{"fleet-operator"}}, internal.PermissionVehiclesDelete, internal.ErrForbidden},
+		{"admin deletes", az, internal.Principal{Subject: "a", Roles: []string{"admin"}}, internal.PermissionVehiclesDelete, nil},
+		{"any role grants", az, internal.Principal{Subject: "a", Roles: []string{"viewer", "admin"}}, internal.PermissionVehiclesDelete, nil},
+		{"unknown role", az, internal.Principal{Subject: "u", Roles: []string{"unknown"}}, internal.PermissionVehiclesRead, internal.ErrForbidden},
+		{"no roles", az, internal.Principal{Subject: "u"}, internal.PermissionVehiclesRead, internal.ErrForbidden},
+		{"resource wildcard", custom, internal.Principal{Subject: "m", Roles: []string{"mechanic"}}, internal.PermissionMaintenanceWrite, nil},
+		{"resource wildcard of other resource", custom, internal.Principal{Subject: "m", Roles: []string{"mechanic"}}, internal.PermissionVehiclesRead, internal.ErrForbidden},
+		// a custom policy replaces the default one
+		{"custom policy without anonymous", custom, internal.Principal{}, internal.PermissionVehiclesRead, internal.ErrForbidden},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if err := c.az.Authorize(c.principal, c.permission); !errors.Is(err, c.wantErr) {
+				t.Fatalf("Authorize(%q) error = %v, want %v", c.permission, err, c.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadPolicyJSONFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.json")
+	if err := os.WriteFile(path, []byte(`{"roles":{"auditor":["vehicles:read","audit:*"]}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	roles, err := LoadPolicyJSONFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(roles) != 1 || len(roles["auditor"]) != 2 {
+		t.Fatalf("LoadPolicyJSONFile() = %v", roles)
+	}
+	if _, err := LoadPolicyJSONFile(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Fatal("LoadPolicyJSONFile() of a missing file succeeded")
+	}
+}
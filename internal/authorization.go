@@ -0,0 +1,61 @@
+package internal
+
+import "errors"
+
+var (
+	ErrForbidden = errors.New("not allowed to perform this operation")
+)
+
+// Permissions over the /vehicles routes
+const (
+	PermissionVehiclesRead        = "vehicles:read"
+	PermissionVehiclesCreate      = "vehicles:create"
+	PermissionVehiclesBatch       = "vehicles:batch"
+	PermissionVehiclesUpdateSpeed = "vehicles:update_speed"
+	PermissionVehiclesUpdateFuel  = "vehicles:update_fuel"
+	PermissionVehiclesDelete      = "vehicles:delete"
+	PermissionVehiclesBulkUpdate  = "vehicles:bulk_update"
+	PermissionVehiclesBulkDelete  = "vehicles:bulk_delete"
+	PermissionVehiclesTransition  = "vehicles:transition"
+	PermissionAuditRead           = "audit:read"
+)
+
+// Permissions over the /catalogs routes
+const (
+	PermissionCatalogsRead  = "catalogs:read"
+	PermissionCatalogsWrite = "catalogs:write"
+)
+
+// Permissions over the /brands routes
+const (
+	PermissionBrandsRead  = "brands:read"
+	PermissionBrandsWrite = "brands:write"
+)
+
+// Permissions over the /owners and /drivers routes and the assignments of the vehicles
+const (
+	PermissionPeopleRead       = "people:read"
+	PermissionPeopleWrite      = "people:write"
+	PermissionAssignmentsWrite = "assignments:write"
+)
+
+// Permissions over the maintenance, odometer readings and usage of the vehicles and the /maintenance routes
+const (
+	PermissionMaintenanceRead  = "maintenance:read"
+	PermissionMaintenanceWrite = "maintenance:write"
+)
+
+// Permissions over the reservations of the vehicles
+const (
+	PermissionReservationsRead  = "reservations:read"
+	PermissionReservationsWrite = "reservations:write"
+)
+
+// RoleAnonymous is the role assumed by requests without a principal
+const RoleAnonymous = "anonymous"
+
+// Authorizer is an interface that represents a policy that decides what a principal can do
+type Authorizer interface {
+	// Authorize returns ErrForbidden when none of the roles of the principal grants the permission
+	Authorize(p Principal, permission string) (err error)
+}
@@ -0,0 +1,118 @@
+package internal
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrBrandNotFound      = errors.New("brand not found")
+	ErrBrandAlreadyExists = errors.New("brand already exists")
+	ErrBrandInUse         = errors.New("brand is referenced by vehicles")
+	ErrModelNotFound      = errors.New("model not found")
+	ErrModelAlreadyExists = errors.New("model already exists")
+	ErrModelInUse         = errors.New("model is referenced by vehicles")
+	ErrInvalidName        = errors.New("name must not be empty")
+	ErrNotInRegistry      = errors.New("brand or model not in the registry")
+)
+
+// Brand is a struct that represents a vehicle brand and its manufacturer
+type Brand struct {
+	// Name is the display name, brands are unique by normalized key
+	Name string
+	// Manufacturer is the company that makes the vehicles of the brand
+	Manufacturer string
+	// Country is the country of origin of the brand
+	Country string
+}
+
+// VehicleModel is a struct that represents a model of a brand and the default specs of its vehicles.
+// Zero specs mean there is no default
+type VehicleModel struct {
+	// Brand is the name of the brand of the model
+	Brand string
+	// Name is the display name, models are unique by normalized key within a brand
+	Name string
+	// Specs are the default attributes of the vehicles of the model
+	Capacity     int
+	MaxSpeed     float64
+	FuelType     string
+	Transmission string
+	Weight       float64
+	Height       float64
+	Length       float64
+	Width        float64
+}
+
+// Template is a method that returns a vehicle with the brand, the model and the default specs filled in
+func (m VehicleModel) Template() Vehicle {
+	return Vehicle{
+		VehicleAttributes: VehicleAttributes{
+			Brand:        m.Brand,
+			Model:        m.Name,
+			Capacity:     m.Capacity,
+			MaxSpeed:     m.MaxSpeed,
+			FuelType:     m.FuelType,
+			Transmission: m.Transmission,
+			Weight:       m.Weight,
+			Dimensions: Dimensions{
+				Height: m.Height,
+				Length: m.Length,
+				Width:  m.Width,
+			},
+		},
+	}
+}
+
+// BrandRepository is an interface that represents a registry of brands and models
+type BrandRepository interface {
+	// FindAll returns the brands sorted by name
+	FindAll(ctx context.Context) (b []Brand, err error)
+	// FindOne returns a brand by its name
+	FindOne(ctx context.Context, name string) (b Brand, err error)
+	// Create adds a brand
+	Create(ctx context.Context, brand Brand) (err error)
+	// Update replaces the manufacturer info of a brand
+	Update(ctx context.Context, brand Brand) (b Brand, err error)
+	// Delete removes a brand and its models
+	Delete(ctx context.Context, name string) (err error)
+	// FindModels returns the models of a brand sorted by name
+	FindModels(ctx context.Context, brand string) (m []VehicleModel, err error)
+	// FindModel returns a model of a brand by its name
+	FindModel(ctx context.Context, brand string, name string) (m VehicleModel, err error)
+	// CreateModel adds a model to its brand
+	CreateModel(ctx context.Context, model VehicleModel) (err error)
+	// UpdateModel replaces the specs of a model
+	UpdateModel(ctx context.Context, model VehicleModel) (m VehicleModel, err error)
+	// DeleteModel removes a model
+	DeleteModel(ctx context.Context, brand string, name string) (err error)
+}
+
+// BrandService is an interface that represents a service of brands and models
+type BrandService interface {
+	// FindAll returns the brands sorted by name
+	FindAll(ctx context.Context) (b []Brand, err error)
+	// FindOne returns a brand by its name
+	FindOne(ctx context.Context, name string) (b Brand, err error)
+	// Create adds a brand
+	Create(ctx context.Context, brand Brand) (b Brand, err error)
+	// Update replaces the manufacturer info of a brand
+	Update(ctx context.Context, brand Brand) (b Brand, err error)
+	// Delete removes a brand and its models unless vehicles still reference it
+	Delete(ctx context.Context, name string) (err error)
+	// FindModels returns the models of a brand sorted by name
+	FindModels(ctx context.Context, brand string) (m []VehicleModel, err error)
+	// FindModel returns a model of a brand by its name
+	FindModel(ctx context.Context, brand string, name string) (m VehicleModel, err error)
+	// CreateModel adds a model to its brand
+	CreateModel(ctx context.Context, model VehicleModel) (m VehicleModel, err error)
+	// UpdateModel replaces the specs of a model
+	UpdateModel(ctx context.Context, model VehicleModel) (m VehicleModel, err error)
+	// DeleteModel removes a model unless vehicles still reference it
+	DeleteModel(ctx context.Context, brand string, name string) (err error)
+	// FindModelVehicles returns the vehicles linked to a model
+	FindModelVehicles(ctx context.Context, brand string, name string) (v []Vehicle, err error)
+	// CreateVehicleFromModel creates a vehicle from the template of a model, the patch sets the
+	// attributes that are not part of the template and overrides the defaults
+	CreateVehicleFromModel(ctx context.Context, brand string, name string, id int, patch VehiclePatch) (v Vehicle, err error)
+}
@@ -0,0 +1,87 @@
+package internal
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrCatalogNotFound           = errors.New("catalog not found")
+	ErrCatalogEntryNotFound      = errors.New("catalog entry not found")
+	ErrCatalogEntryAlreadyExists = errors.New("catalog entry already exists")
+	ErrCatalogEntryInUse         = errors.New("catalog entry is referenced by vehicles")
+	ErrCatalogEntryInvalid       = errors.New("catalog entry value must not be empty")
+	ErrNotInCatalog              = errors.New("value not in catalog")
+)
+
+// CatalogKind is the name of a catalog of allowed values
+type CatalogKind string
+
+const (
+	CatalogFuelTypes     CatalogKind = "fuel_types"
+	CatalogTransmissions CatalogKind = "transmissions"
+	CatalogColors        CatalogKind = "colors"
+)
+
+// CatalogKinds are the catalogs by the vehicle attribute they govern
+var CatalogKinds = map[string]CatalogKind{
+	"fuel_type":    CatalogFuelTypes,
+	"transmission": CatalogTransmissions,
+	"color":        CatalogColors,
+}
+
+// Valid is a method that checks if the catalog exists
+func (k CatalogKind) Valid() bool {
+	for _, kind := range CatalogKinds {
+		if kind == k {
+			return true
+		}
+	}
+	return false
+}
+
+// Attribute is a method that returns the vehicle attribute governed by the catalog
+func (k CatalogKind) Attribute() string {
+	for attribute, kind := range CatalogKinds {
+		if kind == k {
+			return attribute
+		}
+	}
+	return ""
+}
+
+// CatalogEntry is a struct that represents an allowed value of a catalog
+type CatalogEntry struct {
+	// Value is the display value, entries are unique by normalized key
+	Value string
+	// Description is a free text about the entry
+	Description string
+}
+
+// CatalogRepository is an interface that represents a repository of catalogs
+type CatalogRepository interface {
+	// FindAll returns the entries of a catalog sorted by value
+	FindAll(ctx context.Context, kind CatalogKind) (e []CatalogEntry, err error)
+	// FindOne returns an entry of a catalog by its value
+	FindOne(ctx context.Context, kind CatalogKind, value string) (e CatalogEntry, err error)
+	// Create adds an entry to a catalog
+	Create(ctx context.Context, kind CatalogKind, entry CatalogEntry) (err error)
+	// Update replaces the description of an entry
+	Update(ctx context.Context, kind CatalogKind, value string, description string) (e CatalogEntry, err error)
+	// Delete removes an entry from a catalog
+	Delete(ctx context.Context, kind CatalogKind, value string) (err error)
+}
+
+// CatalogService is an interface that represents a service of catalogs
+type CatalogService interface {
+	// FindAll returns the entries of a catalog sorted by value
+	FindAll(ctx context.Context, kind CatalogKind) (e []CatalogEntry, err error)
+	// FindOne returns an entry of a catalog by its value
+	FindOne(ctx context.Context, kind CatalogKind, value string) (e CatalogEntry, err error)
+	// Create adds an entry to a catalog
+	Create(ctx context.Context, kind CatalogKind, entry CatalogEntry) (e CatalogEntry, err error)
+	// Update replaces the description of an entry
+	Update(ctx context.Context, kind CatalogKind, value string, description string) (e CatalogEntry, err error)
+	// Delete removes an entry from a catalog unless vehicles still reference it
+	Delete(ctx context.Context, kind CatalogKind, value string) (err error)
+}
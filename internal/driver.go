@@ -0,0 +1,53 @@
+package internal
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrDriverNotFound = errors.New("driver not found")
+	ErrDriverInUse    = errors.New("driver has current or upcoming vehicles")
+)
+
+// Driver is a struct that represents a person allowed to drive the vehicles of the fleet
+type Driver struct {
+	// Id is the unique identifier of the driver, given by the repository
+	Id int
+	// Name is the name of the driver
+	Name string
+	// LicenseNumber is the number of the driving license
+	LicenseNumber string
+	// Email is the contact email of the driver
+	Email string
+	// Phone is the contact phone of the driver
+	Phone string
+}
+
+// DriverRepository is an interface that represents a repository of drivers
+type DriverRepository interface {
+	// FindAll returns the drivers sorted by id
+	FindAll(ctx context.Context) (d []Driver, err error)
+	// FindOne returns a driver by its id
+	FindOne(ctx context.Context, id int) (d Driver, err error)
+	// Create adds a driver with the next id
+	Create(ctx context.Context, driver Driver) (d Driver, err error)
+	// Update replaces the data of a driver
+	Update(ctx context.Context, driver Driver) (d Driver, err error)
+	// Delete removes a driver
+	Delete(ctx context.Context, id int) (err error)
+}
+
+// DriverService is an interface that represents a service of drivers
+type DriverService interface {
+	// FindAll returns the drivers sorted by id
+	FindAll(ctx context.Context) (d []Driver, err error)
+	// FindOne returns a driver by its id
+	FindOne(ctx context.Context, id int) (d Driver, err error)
+	// Create adds a driver
+	Create(ctx context.Context, driver Driver) (d Driver, err error)
+	// Update replaces the data of a driver
+	Update(ctx context.Context, driver Driver) (d Driver, err error)
+	// Delete removes a driver unless it has current or upcoming vehicles
+	Delete(ctx context.Context, id int) (err error)
+}
@@ -0,0 +1,226 @@
+package handler
+
+import (
+	"app/internal"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bootcamp-go/web/response"
+	"github.com/go-chi/chi/v5"
+)
+
+// AssignmentJSON is a struct that represents an assignment in JSON format
+type AssignmentJSON struct {
+	ID        int        `json:"id"`
+	Role      string     `json:"role"`
+	VehicleID int        `json:"vehicle_id"`
+	PersonID  int        `json:"person_id"`
+	From      time.Time  `json:"from"`
+	To        *time.Time `json:"to"`
+}
+
+// AssignmentCreateJSON is a struct that represents the body of POST /vehicles/{id}/assignments,
+// from defaults to now and to to open-ended
+type AssignmentCreateJSON struct {
+	Role     string     `json:"role"`
+	PersonID *int       `json:"person_id"`
+	From     *time.Time `json:"from"`
+	To       *time.Time `json:"to"`
+}
+
+// AssignmentEndJSON is a struct that represents the optional body of POST /vehicles/{id}/assignments/{assignment_id}/end,
+// at defaults to now
+type AssignmentEndJSON struct {
+	At *time.Time `json:"at"`
+}
+
+// NewAssignmentDefault is a function that returns a new instance of AssignmentDefault
+func NewAssignmentDefault(sv internal.AssignmentService) *AssignmentDefault {
+	return &AssignmentDefault{sv: sv}
+}
+
+// AssignmentDefault is a struct with methods that represent handlers for the owners and drivers of the vehicles
+type AssignmentDefault struct {
+	// sv is the service that will be used by the handler
+	sv internal.AssignmentService
+}
+
+// Create is a method that returns a handler for the route POST /vehicles/{id}/assignments
+func (h *AssignmentDefault) Create() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID format. ID must be an int number.")
+			return
+		}
+		var body AssignmentCreateJSON
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid body")
+			return
+		}
+		if body.Role == "" || body.PersonID == nil {
+			response.Text(w, http.StatusBadRequest, "invalid body. Keys are missing")
+			return
+		}
+		assignment := internal.Assignment{
+			Role:      internal.AssignmentRole(body.Role),
+			VehicleID: id,
+			PersonID:  *body.PersonID,
+			To:        body.To,
+		}
+		if body.From != nil {
+			assignment.From = *body.From
+		}
+
+		// process
+		a, ended, err := h.sv.Assign(r.Context(), assignment)
+		if err != nil {
+			writeAssignmentError(w, err)
+			return
+		}
+
+		// response
+		data := map[string]any{"assignment": newAssignmentJSON(a), "ended": nil}
+		if ended != nil {
+			data["ended"] = newAssignmentJSON(*ended)
+		}
+		response.JSON(w, http.StatusCreated, map[string]any{
+			"message": "assignment created successfully",
+			"data":    data,
+		})
+	}
+}
+
+// End is a method that returns a handler for the route POST /vehicles/{id}/assignments/{assignment_id}/end
+func (h *AssignmentDefault) End() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID format. ID must be an int number.")
+			return
+		}
+		assignmentID, err := strconv.Atoi(chi.URLParam(r, "assignment_id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid assignment ID format. ID must be an int number.")
+			return
+		}
+		var body AssignmentEndJSON
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+			response.Text(w, http.StatusBadRequest, "invalid body")
+			return
+		}
+		var at time.Time
+		if body.At != nil {
+			at = *body.At
+		}
+
+		// process
+		a, err := h.sv.End(r.Context(), id, assignmentID, at)
+		if err != nil {
+			writeAssignmentError(w, err)
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "assignment ended successfully",
+			"data":    newAssignmentJSON(a),
+		})
+	}
+}
+
+// GetByVehicle is a method that returns a handler for the route GET /vehicles/{id}/assignments?role=
+func (h *AssignmentDefault) GetByVehicle() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID format. ID must be an int number.")
+			return
+		}
+
+		// process
+		assignments, err := h.sv.FindByVehicle(r.Context(), id, internal.AssignmentRole(r.URL.Query().Get("role")))
+		if err != nil {
+			writeAssignmentError(w, err)
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    newAssignmentsJSON(assignments),
+		})
+	}
+}
+
+// GetByPerson is a method that returns a handler for the routes GET /owners/{id}/assignments
+// and GET /drivers/{id}/assignments, depending on the role
+func (h *AssignmentDefault) GetByPerson(role internal.AssignmentRole) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID format. ID must be an int number.")
+			return
+		}
+
+		// process
+		assignments, err := h.sv.FindByPerson(r.Context(), role, id)
+		if err != nil {
+			writeAssignmentError(w, err)
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    newAssignmentsJSON(assignments),
+		})
+	}
+}
+
+// writeAssignmentError writes the response of an error of an assignment operation
+func writeAssignmentError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, internal.ErrAssignmentNotFound), errors.Is(err, internal.ErrVehicleNotFounded),
+		errors.Is(err, internal.ErrOwnerNotFound), errors.Is(err, internal.ErrDriverNotFound):
+		response.Text(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, internal.ErrAssignmentOverlap):
+		response.Text(w, http.StatusConflict, err.Error())
+	case errors.Is(err, internal.ErrInvalidAssignment):
+		response.Text(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		response.Text(w, http.StatusGatewayTimeout, err.Error())
+	default:
+		response.Text(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// newAssignmentJSON converts an assignment to JSON format
+func newAssignmentJSON(a internal.Assignment) AssignmentJSON {
+	return AssignmentJSON{
+		ID:        a.Id,
+		Role:      string(a.Role),
+		VehicleID: a.VehicleID,
+		PersonID:  a.PersonID,
+		From:      a.From,
+		To:        a.To,
+	}
+}
+
+// newAssignmentsJSON converts a history of assignments to JSON format
+func newAssignmentsJSON(assignments []internal.Assignment) []AssignmentJSON {
+	data := make([]AssignmentJSON, len(assignments))
+	for i, a := range assignments {
+		data[i] = newAssignmentJSON(a)
+	}
+	return data
+}
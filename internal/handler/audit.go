@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"app/internal"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bootcamp-go/web/response"
+	"github.com/go-chi/chi/v5"
+)
+
+// AuditEntryJSON is a struct that represents an audit entry in JSON format
+type AuditEntryJSON struct {
+	ID        int          `json:"id"`
+	VehicleID int          `json:"vehicle_id"`
+	Action    string       `json:"action"`
+	Actor     string       `json:"actor"`
+	RequestID string       `json:"request_id"`
+	Timestamp time.Time    `json:"timestamp"`
+	Reason    string       `json:"reason,omitempty"`
+	Before    *VehicleJSON `json:"before"`
+	After     *VehicleJSON `json:"after"`
+}
+
+// NewAuditDefault is a function that returns a new instance of AuditDefault
+func NewAuditDefault(sv internal.AuditService) *AuditDefault {
+	return &AuditDefault{sv: sv}
+}
+
+// AuditDefault is a struct with methods that represent handlers for the audit log
+type AuditDefault struct {
+	// sv is the service that will be used by the handler
+	sv internal.AuditService
+}
+
+// GetAll is a method that returns a handler for the route GET /audit?from=&to=
+func (h *AuditDefault) GetAll() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		from, to, err := parseTimeRange(r)
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		entries, err := h.sv.FindAll(r.Context(), from, to)
+		if err != nil {
+			writeAuditError(w, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "audit entries found",
+			"data":    newAuditEntriesJSON(r, entries),
+		})
+	}
+}
+
+// GetByVehicle is a method that returns a handler for the route GET /vehicles/{id}/audit?from=&to=
+func (h *AuditDefault) GetByVehicle() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID format. ID must be an int number.")
+			return
+		}
+
+		from, to, err := parseTimeRange(r)
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		entries, err := h.sv.FindByVehicle(r.Context(), id, from, to)
+		if err != nil {
+			writeAuditError(w, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "audit entries found",
+			"data":    newAuditEntriesJSON(r, entries),
+		})
+	}
+}
+
+// writeAuditError writes the response of an error of the audit service
+func writeAuditError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		response.Text(w, http.StatusGatewayTimeout, err.Error())
+	default:
+		response.Text(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// parseTimeRange parses the optional from and to query params in RFC 3339 format
+func parseTimeRange(r *http.Request) (from, to time.Time, err error) {
+	if s := r.URL.Query().Get("from"); s != "" {
+		if from, err = time.Parse(time.RFC3339, s); err != nil {
+			err = errors.New("invalid from value, it must be a RFC 3339 timestamp")
+			return
+		}
+	}
+	if s := r.URL.Query().Get("to"); s != "" {
+		if to, err = time.Parse(time.RFC3339, s); err != nil {
+			err = errors.New("invalid to value, it must be a RFC 3339 timestamp")
+			return
+		}
+	}
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		err = errors.New("invalid time range, from must be before to")
+	}
+	return
+}
+
+// newAuditEntriesJSON is a function that serializes audit entries in JSON format, in the unit system of the request
+func newAuditEntriesJSON(r *http.Request, entries []internal.AuditEntry) []AuditEntryJSON {
+	data := make([]AuditEntryJSON, len(entries))
+	for i, e := range entries {
+		data[i] = AuditEntryJSON{
+			ID:        e.Id,
+			VehicleID: e.VehicleID,
+			Action:    e.Action,
+			Actor:     e.Actor,
+			RequestID: e.RequestID,
+			Timestamp: e.Timestamp,
+			Reason:    e.Reason,
+		}
+		if e.Before != nil {
+			before := newVehicleJSON(outVehicle(r, *e.Before))
+			data[i].Before = &before
+		}
+		if e.After != nil {
+			after := newVehicleJSON(outVehicle(r, *e.After))
+			data[i].After = &after
+		}
+	}
+	return data
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"app/internal/service"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestAuditDefault_Get(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	rp := repository.NewAuditSlice(nil)
+	for i, vehicleID := range []int{1, 2, 1} {
+		after := internal.Vehicle{Id: vehicleID, VehicleAttributes: internal.VehicleAttributes{MaxSpeed: 100}}
+		entry := internal.AuditEntry{VehicleID: vehicleID, Action: internal.AuditActionUpdateMaxSpeed, Timestamp: t0.Add(time.Duration(i) * time.Hour), After: &after}
+		if err := rp.Append(context.Background(), entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+	hd := NewAuditDefault(service.NewAuditDefault(rp))
+	rt := chi.NewRouter()
+	rt.Get("/audit", hd.GetAll())
+	rt.Get("/vehicles/{id}/audit", hd.GetByVehicle())
+
+	cases := []struct {
+		name   string
+		target string
+		code   int
+		want   []int
+	}{
+		{"all", "/audit", http.StatusOK, []int{1, 2, 3}},
+		{"from", "/audit?from=2026-01-01T01:00:00Z", http.StatusOK, []int{2, 3}},
+		{"to", "/audit?to=2026-01-01T01:00:00Z", http.StatusOK, []int{1, 2}},
+		{"from and to", "/audit?from=2026-01-01T00:30:00Z&to=2026-01-01T01:30:00Z", http.StatusOK, []int{2}},
+		{"in another zone", "/audit?from=2026-01-01T02:00:00%2B01:00", http.StatusOK, []int{2, 3}},
+		{"reversed range", "/audit?from=2026-01-02T00:00:00Z&to=2026-01-01T00:00:00Z", http.StatusBadRequest, nil},
+		{"invalid from", "/audit?from=yesterday", http.StatusBadRequest, nil},
+		{"invalid to", "/audit?to=2026-01-01", http.StatusBadRequest, nil},
+		{"vehicle", "/vehicles/1/audit", http.StatusOK, []int{1, 3}},
+		{"vehicle from", "/vehicles/1/audit?from=2026-01-01T01:00:00Z", http.StatusOK, []int{3}},
+		{"vehicle without entries", "/vehicles/9/audit", http.StatusOK, []int{}},
+		{"invalid vehicle", "/vehicles/a/audit", http.StatusBadRequest, nil},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			res := httptest.NewRecorder()
+			rt.ServeHTTP(res, httptest.NewRequest(http.MethodGet, c.target, nil))
+			if res.Code != c.code {
+				t.Fatalf("code = %d, want %d: %s", res.Code, c.code, res.Body.String())
+			}
+			if c.want == nil {
+				return
+			}
+
+			var body struct {
+				Data []AuditEntryJSON `json:"data"`
+			}
+			if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			if len(body.Data) != len(c.want) {
+				t.Fatalf("entries = %d, want %v", len(body.Data), c.want)
+			}
+			for i, e := range body.Data {
+				if e.ID != c.want[i] || e.After == nil || e.Before != nil {
+					t.Fatalf("entries[%d] = %+v, want id %d", i, e, c.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,384 @@
+package handler
+
+import (
+	"app/internal"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/bootcamp-go/web/response"
+	"github.com/go-chi/chi/v5"
+)
+
+// BrandJSON is a struct that represents a brand in JSON format
+type BrandJSON struct {
+	Name         string `json:"name"`
+	Manufacturer string `json:"manufacturer"`
+	Country      string `json:"country"`
+}
+
+// VehicleModelJSON is a struct that represents a model and its default specs in JSON format
+type VehicleModelJSON struct {
+	Brand        string  `json:"brand"`
+	Name         string  `json:"name"`
+	Capacity     int     `json:"passengers"`
+	MaxSpeed     float64 `json:"max_speed"`
+	FuelType     string  `json:"fuel_type"`
+	Transmission string  `json:"transmission"`
+	Weight       float64 `json:"weight"`
+	Height       float64 `json:"height"`
+	Length       float64 `json:"length"`
+	Width        float64 `json:"width"`
+}
+
+// VehicleFromModelJSON is a struct that represents the body of POST /brands/{brand}/models/{model}/vehicles:
+// the id, the attributes that are not part of the template and the defaults to override
+type VehicleFromModelJSON struct {
+	ID *int `json:"id"`
+	VehiclePatchJSON
+}
+
+// NewBrandDefault is a function that returns a new instance of BrandDefault
+func NewBrandDefault(sv internal.BrandService) *BrandDefault {
+	return &BrandDefault{sv: sv}
+}
+
+// BrandDefault is a struct with methods that represent handlers for the brand and model registry
+type BrandDefault struct {
+	// sv is the service that will be used by the handler
+	sv internal.BrandService
+}
+
+// GetAll is a method that returns a handler for the route GET /brands
+func (h *BrandDefault) GetAll() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// process
+		brands, err := h.sv.FindAll(r.Context())
+		if err != nil {
+			writeBrandError(w, err)
+			return
+		}
+
+		// response
+		data := make([]BrandJSON, len(brands))
+		for i, b := range brands {
+			data[i] = newBrandJSON(b)
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    data,
+		})
+	}
+}
+
+// GetByName is a method that returns a handler for the route GET /brands/{brand}
+func (h *BrandDefault) GetByName() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// process
+		b, err := h.sv.FindOne(r.Context(), chi.URLParam(r, "brand"))
+		if err != nil {
+			writeBrandError(w, err)
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    newBrandJSON(b),
+		})
+	}
+}
+
+// Create is a method that returns a handler for the route POST /brands
+func (h *BrandDefault) Create() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		var body BrandJSON
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid body")
+			return
+		}
+
+		// process
+		b, err := h.sv.Create(r.Context(), body.toDomain())
+		if err != nil {
+			writeBrandError(w, err)
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusCreated, map[string]any{
+			"message": "brand created successfully",
+			"data":    newBrandJSON(b),
+		})
+	}
+}
+
+// Update is a method that returns a handler for the route PUT /brands/{brand}
+func (h *BrandDefault) Update() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		var body BrandJSON
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid body")
+			return
+		}
+		body.Name = chi.URLParam(r, "brand")
+
+		// process
+		b, err := h.sv.Update(r.Context(), body.toDomain())
+		if err != nil {
+			writeBrandError(w, err)
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "brand updated successfully",
+			"data":    newBrandJSON(b),
+		})
+	}
+}
+
+// Delete is a method that returns a handler for the route DELETE /brands/{brand}
+func (h *BrandDefault) Delete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// process
+		if err := h.sv.Delete(r.Context(), chi.URLParam(r, "brand")); err != nil {
+			writeBrandError(w, err)
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusNoContent, map[string]any{
+			"message": "brand deleted successfully",
+		})
+	}
+}
+
+// GetModels is a method that returns a handler for the route GET /brands/{brand}/models
+func (h *BrandDefault) GetModels() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// process
+		models, err := h.sv.FindModels(r.Context(), chi.URLParam(r, "brand"))
+		if err != nil {
+			writeBrandError(w, err)
+			return
+		}
+
+		// response
+		data := make([]VehicleModelJSON, len(models))
+		for i, m := range models {
+			data[i] = newVehicleModelJSON(outModel(r, m))
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    data,
+		})
+	}
+}
+
+// GetModel is a method that returns a handler for the route GET /brands/{brand}/models/{model}
+func (h *BrandDefault) GetModel() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// process
+		m, err := h.sv.FindModel(r.Context(), chi.URLParam(r, "brand"), chi.URLParam(r, "model"))
+		if err != nil {
+			writeBrandError(w, err)
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    newVehicleModelJSON(outModel(r, m)),
+		})
+	}
+}
+
+// CreateModel is a method that returns a handler for the route POST /brands/{brand}/models
+func (h *BrandDefault) CreateModel() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		var body VehicleModelJSON
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid body")
+			return
+		}
+		body.Brand = chi.URLParam(r, "brand")
+
+		// process
+		m, err := h.sv.CreateModel(r.Context(), inModel(r, body.toDomain()))
+		if err != nil {
+			writeBrandError(w, err)
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusCreated, map[string]any{
+			"message": "model created successfully",
+			"data":    newVehicleModelJSON(outModel(r, m)),
+		})
+	}
+}
+
+// UpdateModel is a method that returns a handler for the route PUT /brands/{brand}/models/{model}
+func (h *BrandDefault) UpdateModel() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		var body VehicleModelJSON
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid body")
+			return
+		}
+		body.Brand, body.Name = chi.URLParam(r, "brand"), chi.URLParam(r, "model")
+
+		// process
+		m, err := h.sv.UpdateModel(r.Context(), inModel(r, body.toDomain()))
+		if err != nil {
+			writeBrandError(w, err)
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "model updated successfully",
+			"data":    newVehicleModelJSON(outModel(r, m)),
+		})
+	}
+}
+
+// DeleteModel is a method that returns a handler for the route DELETE /brands/{brand}/models/{model}
+func (h *BrandDefault) DeleteModel() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// process
+		if err := h.sv.DeleteModel(r.Context(), chi.URLParam(r, "brand"), chi.URLParam(r, "model")); err != nil {
+			writeBrandError(w, err)
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusNoContent, map[string]any{
+			"message": "model deleted successfully",
+		})
+	}
+}
+
+// GetModelVehicles is a method that returns a handler for the route GET /brands/{brand}/models/{model}/vehicles
+func (h *BrandDefault) GetModelVehicles() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// process
+		vehicles, err := h.sv.FindModelVehicles(r.Context(), chi.URLParam(r, "brand"), chi.URLParam(r, "model"))
+		if err != nil {
+			writeBrandError(w, err)
+			return
+		}
+
+		// response
+		data := make([]VehicleJSON, len(vehicles))
+		for i, v := range vehicles {
+			data[i] = newVehicleJSON(outVehicle(r, v))
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    data,
+		})
+	}
+}
+
+// CreateVehicle is a method that returns a handler for the route POST /brands/{brand}/models/{model}/vehicles
+func (h *BrandDefault) CreateVehicle() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		var body VehicleFromModelJSON
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid body")
+			return
+		}
+		if body.ID == nil {
+			response.Text(w, http.StatusBadRequest, "invalid body. Keys are missing")
+			return
+		}
+
+		// process
+		v, err := h.sv.CreateVehicleFromModel(r.Context(), chi.URLParam(r, "brand"), chi.URLParam(r, "model"), *body.ID, inPatch(r, body.VehiclePatchJSON.toDomain()))
+		if err != nil {
+			switch {
+			case errors.Is(err, internal.ErrCarAlreadyExists), errors.Is(err, internal.ErrRegistrationAlreadyExists), errors.Is(err, internal.ErrVINAlreadyExists):
+				response.Text(w, http.StatusConflict, err.Error())
+			case errors.Is(err, internal.ErrNotInCatalog), errors.Is(err, internal.ErrNotInRegistry), errors.Is(err, internal.ErrInvalidRegistration),
+				errors.Is(err, internal.ErrInvalidVIN), errors.Is(err, internal.ErrVINMismatch):
+				response.Text(w, http.StatusUnprocessableEntity, err.Error())
+			default:
+				writeBrandError(w, err)
+			}
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusCreated, map[string]any{
+			"message": "Vehicle created successfully",
+			"data":    newVehicleJSON(outVehicle(r, v)),
+		})
+	}
+}
+
+// writeBrandError writes the response of an error of a registry operation
+func writeBrandError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, internal.ErrBrandNotFound), errors.Is(err, internal.ErrModelNotFound):
+		response.Text(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, internal.ErrBrandAlreadyExists), errors.Is(err, internal.ErrModelAlreadyExists),
+		errors.Is(err, internal.ErrBrandInUse), errors.Is(err, internal.ErrModelInUse):
+		response.Text(w, http.StatusConflict, err.Error())
+	case errors.Is(err, internal.ErrInvalidName):
+		response.Text(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		response.Text(w, http.StatusGatewayTimeout, err.Error())
+	default:
+		response.Text(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// newBrandJSON converts a brand to JSON format
+func newBrandJSON(b internal.Brand) BrandJSON {
+	return BrandJSON{Name: b.Name, Manufacturer: b.Manufacturer, Country: b.Country}
+}
+
+// toDomain is a method that converts the brand to the domain
+func (b BrandJSON) toDomain() internal.Brand {
+	return internal.Brand{Name: b.Name, Manufacturer: b.Manufacturer, Country: b.Country}
+}
+
+// newVehicleModelJSON converts a model to JSON format
+func newVehicleModelJSON(m internal.VehicleModel) VehicleModelJSON {
+	return VehicleModelJSON{
+		Brand:        m.Brand,
+		Name:         m.Name,
+		Capacity:     m.Capacity,
+		MaxSpeed:     m.MaxSpeed,
+		FuelType:     m.FuelType,
+		Transmission: m.Transmission,
+		Weight:       m.Weight,
+		Height:       m.Height,
+		Length:       m.Length,
+		Width:        m.Width,
+	}
+}
+
+// toDomain is a method that converts the model to the domain
+func (m VehicleModelJSON) toDomain() internal.VehicleModel {
+	return internal.VehicleModel{
+		Brand:        m.Brand,
+		Name:         m.Name,
+		Capacity:     m.Capacity,
+		MaxSpeed:     m.MaxSpeed,
+		FuelType:     m.FuelType,
+		Transmission: m.Transmission,
+		Weight:       m.Weight,
+		Height:       m.Height,
+		Length:       m.Length,
+		Width:        m.Width,
+	}
+}
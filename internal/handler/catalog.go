@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"app/internal"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/bootcamp-go/web/response"
+	"github.com/go-chi/chi/v5"
+)
+
+// CatalogEntryJSON is a struct that represents a catalog entry in JSON format
+type CatalogEntryJSON struct {
+	Value       string `json:"value"`
+	Description string `json:"description"`
+}
+
+// NewCatalogDefault is a function that returns a new instance of CatalogDefault
+func NewCatalogDefault(sv internal.CatalogService) *CatalogDefault {
+	return &CatalogDefault{sv: sv}
+}
+
+// CatalogDefault is a struct with methods that represent handlers for the catalogs
+type CatalogDefault struct {
+	// sv is the service that will be used by the handler
+	sv internal.CatalogService
+}
+
+// GetAll is a method that returns a handler for the route GET /catalogs/{catalog}
+func (h *CatalogDefault) GetAll() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		kind := internal.CatalogKind(chi.URLParam(r, "catalog"))
+
+		// process
+		entries, err := h.sv.FindAll(r.Context(), kind)
+		if err != nil {
+			writeCatalogError(w, err)
+			return
+		}
+
+		// response
+		data := make([]CatalogEntryJSON, len(entries))
+		for i, e := range entries {
+			data[i] = CatalogEntryJSON{Value: e.Value, Description: e.Description}
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    data,
+		})
+	}
+}
+
+// GetByValue is a method that returns a handler for the route GET /catalogs/{catalog}/{value}
+func (h *CatalogDefault) GetByValue() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		kind := internal.CatalogKind(chi.URLParam(r, "catalog"))
+
+		// process
+		e, err := h.sv.FindOne(r.Context(), kind, chi.URLParam(r, "value"))
+		if err != nil {
+			writeCatalogError(w, err)
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    CatalogEntryJSON{Value: e.Value, Description: e.Description},
+		})
+	}
+}
+
+// Create is a method that returns a handler for the route POST /catalogs/{catalog}
+func (h *CatalogDefault) Create() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		kind := internal.CatalogKind(chi.URLParam(r, "catalog"))
+		var body CatalogEntryJSON
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid body")
+			return
+		}
+
+		// process
+		e, err := h.sv.Create(r.Context(), kind, internal.CatalogEntry{Value: body.Value, Description: body.Description})
+		if err != nil {
+			writeCatalogError(w, err)
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusCreated, map[string]any{
+			"message": "catalog entry created successfully",
+			"data":    CatalogEntryJSON{Value: e.Value, Description: e.Description},
+		})
+	}
+}
+
+// Update is a method that returns a handler for the route PUT /catalogs/{catalog}/{value}
+func (h *CatalogDefault) Update() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		kind := internal.CatalogKind(chi.URLParam(r, "catalog"))
+		var body struct {
+			Description string `json:"description"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid body")
+			return
+		}
+
+		// process
+		e, err := h.sv.Update(r.Context(), kind, chi.URLParam(r, "value"), body.Description)
+		if err != nil {
+			writeCatalogError(w, err)
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "catalog entry updated successfully",
+			"data":    CatalogEntryJSON{Value: e.Value, Description: e.Description},
+		})
+	}
+}
+
+// Delete is a method that returns a handler for the route DELETE /catalogs/{catalog}/{value}
+func (h *CatalogDefault) Delete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		kind := internal.CatalogKind(chi.URLParam(r, "catalog"))
+
+		// process
+		if err := h.sv.Delete(r.Context(), kind, chi.URLParam(r, "value")); err != nil {
+			writeCatalogError(w, err)
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusNoContent, map[string]any{
+			"message": "catalog entry deleted successfully",
+		})
+	}
+}
+
+// writeCatalogError writes the response of an error of a catalog operation
+func writeCatalogError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, internal.ErrCatalogNotFound), errors.Is(err, internal.ErrCatalogEntryNotFound):
+		response.Text(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, internal.ErrCatalogEntryAlreadyExists), errors.Is(err, internal.ErrCatalogEntryInUse):
+		response.Text(w, http.StatusConflict, err.Error())
+	case errors.Is(err, internal.ErrCatalogEntryInvalid):
+		response.Text(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		response.Text(w, http.StatusGatewayTimeout, err.Error())
+	default:
+		response.Text(w, http.StatusInternalServerError, err.Error())
+	}
+}
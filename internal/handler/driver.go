@@ -0,0 +1,39 @@
+package handler
+
+import "app/internal"
+
+// DriverJSON is a struct that represents a driver in JSON format
+type DriverJSON struct {
+	ID            int    `json:"id"`
+	Name          string `json:"name"`
+	LicenseNumber string `json:"license_number"`
+	Email         string `json:"email"`
+	Phone         string `json:"phone"`
+}
+
+// NewDriverDefault is a function that returns a new instance of DriverDefault
+func NewDriverDefault(sv internal.DriverService) *DriverDefault {
+	return &DriverDefault{&personDefault[internal.Driver, DriverJSON]{
+		sv:          sv,
+		noun:        "driver",
+		newJSON:     newDriverJSON,
+		errNotFound: internal.ErrDriverNotFound,
+		errInUse:    internal.ErrDriverInUse,
+	}}
+}
+
+// DriverDefault is a struct with methods that represent handlers for drivers,
+// for the routes GET, POST /drivers and GET, PUT, DELETE /drivers/{id}
+type DriverDefault struct {
+	*personDefault[internal.Driver, DriverJSON]
+}
+
+// newDriverJSON converts a driver to JSON format
+func newDriverJSON(d internal.Driver) DriverJSON {
+	return DriverJSON{ID: d.Id, Name: d.Name, LicenseNumber: d.LicenseNumber, Email: d.Email, Phone: d.Phone}
+}
+
+// toDomain is a method that converts the driver with the given id to the domain
+func (d DriverJSON) toDomain(id int) internal.Driver {
+	return internal.Driver{Id: id, Name: d.Name, LicenseNumber: d.LicenseNumber, Email: d.Email, Phone: d.Phone}
+}
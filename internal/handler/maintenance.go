@@ -0,0 +1,358 @@
+package handler
+
+import (
+	"app/internal"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bootcamp-go/web/response"
+	"github.com/go-chi/chi/v5"
+)
+
+// MaintenanceRecordJSON is a struct that represents a maintenance record in JSON format, the odometer in the units of the request
+type MaintenanceRecordJSON struct {
+	ID        int       `json:"id"`
+	VehicleID int       `json:"vehicle_id"`
+	Date      time.Time `json:"date"`
+	Odometer  float64   `json:"odometer"`
+	Type      string    `json:"type"`
+	Cost      float64   `json:"cost"`
+	Notes     string    `json:"notes"`
+}
+
+// MaintenanceRecordCreateJSON is a struct that represents the body of POST /vehicles/{id}/maintenance/records,
+// date defaults to now
+type MaintenanceRecordCreateJSON struct {
+	Date     *time.Time `json:"date"`
+	Odometer *float64   `json:"odometer"`
+	Type     string     `json:"type"`
+	Cost     float64    `json:"cost"`
+	Notes    string     `json:"notes"`
+}
+
+// MaintenanceScheduleJSON is a struct that represents a maintenance schedule and its next service in JSON format,
+// the distances in the units of the request
+type MaintenanceScheduleJSON struct {
+	ID               int                    `json:"id"`
+	VehicleID        int                    `json:"vehicle_id"`
+	Type             string                 `json:"type"`
+	IntervalDays     int                    `json:"interval_days"`
+	IntervalDistance float64                `json:"interval_distance"`
+	Since            time.Time              `json:"since"`
+	SinceOdometer    float64                `json:"since_odometer"`
+	LastService      *MaintenanceRecordJSON `json:"last_service"`
+	DueDate          *time.Time             `json:"due_date"`
+	DueOdometer      *float64               `json:"due_odometer"`
+	CurrentOdometer  float64                `json:"current_odometer"`
+	Overdue          bool                   `json:"overdue"`
+}
+
+// MaintenanceScheduleCreateJSON is a struct that represents the body of POST /vehicles/{id}/maintenance/schedules,
+// since defaults to now and since_odometer to the last known odometer
+type MaintenanceScheduleCreateJSON struct {
+	Type             string     `json:"type"`
+	IntervalDays     int        `json:"interval_days"`
+	IntervalDistance float64    `json:"interval_distance"`
+	Since            *time.Time `json:"since"`
+	SinceOdometer    float64    `json:"since_odometer"`
+}
+
+// NewMaintenanceDefault is a function that returns a new instance of MaintenanceDefault
+func NewMaintenanceDefault(sv internal.MaintenanceService) *MaintenanceDefault {
+	return &MaintenanceDefault{sv: sv}
+}
+
+// MaintenanceDefault is a struct with methods that represent handlers for the maintenance of the vehicles
+type MaintenanceDefault struct {
+	// sv is the service that will be used by the handler
+	sv internal.MaintenanceService
+}
+
+// GetByVehicle is a method that returns a handler for the route GET /vehicles/{id}/maintenance
+func (h *MaintenanceDefault) GetByVehicle() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID format. ID must be an int number.")
+			return
+		}
+
+		// process
+		records, due, err := h.sv.FindByVehicle(r.Context(), id)
+		if err != nil {
+			writeMaintenanceError(w, err)
+			return
+		}
+
+		// response
+		data := make([]MaintenanceRecordJSON, len(records))
+		for i, record := range records {
+			data[i] = newMaintenanceRecordJSON(r, record)
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    map[string]any{"records": data, "schedules": newMaintenanceSchedulesJSON(r, due)},
+		})
+	}
+}
+
+// CreateRecord is a method that returns a handler for the route POST /vehicles/{id}/maintenance/records
+func (h *MaintenanceDefault) CreateRecord() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID format. ID must be an int number.")
+			return
+		}
+		var body MaintenanceRecordCreateJSON
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid body")
+			return
+		}
+		if body.Type == "" || body.Odometer == nil {
+			response.Text(w, http.StatusBadRequest, "invalid body. Keys are missing")
+			return
+		}
+		record := internal.MaintenanceRecord{
+			VehicleID: id,
+			Odometer:  inValue(r, "distance", *body.Odometer),
+			Type:      body.Type,
+			Cost:      body.Cost,
+			Notes:     body.Notes,
+		}
+		if body.Date != nil {
+			record.Date = *body.Date
+		}
+
+		// process
+		record, err = h.sv.CreateRecord(r.Context(), record)
+		if err != nil {
+			writeMaintenanceError(w, err)
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusCreated, map[string]any{
+			"message": "maintenance record created successfully",
+			"data":    newMaintenanceRecordJSON(r, record),
+		})
+	}
+}
+
+// DeleteRecord is a method that returns a handler for the route DELETE /vehicles/{id}/maintenance/records/{record_id}
+func (h *MaintenanceDefault) DeleteRecord() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID format. ID must be an int number.")
+			return
+		}
+		recordID, err := strconv.Atoi(chi.URLParam(r, "record_id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid record ID format. ID must be an int number.")
+			return
+		}
+
+		// process
+		if err := h.sv.DeleteRecord(r.Context(), id, recordID); err != nil {
+			writeMaintenanceError(w, err)
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusNoContent, map[string]any{
+			"message": "maintenance record deleted successfully",
+		})
+	}
+}
+
+// CreateSchedule is a method that returns a handler for the route POST /vehicles/{id}/maintenance/schedules
+func (h *MaintenanceDefault) CreateSchedule() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID format. ID must be an int number.")
+			return
+		}
+		var body MaintenanceScheduleCreateJSON
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid body")
+			return
+		}
+		if body.Type == "" {
+			response.Text(w, http.StatusBadRequest, "invalid body. Keys are missing")
+			return
+		}
+		schedule := internal.MaintenanceSchedule{
+			VehicleID:        id,
+			Type:             body.Type,
+			IntervalDays:     body.IntervalDays,
+			IntervalDistance: inValue(r, "distance", body.IntervalDistance),
+			SinceOdometer:    inValue(r, "distance", body.SinceOdometer),
+		}
+		if body.Since != nil {
+			schedule.Since = *body.Since
+		}
+
+		// process
+		schedule, err = h.sv.CreateSchedule(r.Context(), schedule)
+		if err != nil {
+			writeMaintenanceError(w, err)
+			return
+		}
+		// the next service of the schedule takes the past records of its type into account
+		_, due, err := h.sv.FindByVehicle(r.Context(), id)
+		if err != nil {
+			writeMaintenanceError(w, err)
+			return
+		}
+
+		// response
+		var data MaintenanceScheduleJSON
+		for _, d := range due {
+			if d.Schedule.Id == schedule.Id {
+				data = newMaintenanceScheduleJSON(r, d)
+			}
+		}
+		response.JSON(w, http.StatusCreated, map[string]any{
+			"message": "maintenance schedule created successfully",
+			"data":    data,
+		})
+	}
+}
+
+// DeleteSchedule is a method that returns a handler for the route DELETE /vehicles/{id}/maintenance/schedules/{schedule_id}
+func (h *MaintenanceDefault) DeleteSchedule() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID format. ID must be an int number.")
+			return
+		}
+		scheduleID, err := strconv.Atoi(chi.URLParam(r, "schedule_id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid schedule ID format. ID must be an int number.")
+			return
+		}
+
+		// process
+		if err := h.sv.DeleteSchedule(r.Context(), id, scheduleID); err != nil {
+			writeMaintenanceError(w, err)
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusNoContent, map[string]any{
+			"message": "maintenance schedule deleted successfully",
+		})
+	}
+}
+
+// GetDue is a method that returns a handler for the route GET /maintenance/due?days=30&distance=,
+// the distance in the units of the request
+func (h *MaintenanceDefault) GetDue() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		days := 30
+		if value := r.URL.Query().Get("days"); value != "" {
+			var err error
+			if days, err = strconv.Atoi(value); err != nil || days < 0 {
+				response.Text(w, http.StatusBadRequest, "invalid days value, it must be a positive int value")
+				return
+			}
+		}
+		var distance float64
+		if value := r.URL.Query().Get("distance"); value != "" {
+			var err error
+			if distance, err = strconv.ParseFloat(value, 64); err != nil || distance < 0 {
+				response.Text(w, http.StatusBadRequest, "invalid distance value, it must be a positive float64 value")
+				return
+			}
+		}
+
+		// process
+		due, err := h.sv.FindDue(r.Context(), time.Duration(days)*24*time.Hour, inValue(r, "distance", distance))
+		if err != nil {
+			writeMaintenanceError(w, err)
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    newMaintenanceSchedulesJSON(r, due),
+		})
+	}
+}
+
+// writeMaintenanceError writes the response of an error of a maintenance operation
+func writeMaintenanceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, internal.ErrMaintenanceRecordNotFound), errors.Is(err, internal.ErrMaintenanceScheduleNotFound),
+		errors.Is(err, internal.ErrVehicleNotFounded):
+		response.Text(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, internal.ErrInvalidMaintenance):
+		response.Text(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		response.Text(w, http.StatusGatewayTimeout, err.Error())
+	default:
+		response.Text(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// newMaintenanceRecordJSON converts a maintenance record to JSON format in the units of the request
+func newMaintenanceRecordJSON(r *http.Request, record internal.MaintenanceRecord) MaintenanceRecordJSON {
+	return MaintenanceRecordJSON{
+		ID:        record.Id,
+		VehicleID: record.VehicleID,
+		Date:      record.Date,
+		Odometer:  unitsOf(r).FromCanonical("distance", record.Odometer),
+		Type:      record.Type,
+		Cost:      record.Cost,
+		Notes:     record.Notes,
+	}
+}
+
+// newMaintenanceScheduleJSON converts a schedule and its next service to JSON format in the units of the request
+func newMaintenanceScheduleJSON(r *http.Request, d internal.MaintenanceDue) MaintenanceScheduleJSON {
+	u := unitsOf(r)
+	data := MaintenanceScheduleJSON{
+		ID:               d.Schedule.Id,
+		VehicleID:        d.Schedule.VehicleID,
+		Type:             d.Schedule.Type,
+		IntervalDays:     d.Schedule.IntervalDays,
+		IntervalDistance: u.FromCanonical("distance", d.Schedule.IntervalDistance),
+		Since:            d.Schedule.Since,
+		SinceOdometer:    u.FromCanonical("distance", d.Schedule.SinceOdometer),
+		DueDate:          d.Date,
+		CurrentOdometer:  u.FromCanonical("distance", d.CurrentOdometer),
+		Overdue:          d.Overdue,
+	}
+	if d.LastService != nil {
+		last := newMaintenanceRecordJSON(r, *d.LastService)
+		data.LastService = &last
+	}
+	if d.Odometer != nil {
+		odometer := u.FromCanonical("distance", *d.Odometer)
+		data.DueOdometer = &odometer
+	}
+	return data
+}
+
+// newMaintenanceSchedulesJSON converts the next services of schedules to JSON format in the units of the request
+func newMaintenanceSchedulesJSON(r *http.Request, due []internal.MaintenanceDue) []MaintenanceScheduleJSON {
+	data := make([]MaintenanceScheduleJSON, len(due))
+	for i, d := range due {
+		data[i] = newMaintenanceScheduleJSON(r, d)
+	}
+	return data
+}
@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"app/internal"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bootcamp-go/web/response"
+	"github.com/go-chi/chi/v5"
+)
+
+// OdometerReadingJSON is a struct that represents an odometer reading in JSON format, the value in the units of the request
+type OdometerReadingJSON struct {
+	ID          int       `json:"id"`
+	VehicleID   int       `json:"vehicle_id"`
+	Date        time.Time `json:"date"`
+	Value       float64   `json:"value"`
+	Replacement bool      `json:"replacement"`
+	Notes       string    `json:"notes"`
+}
+
+// OdometerReadingCreateJSON is a struct that represents the body of POST /vehicles/{id}/odometer,
+// date defaults to now and replacement allows a value lower than the previous reading
+type OdometerReadingCreateJSON struct {
+	Date        *time.Time `json:"date"`
+	Value       *float64   `json:"value"`
+	Replacement bool       `json:"replacement"`
+	Notes       string     `json:"notes"`
+}
+
+// OdometerUsageJSON is a struct that represents the usage of a vehicle in JSON format, the distances in the units of the request
+type OdometerUsageJSON struct {
+	Readings             int                      `json:"readings"`
+	First                *OdometerReadingJSON     `json:"first"`
+	Last                 *OdometerReadingJSON     `json:"last"`
+	Distance             float64                  `json:"distance"`
+	Days                 float64                  `json:"days"`
+	AverageDailyDistance float64                  `json:"average_daily_distance"`
+	NextService          *MaintenanceScheduleJSON `json:"next_service"`
+	NextServiceDate      *time.Time               `json:"next_service_date"`
+}
+
+// NewOdometerDefault is a function that returns a new instance of OdometerDefault
+func NewOdometerDefault(sv internal.OdometerService) *OdometerDefault {
+	return &OdometerDefault{sv: sv}
+}
+
+// OdometerDefault is a struct with methods that represent handlers for the odometer readings of the vehicles
+type OdometerDefault struct {
+	// sv is the service that will be used by the handler
+	sv internal.OdometerService
+}
+
+// GetByVehicle is a method that returns a handler for the route GET /vehicles/{id}/odometer?from=&to=
+func (h *OdometerDefault) GetByVehicle() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID format. ID must be an int number.")
+			return
+		}
+		from, to, err := parseTimeRange(r)
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// process
+		readings, err := h.sv.FindReadings(r.Context(), id, from, to)
+		if err != nil {
+			writeOdometerError(w, err)
+			return
+		}
+
+		// response
+		data := make([]OdometerReadingJSON, len(readings))
+		for i, reading := range readings {
+			data[i] = newOdometerReadingJSON(r, reading)
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    data,
+		})
+	}
+}
+
+// Create is a method that returns a handler for the route POST /vehicles/{id}/odometer
+func (h *OdometerDefault) Create() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID format. ID must be an int number.")
+			return
+		}
+		var body OdometerReadingCreateJSON
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid body")
+			return
+		}
+		if body.Value == nil {
+			response.Text(w, http.StatusBadRequest, "invalid body. Keys are missing")
+			return
+		}
+		reading := internal.OdometerReading{
+			VehicleID:   id,
+			Value:       inValue(r, "distance", *body.Value),
+			Replacement: body.Replacement,
+			Notes:       body.Notes,
+		}
+		if body.Date != nil {
+			reading.Date = *body.Date
+		}
+
+		// process
+		reading, err = h.sv.Create(r.Context(), reading)
+		if err != nil {
+			writeOdometerError(w, err)
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusCreated, map[string]any{
+			"message": "odometer reading created successfully",
+			"data":    newOdometerReadingJSON(r, reading),
+		})
+	}
+}
+
+// Usage is a method that returns a handler for the route GET /vehicles/{id}/usage?from=&to=
+func (h *OdometerDefault) Usage() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID format. ID must be an int number.")
+			return
+		}
+		from, to, err := parseTimeRange(r)
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// process
+		usage, err := h.sv.Usage(r.Context(), id, from, to)
+		if err != nil {
+			writeOdometerError(w, err)
+			return
+		}
+
+		// response
+		u := unitsOf(r)
+		data := OdometerUsageJSON{
+			Readings:             usage.Readings,
+			Distance:             u.FromCanonical("distance", usage.Distance),
+			Days:                 usage.Days,
+			AverageDailyDistance: u.FromCanonical("distance", usage.AverageDailyDistance),
+			NextServiceDate:      usage.NextServiceDate,
+		}
+		if usage.First != nil {
+			first, last := newOdometerReadingJSON(r, *usage.First), newOdometerReadingJSON(r, *usage.Last)
+			data.First, data.Last = &first, &last
+		}
+		if usage.NextService != nil {
+			next := newMaintenanceScheduleJSON(r, *usage.NextService)
+			data.NextService = &next
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    data,
+		})
+	}
+}
+
+// writeOdometerError writes the response of an error of an odometer operation
+func writeOdometerError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, internal.ErrVehicleNotFounded):
+		response.Text(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, internal.ErrOdometerDecreasing):
+		response.Text(w, http.StatusConflict, err.Error())
+	case errors.Is(err, internal.ErrInvalidOdometerReading):
+		response.Text(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		response.Text(w, http.StatusGatewayTimeout, err.Error())
+	default:
+		response.Text(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// newOdometerReadingJSON converts an odometer reading to JSON format in the units of the request
+func newOdometerReadingJSON(r *http.Request, reading internal.OdometerReading) OdometerReadingJSON {
+	return OdometerReadingJSON{
+		ID:          reading.Id,
+		VehicleID:   reading.VehicleID,
+		Date:        reading.Date,
+		Value:       unitsOf(r).FromCanonical("distance", reading.Value),
+		Replacement: reading.Replacement,
+		Notes:       reading.Notes,
+	}
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"app/internal/service"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestOdometerDefault_Create(t *testing.T) {
+	// the vehicle 1 read 1000 km two days ago
+	last := time.Now().UTC().Add(-48 * time.Hour).Truncate(time.Second)
+	date := func(d time.Duration) string { return last.Add(d).Format(time.RFC3339) }
+
+	cases := []struct {
+		name   string
+		target string
+		body   string
+		code   int
+	}{
+		{"increasing", "/vehicles/1/odometer", fmt.Sprintf(`{"date":%q,"value":1200}`, date(time.Hour)), http.StatusCreated},
+		{"now", "/vehicles/1/odometer", `{"value":1200}`, http.StatusCreated},
+		{"equal", "/vehicles/1/odometer", fmt.Sprintf(`{"date":%q,"value":1000}`, date(time.Hour)), http.StatusCreated},
+		{"decreasing", "/vehicles/1/odometer", fmt.Sprintf(`{"date":%q,"value":900}`, date(time.Hour)), http.StatusConflict},
+		{"decreasing after a replacement", "/vehicles/1/odometer", fmt.Sprintf(`{"date":%q,"value":10,"replacement":true}`, date(time.Hour)), http.StatusCreated},
+		{"backdated above the next reading", "/vehicles/1/odometer", fmt.Sprintf(`{"date":%q,"value":1100}`, date(-time.Hour)), http.StatusConflict},
+		{"backdated below the next reading", "/vehicles/1/odometer", fmt.Sprintf(`{"date":%q,"value":900}`, date(-time.Hour)), http.StatusCreated},
+		{"negative", "/vehicles/1/odometer", `{"value":-1}`, http.StatusBadRequest},
+		{"in the future", "/vehicles/1/odometer", fmt.Sprintf(`{"date":%q,"value":1200}`, date(72*time.Hour)), http.StatusBadRequest},
+		{"without value", "/vehicles/1/odometer", `{}`, http.StatusBadRequest},
+		{"unknown vehicle", "/vehicles/2/odometer", `{"value":10}`, http.StatusNotFound},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			rpVehicle := repository.NewVehicleMap(map[int]internal.Vehicle{1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford"}}})
+			rp := repository.NewOdometerMap()
+			if _, err := rp.Create(context.Background(), internal.OdometerReading{VehicleID: 1, Date: last, Value: 1000}); err != nil {
+				t.Fatal(err)
+			}
+			sv := service.NewOdometerDefault(rp, rpVehicle, service.NewMaintenanceDefault(repository.NewMaintenanceMap(), rpVehicle, rp))
+			hd := NewOdometerDefault(sv)
+			rt := chi.NewRouter()
+			rt.Post("/vehicles/{id}/odometer", hd.Create())
+
+			res := httptest.NewRecorder()
+			rt.ServeHTTP(res, httptest.NewRequest(http.MethodPost, c.target, strings.NewReader(c.body)))
+			if res.Code != c.code {
+				t.Fatalf("code = %d, want %d: %s", res.Code, c.code, res.Body.String())
+			}
+		})
+	}
+}
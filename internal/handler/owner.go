@@ -0,0 +1,38 @@
+package handler
+
+import "app/internal"
+
+// OwnerJSON is a struct that represents an owner in JSON format
+type OwnerJSON struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Phone string `json:"phone"`
+}
+
+// NewOwnerDefault is a function that returns a new instance of OwnerDefault
+func NewOwnerDefault(sv internal.OwnerService) *OwnerDefault {
+	return &OwnerDefault{&personDefault[internal.Owner, OwnerJSON]{
+		sv:          sv,
+		noun:        "owner",
+		newJSON:     newOwnerJSON,
+		errNotFound: internal.ErrOwnerNotFound,
+		errInUse:    internal.ErrOwnerInUse,
+	}}
+}
+
+// OwnerDefault is a struct with methods that represent handlers for owners,
+// for the routes GET, POST /owners and GET, PUT, DELETE /owners/{id}
+type OwnerDefault struct {
+	*personDefault[internal.Owner, OwnerJSON]
+}
+
+// newOwnerJSON converts an owner to JSON format
+func newOwnerJSON(o internal.Owner) OwnerJSON {
+	return OwnerJSON{ID: o.Id, Name: o.Name, Email: o.Email, Phone: o.Phone}
+}
+
+// toDomain is a method that converts the owner with the given id to the domain
+func (o OwnerJSON) toDomain(id int) internal.Owner {
+	return internal.Owner{Id: id, Name: o.Name, Email: o.Email, Phone: o.Phone}
+}
@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"app/internal"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/bootcamp-go/web/response"
+	"github.com/go-chi/chi/v5"
+)
+
+// personService is an interface that represents a service of people, met by the owner and driver ones
+type personService[T any] interface {
+	FindAll(ctx context.Context) (p []T, err error)
+	FindOne(ctx context.Context, id int) (p T, err error)
+	Create(ctx context.Context, person T) (p T, err error)
+	Update(ctx context.Context, person T) (p T, err error)
+	Delete(ctx context.Context, id int) (err error)
+}
+
+// personJSON is an interface that represents a person in JSON format
+type personJSON[T any] interface {
+	// toDomain converts the person with the given id to the domain
+	toDomain(id int) T
+}
+
+// personDefault is a struct with methods that represent handlers for people, shared by owners and drivers
+type personDefault[T any, J personJSON[T]] struct {
+	// sv is the service that will be used by the handler
+	sv personService[T]
+	// noun names a person in the messages
+	noun string
+	// newJSON converts a person to JSON format
+	newJSON func(p T) J
+	// errNotFound and errInUse are the errors of the service for a missing person and a person with vehicles
+	errNotFound, errInUse error
+}
+
+// GetAll is a method that returns a handler for the route GET of the people
+func (h *personDefault[T, J]) GetAll() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// process
+		people, err := h.sv.FindAll(r.Context())
+		if err != nil {
+			h.writeError(w, err)
+			return
+		}
+
+		// response
+		data := make([]J, len(people))
+		for i, p := range people {
+			data[i] = h.newJSON(p)
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    data,
+		})
+	}
+}
+
+// GetByID is a method that returns a handler for the route GET of a person by {id}
+func (h *personDefault[T, J]) GetByID() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID format. ID must be an int number.")
+			return
+		}
+
+		// process
+		p, err := h.sv.FindOne(r.Context(), id)
+		if err != nil {
+			h.writeError(w, err)
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    h.newJSON(p),
+		})
+	}
+}
+
+// Create is a method that returns a handler for the route POST of the people
+func (h *personDefault[T, J]) Create() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		var body J
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid body")
+			return
+		}
+
+		// process, the id is given by the repository
+		p, err := h.sv.Create(r.Context(), body.toDomain(0))
+		if err != nil {
+			h.writeError(w, err)
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusCreated, map[string]any{
+			"message": h.noun + " created successfully",
+			"data":    h.newJSON(p),
+		})
+	}
+}
+
+// Update is a method that returns a handler for the route PUT of a person by {id}
+func (h *personDefault[T, J]) Update() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID format. ID must be an int number.")
+			return
+		}
+		var body J
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid body")
+			return
+		}
+
+		// process
+		p, err := h.sv.Update(r.Context(), body.toDomain(id))
+		if err != nil {
+			h.writeError(w, err)
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": h.noun + " updated successfully",
+			"data":    h.newJSON(p),
+		})
+	}
+}
+
+// Delete is a method that returns a handler for the route DELETE of a person by {id}
+func (h *personDefault[T, J]) Delete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID format. ID must be an int number.")
+			return
+		}
+
+		// process
+		if err := h.sv.Delete(r.Context(), id); err != nil {
+			h.writeError(w, err)
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusNoContent, map[string]any{
+			"message": h.noun + " deleted successfully",
+		})
+	}
+}
+
+// writeError writes the response of an error of a person operation
+func (h *personDefault[T, J]) writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, h.errNotFound):
+		response.Text(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, h.errInUse):
+		response.Text(w, http.StatusConflict, err.Error())
+	case errors.Is(err, internal.ErrInvalidName):
+		response.Text(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		response.Text(w, http.StatusGatewayTimeout, err.Error())
+	default:
+		response.Text(w, http.StatusInternalServerError, err.Error())
+	}
+}
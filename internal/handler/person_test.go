@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"app/internal/service"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestOwnerDefault(t *testing.T) {
+	cases := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		code   int
+		// want is a part of the body of the response
+		want string
+	}{
+		{"list", http.MethodGet, "/owners", "", http.StatusOK, `"name":"Ann"`},
+		{"get", http.MethodGet, "/owners/1", "", http.StatusOK, `"id":1`},
+		{"get unknown", http.MethodGet, "/owners/3", "", http.StatusNotFound, "owner not found"},
+		{"get invalid id", http.MethodGet, "/owners/a", "", http.StatusBadRequest, "Invalid ID"},
+		{"create", http.MethodPost, "/owners", `{"id":9,"name":" Bob "}`, http.StatusCreated, `"id":3,"name":"Bob"`},
+		{"create without name", http.MethodPost, "/owners", `{"email":"a@b.c"}`, http.StatusBadRequest, "name"},
+		{"create invalid body", http.MethodPost, "/owners", `{`, http.StatusBadRequest, "invalid body"},
+		{"update", http.MethodPut, "/owners/1", `{"id":2,"name":"Annie"}`, http.StatusOK, `"id":1,"name":"Annie"`},
+		{"update unknown", http.MethodPut, "/owners/3", `{"name":"Annie"}`, http.StatusNotFound, "owner not found"},
+		{"delete", http.MethodDelete, "/owners/2", "", http.StatusNoContent, ""},
+		{"delete with vehicles", http.MethodDelete, "/owners/1", "", http.StatusConflict, "owner has current or upcoming vehicles"},
+		{"delete unknown", http.MethodDelete, "/owners/3", "", http.StatusNotFound, "owner not found"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			rpAssignment := repository.NewAssignmentMap()
+			rpAssignment.Create(context.Background(), internal.Assignment{VehicleID: 1, Role: internal.AssignmentOwner, PersonID: 1})
+			rpOwner := repository.NewOwnerMap(map[int]internal.Owner{1: {Id: 1, Name: "Ann"}, 2: {Id: 2, Name: "Ed"}})
+			hd := NewOwnerDefault(service.NewOwnerDefault(rpOwner, rpAssignment, nil))
+			rt := chi.NewRouter()
+			rt.Get("/owners", hd.GetAll())
+			rt.Post("/owners", hd.Create())
+			rt.Get("/owners/{id}", hd.GetByID())
+			rt.Put("/owners/{id}", hd.Update())
+			rt.Delete("/owners/{id}", hd.Delete())
+
+			res := httptest.NewRecorder()
+			rt.ServeHTTP(res, httptest.NewRequest(c.method, c.target, strings.NewReader(c.body)))
+			if res.Code != c.code || !strings.Contains(res.Body.String(), c.want) {
+				t.Fatalf("code = %d, want %d: %s", res.Code, c.code, res.Body.String())
+			}
+		})
+	}
+}
+
+func TestDriverDefault(t *testing.T) {
+	hd := NewDriverDefault(service.NewDriverDefault(repository.NewDriverMap(nil), repository.NewAssignmentMap(), nil))
+	rt := chi.NewRouter()
+	rt.Post("/drivers", hd.Create())
+	rt.Get("/drivers/{id}", hd.GetByID())
+
+	res := httptest.NewRecorder()
+	rt.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/drivers", strings.NewReader(`{"name":"Bob","license_number":"B-1"}`)))
+	if res.Code != http.StatusCreated || !strings.Contains(res.Body.String(), "driver created successfully") {
+		t.Fatalf("code = %d: %s", res.Code, res.Body.String())
+	}
+
+	res = httptest.NewRecorder()
+	rt.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/drivers/1", nil))
+	if res.Code != http.StatusOK || !strings.Contains(res.Body.String(), `"license_number":"B-1"`) {
+		t.Fatalf("code = %d: %s", res.Code, res.Body.String())
+	}
+}
@@ -0,0 +1,313 @@
+package handler
+
+import (
+	"app/internal"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bootcamp-go/web/response"
+	"github.com/go-chi/chi/v5"
+)
+
+// ReservationJSON is a struct that represents a reservation in JSON format
+type ReservationJSON struct {
+	ID          int        `json:"id"`
+	VehicleID   int        `json:"vehicle_id"`
+	From        time.Time  `json:"from"`
+	To          time.Time  `json:"to"`
+	ReservedBy  string     `json:"reserved_by"`
+	Purpose     string     `json:"purpose"`
+	Passengers  int        `json:"passengers"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CancelledAt *time.Time `json:"cancelled_at"`
+}
+
+// ReservationCreateJSON is a struct that represents the body of POST /vehicles/{id}/reservations,
+// reserved_by defaults to the caller
+type ReservationCreateJSON struct {
+	From       *time.Time `json:"from"`
+	To         *time.Time `json:"to"`
+	ReservedBy string     `json:"reserved_by"`
+	Purpose    string     `json:"purpose"`
+	Passengers int        `json:"passengers"`
+}
+
+// NewReservationDefault is a function that returns a new instance of ReservationDefault
+func NewReservationDefault(sv internal.ReservationService) *ReservationDefault {
+	return &ReservationDefault{sv: sv}
+}
+
+// ReservationDefault is a struct with methods that represent handlers for the reservations of the vehicles
+type ReservationDefault struct {
+	// sv is the service that will be used by the handler
+	sv internal.ReservationService
+}
+
+// GetByVehicle is a method that returns a handler for the route GET /vehicles/{id}/reservations?from=&to=
+func (h *ReservationDefault) GetByVehicle() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID format. ID must be an int number.")
+			return
+		}
+		from, to, err := parseTimeRange(r)
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// process
+		reservations, err := h.sv.FindByVehicle(r.Context(), id, from, to)
+		if err != nil {
+			writeReservationError(w, err)
+			return
+		}
+
+		// response
+		data := make([]ReservationJSON, len(reservations))
+		for i, reservation := range reservations {
+			data[i] = newReservationJSON(reservation)
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    data,
+		})
+	}
+}
+
+// Create is a method that returns a handler for the route POST /vehicles/{id}/reservations
+func (h *ReservationDefault) Create() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID format. ID must be an int number.")
+			return
+		}
+		var body ReservationCreateJSON
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid body")
+			return
+		}
+		if body.From == nil || body.To == nil {
+			response.Text(w, http.StatusBadRequest, "invalid body. Keys are missing")
+			return
+		}
+
+		// process
+		reservation, err := h.sv.Create(r.Context(), internal.Reservation{
+			VehicleID:  id,
+			From:       *body.From,
+			To:         *body.To,
+			ReservedBy: body.ReservedBy,
+			Purpose:    body.Purpose,
+			Passengers: body.Passengers,
+		})
+		if err != nil {
+			writeReservationError(w, err)
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusCreated, map[string]any{
+			"message": "reservation created successfully",
+			"data":    newReservationJSON(reservation),
+		})
+	}
+}
+
+// Cancel is a method that returns a handler for the route POST /vehicles/{id}/reservations/{reservation_id}/cancel
+func (h *ReservationDefault) Cancel() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID format. ID must be an int number.")
+			return
+		}
+		reservationID, err := strconv.Atoi(chi.URLParam(r, "reservation_id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid reservation ID format. ID must be an int number.")
+			return
+		}
+
+		// process
+		reservation, err := h.sv.Cancel(r.Context(), id, reservationID)
+		if err != nil {
+			writeReservationError(w, err)
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "reservation cancelled successfully",
+			"data":    newReservationJSON(reservation),
+		})
+	}
+}
+
+// Available is a method that returns a handler for the route GET /vehicles/available?from=&to=&passengers=
+// plus the filter query params
+func (h *ReservationDefault) Available() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		from, to, err := parseTimeRange(r)
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if from.IsZero() || to.IsZero() {
+			response.Text(w, http.StatusBadRequest, "from and to are required")
+			return
+		}
+		filter, err := parseFilterQuery(r)
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// process
+		vehicles, err := h.sv.Available(r.Context(), filter, from, to)
+		if err != nil {
+			writeReservationError(w, err)
+			return
+		}
+
+		// response
+		data := make([]VehicleJSON, len(vehicles))
+		for i, v := range vehicles {
+			data[i] = newVehicleJSON(outVehicle(r, v))
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    data,
+		})
+	}
+}
+
+// Calendar is a method that returns a handler for the route GET /vehicles/{id}/reservations.ics,
+// the reservations of the vehicle in iCalendar format (RFC 5545)
+func (h *ReservationDefault) Calendar() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID format. ID must be an int number.")
+			return
+		}
+
+		// process
+		reservations, err := h.sv.FindByVehicle(r.Context(), id, time.Time{}, time.Time{})
+		if err != nil {
+			writeReservationError(w, err)
+			return
+		}
+
+		// response
+		w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="vehicle-%d.ics"`, id))
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(newICalendar(id, reservations)))
+	}
+}
+
+// writeReservationError writes the response of an error of a reservation operation
+func writeReservationError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, internal.ErrReservationNotFound), errors.Is(err, internal.ErrVehicleNotFounded):
+		response.Text(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, internal.ErrReservationConflict):
+		response.Text(w, http.StatusConflict, err.Error())
+	case errors.Is(err, internal.ErrInvalidReservation):
+		response.Text(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		response.Text(w, http.StatusGatewayTimeout, err.Error())
+	default:
+		response.Text(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// newReservationJSON converts a reservation to JSON format
+func newReservationJSON(r internal.Reservation) ReservationJSON {
+	return ReservationJSON{
+		ID:          r.Id,
+		VehicleID:   r.VehicleID,
+		From:        r.From,
+		To:          r.To,
+		ReservedBy:  r.ReservedBy,
+		Purpose:     r.Purpose,
+		Passengers:  r.Passengers,
+		CreatedAt:   r.CreatedAt,
+		CancelledAt: r.CancelledAt,
+	}
+}
+
+// newICalendar serializes the reservations of a vehicle as an iCalendar, cancelled ones with the CANCELLED status
+func newICalendar(vehicleID int, reservations []internal.Reservation) string {
+	const stamp = "20060102T150405Z"
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//vehicles//reservations//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:" + icalText(fmt.Sprintf("Vehicle %d reservations", vehicleID)),
+	}
+	for _, r := range reservations {
+		status, stamped := "CONFIRMED", r.CreatedAt
+		if r.CancelledAt != nil {
+			status, stamped = "CANCELLED", *r.CancelledAt
+		}
+		summary := "Reserved by " + r.ReservedBy
+		if r.Purpose != "" {
+			summary += ": " + r.Purpose
+		}
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			fmt.Sprintf("UID:reservation-%d@vehicles", r.Id),
+			"DTSTAMP:"+stamped.UTC().Format(stamp),
+			"DTSTART:"+r.From.UTC().Format(stamp),
+			"DTEND:"+r.To.UTC().Format(stamp),
+			"SUMMARY:"+icalText(summary),
+			"DESCRIPTION:"+icalText(fmt.Sprintf("Vehicle %d, %d passengers", r.VehicleID, r.Passengers)),
+			"STATUS:"+status,
+			"END:VEVENT",
+		)
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(icalFold(line))
+		b.WriteString("\r\n")
+	}
+	return b.String()
+}
+
+// icalText escapes a text value of an iCalendar property
+func icalText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// icalFold splits a content line longer than 75 octets into continuation lines, without breaking UTF-8 characters
+func icalFold(line string) string {
+	var b strings.Builder
+	size := 0
+	for _, c := range line {
+		if n := len(string(c)); size+n > 75 {
+			b.WriteString("\r\n ")
+			size = 1
+		}
+		b.WriteRune(c)
+		size += len(string(c))
+	}
+	return b.String()
+}
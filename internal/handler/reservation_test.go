@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"app/internal/service"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestReservationDefault_Create(t *testing.T) {
+	from := time.Now().UTC().Add(24 * time.Hour).Truncate(time.Hour)
+	body := func(from, to time.Time) string {
+		return fmt.Sprintf(`{"from":%q,"to":%q,"reserved_by":"bob"}`, from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+
+	cases := []struct {
+		name   string
+		target string
+		body   string
+		code   int
+	}{
+		{"free", "/vehicles/1/reservations", body(from.Add(2*time.Hour), from.Add(3*time.Hour)), http.StatusCreated},
+		{"overlapping", "/vehicles/1/reservations", body(from.Add(time.Hour), from.Add(3*time.Hour)), http.StatusConflict},
+		{"reversed range", "/vehicles/1/reservations", body(from.Add(3*time.Hour), from.Add(2*time.Hour)), http.StatusBadRequest},
+		{"without range", "/vehicles/1/reservations", `{"reserved_by":"bob"}`, http.StatusBadRequest},
+		{"invalid body", "/vehicles/1/reservations", `{"from":`, http.StatusBadRequest},
+		{"unknown vehicle", "/vehicles/2/reservations", body(from, from.Add(time.Hour)), http.StatusNotFound},
+		{"invalid id", "/vehicles/a/reservations", body(from, from.Add(time.Hour)), http.StatusBadRequest},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			rpVehicle := repository.NewVehicleMap(map[int]internal.Vehicle{
+				1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", Capacity: 4}, Status: internal.VehicleStatusInService},
+			})
+			sv := service.NewReservationDefault(repository.NewReservationMap(), rpVehicle, nil)
+			hd := NewReservationDefault(sv)
+			rt := chi.NewRouter()
+			rt.Post("/vehicles/{id}/reservations", hd.Create())
+
+			// the vehicle 1 is reserved for the first two hours
+			res := httptest.NewRecorder()
+			rt.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/vehicles/1/reservations", strings.NewReader(body(from, from.Add(2*time.Hour)))))
+			if res.Code != http.StatusCreated {
+				t.Fatalf("code = %d: %s", res.Code, res.Body.String())
+			}
+
+			res = httptest.NewRecorder()
+			rt.ServeHTTP(res, httptest.NewRequest(http.MethodPost, c.target, strings.NewReader(c.body)))
+			if res.Code != c.code {
+				t.Fatalf("code = %d, want %d: %s", res.Code, c.code, res.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"app/internal"
+	"net/http"
+)
+
+// unitsOf returns the unit system of the request
+func unitsOf(r *http.Request) internal.UnitSystem {
+	return internal.UnitsFromContext(r.Context())
+}
+
+// outVehicle converts a stored vehicle to the unit system of the request
+func outVehicle(r *http.Request, v internal.Vehicle) internal.Vehicle {
+	return unitsOf(r).FromCanonicalVehicle(v)
+}
+
+// outVehicles converts stored vehicles to the unit system of the request
+func outVehicles(r *http.Request, v map[int]internal.Vehicle) map[int]internal.Vehicle {
+	out := make(map[int]internal.Vehicle, len(v))
+	for key, value := range v {
+		out[key] = outVehicle(r, value)
+	}
+	return out
+}
+
+// inVehicle converts a vehicle in the unit system of the request to metric
+func inVehicle(r *http.Request, v internal.Vehicle) internal.Vehicle {
+	return unitsOf(r).ToCanonicalVehicle(v)
+}
+
+// inValue converts a value of a measured attribute in the unit system of the request to metric
+func inValue(r *http.Request, field string, value float64) float64 {
+	return unitsOf(r).ToCanonical(field, value)
+}
+
+// inOptional converts an optional value of a measured attribute to metric
+func inOptional(r *http.Request, field string, value *float64) *float64 {
+	if value == nil {
+		return nil
+	}
+	v := inValue(r, field, *value)
+	return &v
+}
+
+// inFilter converts the ranges of a filter in the unit system of the request to metric
+func inFilter(r *http.Request, f internal.VehicleFilter) internal.VehicleFilter {
+	f.MinLength, f.MaxLength = inOptional(r, "length", f.MinLength), inOptional(r, "length", f.MaxLength)
+	f.MinWidth, f.MaxWidth = inOptional(r, "width", f.MinWidth), inOptional(r, "width", f.MaxWidth)
+	f.MinWeight, f.MaxWeight = inOptional(r, "weight", f.MinWeight), inOptional(r, "weight", f.MaxWeight)
+	return f
+}
+
+// inPatch converts the measures of a patch in the unit system of the request to metric
+func inPatch(r *http.Request, p internal.VehiclePatch) internal.VehiclePatch {
+	p.MaxSpeed = inOptional(r, "max_speed", p.MaxSpeed)
+	p.Weight = inOptional(r, "weight", p.Weight)
+	p.Height = inOptional(r, "height", p.Height)
+	p.Length = inOptional(r, "length", p.Length)
+	p.Width = inOptional(r, "width", p.Width)
+	return p
+}
+
+// outModel converts the default specs of a stored model to the unit system of the request
+func outModel(r *http.Request, m internal.VehicleModel) internal.VehicleModel {
+	u := unitsOf(r)
+	m.MaxSpeed = u.FromCanonical("max_speed", m.MaxSpeed)
+	m.Weight = u.FromCanonical("weight", m.Weight)
+	m.Height = u.FromCanonical("height", m.Height)
+	m.Length = u.FromCanonical("length", m.Length)
+	m.Width = u.FromCanonical("width", m.Width)
+	return m
+}
+
+// inModel converts the default specs of a model in the unit system of the request to metric
+func inModel(r *http.Request, m internal.VehicleModel) internal.VehicleModel {
+	u := unitsOf(r)
+	m.MaxSpeed = u.ToCanonical("max_speed", m.MaxSpeed)
+	m.Weight = u.ToCanonical("weight", m.Weight)
+	m.Height = u.ToCanonical("height", m.Height)
+	m.Length = u.ToCanonical("length", m.Length)
+	m.Width = u.ToCanonical("width", m.Width)
+	return m
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"app/internal"
+	"context"
 	"encoding/json"
 	"errors"
 	"github.com/go-chi/chi/v5"
@@ -9,72 +10,238 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/bootcamp-go/web/response"
 )
 
 // VehicleJSON is a struct that represents a vehicle in JSON format
 type VehicleJSON struct {
-	ID              int     `json:"id"`
-	Brand           string  `json:"brand"`
-	Model           string  `json:"model"`
-	Registration    string  `json:"registration"`
-	Color           string  `json:"color"`
-	FabricationYear int     `json:"year"`
-	Capacity        int     `json:"passengers"`
-	MaxSpeed        float64 `json:"max_speed"`
-	FuelType        string  `json:"fuel_type"`
-	Transmission    string  `json:"transmission"`
-	Weight          float64 `json:"weight"`
-	Height          float64 `json:"height"`
-	Length          float64 `json:"length"`
-	Width           float64 `json:"width"`
+	ID              int        `json:"id"`
+	Brand           string     `json:"brand"`
+	Model           string     `json:"model"`
+	Registration    string     `json:"registration"`
+	VIN             string     `json:"vin"`
+	Color           string     `json:"color"`
+	FabricationYear int        `json:"year"`
+	Capacity        int        `json:"passengers"`
+	MaxSpeed        float64    `json:"max_speed"`
+	FuelType        string     `json:"fuel_type"`
+	Transmission    string     `json:"transmission"`
+	Weight          float64    `json:"weight"`
+	Height          float64    `json:"height"`
+	Length          float64    `json:"length"`
+	Width           float64    `json:"width"`
+	Status          string     `json:"status"`
+	DeletedAt       *time.Time `json:"deleted_at,omitempty"`
+}
+
+// VehicleRevisionJSON is a struct that represents a revision of a vehicle in JSON format
+type VehicleRevisionJSON struct {
+	Version   int                      `json:"version"`
+	Timestamp time.Time                `json:"timestamp"`
+	Deleted   bool                     `json:"deleted"`
+	Vehicle   VehicleJSON              `json:"vehicle"`
+	Changes   []VehicleFieldChangeJSON `json:"changes"`
+}
+
+// VehicleFieldChangeJSON is a struct that represents the change of an attribute in JSON format
+type VehicleFieldChangeJSON struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
 }
 
 // NewVehicleDefault is a function that returns a new instance of VehicleDefault
-func NewVehicleDefault(sv internal.VehicleService) *VehicleDefault {
-	return &VehicleDefault{sv: sv}
+func NewVehicleDefault(sv internal.VehicleService, sa internal.AssignmentService) *VehicleDefault {
+	return &VehicleDefault{sv: sv, sa: sa}
 }
 
 // VehicleDefault is a struct with methods that represent handlers for vehicles
 type VehicleDefault struct {
 	// sv is the service that will be used by the handler
 	sv internal.VehicleService
+	// sa is the service of the owners and drivers of the vehicles, used by the listing filters (nil disables them)
+	sa internal.AssignmentService
 }
 
-// GetAll is a method that returns a handler for the route GET /vehicles
+// GetAll is a method that returns a handler for the route GET /vehicles?as_of=&owner_id=&driver_id=&unassigned=&status=,
+// the owners and drivers are the ones at as_of. status takes a comma separated list, counts are computed before it applies
 func (h *VehicleDefault) GetAll() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// request
-		// ...
+		asOf, err := parseAsOf(r)
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		assigned, err := parseAssignmentQuery(r)
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		assigned.At = asOf
+		statuses := make(map[internal.VehicleStatus]bool)
+		for _, s := range parseList(r, "status") {
+			status, err := internal.ParseVehicleStatus(s)
+			if err != nil {
+				response.Text(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			statuses[status] = true
+		}
 
 		// process
-		// - get all vehicles
-		v, err := h.sv.FindAll()
+		// - get all vehicles, at a past moment if requested
+		var v map[int]internal.Vehicle
+		if asOf.IsZero() {
+			v, err = h.sv.FindAll(r.Context())
+		} else {
+			v, err = h.sv.FindAllAsOf(r.Context(), asOf)
+		}
+		// - keep the ones of the requested owner or driver
+		if err == nil && h.sa != nil {
+			v, err = h.sa.FilterVehicles(r.Context(), v, assigned)
+		}
 		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				response.Text(w, http.StatusGatewayTimeout, err.Error())
+				return
+			}
 			response.JSON(w, http.StatusInternalServerError, nil)
 			return
 		}
 
+		// - count the vehicles by status and keep the requested ones
+		counts := make(map[internal.VehicleStatus]int, len(internal.VehicleStatuses))
+		for _, status := range internal.VehicleStatuses {
+			counts[status] = 0
+		}
+		for key, value := range v {
+			counts[value.Status]++
+			if len(statuses) > 0 && !statuses[value.Status] {
+				delete(v, key)
+			}
+		}
+
 		// response
 		data := make(map[int]VehicleJSON)
 		for key, value := range v {
-			data[key] = VehicleJSON{
-				ID:              value.Id,
-				Brand:           value.Brand,
-				Model:           value.Model,
-				Registration:    value.Registration,
-				Color:           value.Color,
-				FabricationYear: value.FabricationYear,
-				Capacity:        value.Capacity,
-				MaxSpeed:        value.MaxSpeed,
-				FuelType:        value.FuelType,
-				Transmission:    value.Transmission,
-				Weight:          value.Weight,
-				Height:          value.Height,
-				Length:          value.Length,
-				Width:           value.Width,
+			data[key] = newVehicleJSON(outVehicle(r, value))
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "success",
+			"counts":  counts,
+			"data":    data,
+		})
+	}
+}
+
+// GetByID is a method that returns a handler for the route GET /vehicles/{id}?as_of=
+func (h *VehicleDefault) GetByID() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID format. ID must be an int number.")
+			return
+		}
+		asOf, err := parseAsOf(r)
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// process
+		var v internal.Vehicle
+		if asOf.IsZero() {
+			v, err = h.sv.FindOne(r.Context(), id)
+		} else {
+			v, err = h.sv.FindOneAsOf(r.Context(), id, asOf)
+		}
+		if err != nil {
+			switch {
+			case errors.Is(err, context.DeadlineExceeded):
+				response.Text(w, http.StatusGatewayTimeout, err.Error())
+			default:
+				response.Text(w, http.StatusNotFound, err.Error())
+			}
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    newVehicleJSON(outVehicle(r, v)),
+		})
+	}
+}
+
+// History is a method that returns a handler for the route GET /vehicles/{id}/history
+func (h *VehicleDefault) History() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID format. ID must be an int number.")
+			return
+		}
+
+		// process
+		revisions, err := h.sv.FindHistory(r.Context(), id)
+		if err != nil {
+			switch {
+			case errors.Is(err, context.DeadlineExceeded):
+				response.Text(w, http.StatusGatewayTimeout, err.Error())
+			default:
+				response.Text(w, http.StatusNotFound, err.Error())
+			}
+			return
+		}
+
+		// response
+		data := make([]VehicleRevisionJSON, len(revisions))
+		for i, rev := range revisions {
+			data[i] = VehicleRevisionJSON{
+				Version:   rev.Version,
+				Timestamp: rev.Timestamp,
+				Deleted:   rev.Deleted,
+				Vehicle:   newVehicleJSON(outVehicle(r, rev.Vehicle)),
+				Changes:   make([]VehicleFieldChangeJSON, 0),
+			}
+			if i == 0 {
+				continue
+			}
+			for _, c := range internal.DiffVehicles(outVehicle(r, revisions[i-1].Vehicle), outVehicle(r, rev.Vehicle)) {
+				data[i].Changes = append(data[i].Changes, VehicleFieldChangeJSON{Field: c.Field, From: c.From, To: c.To})
+			}
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "history found",
+			"data":    data,
+		})
+	}
+}
+
+// Trash is a method that returns a handler for the route GET /vehicles/trash
+func (h *VehicleDefault) Trash() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// process
+		v, err := h.sv.FindTrash(r.Context())
+		if err != nil {
+			switch {
+			case errors.Is(err, context.DeadlineExceeded):
+				response.Text(w, http.StatusGatewayTimeout, err.Error())
+			default:
+				response.Text(w, http.StatusInternalServerError, err.Error())
 			}
+			return
+		}
+
+		// response
+		data := make(map[int]VehicleJSON)
+		for key, value := range v {
+			data[key] = newVehicleJSON(outVehicle(r, value))
 		}
 		response.JSON(w, http.StatusOK, map[string]any{
 			"message": "success",
@@ -83,6 +250,40 @@ func (h *VehicleDefault) GetAll() http.HandlerFunc {
 	}
 }
 
+// Restore is a method that returns a handler for the route POST /vehicles/{id}/restore
+func (h *VehicleDefault) Restore() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID format. ID must be an int number.")
+			return
+		}
+
+		// process
+		vehicleRestored, err := h.sv.Restore(r.Context(), id)
+		if err != nil {
+			switch {
+			case errors.Is(err, internal.ErrVehicleNotInTrash):
+				response.Text(w, http.StatusNotFound, err.Error())
+			case errors.Is(err, internal.ErrRegistrationAlreadyExists), errors.Is(err, internal.ErrVINAlreadyExists):
+				response.Text(w, http.StatusConflict, err.Error())
+			case errors.Is(err, context.DeadlineExceeded):
+				response.Text(w, http.StatusGatewayTimeout, err.Error())
+			default:
+				response.Text(w, http.StatusInternalServerError, err.Error())
+			}
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "vehicle restored successfully",
+			"data":    newVehicleJSON(outVehicle(r, vehicleRestored)),
+		})
+	}
+}
+
 func (h *VehicleDefault) CreateVehicle() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// request
@@ -111,22 +312,32 @@ func (h *VehicleDefault) CreateVehicle() http.HandlerFunc {
 		}
 
 		// Error handling
-		if err := h.sv.CreateVehicle(vehicle); err != nil {
+		vehicle = inVehicle(r, vehicle)
+		if err := h.sv.CreateVehicle(r.Context(), vehicle); err != nil {
 			switch {
 			case errors.Is(err, internal.ErrInvalidBody):
 				response.Text(w, http.StatusBadRequest, err.Error())
-			case errors.Is(err, internal.ErrCarAlreadyExists):
+			case errors.Is(err, internal.ErrCarAlreadyExists), errors.Is(err, internal.ErrRegistrationAlreadyExists), errors.Is(err, internal.ErrVINAlreadyExists):
 				response.Text(w, http.StatusConflict, err.Error())
+			case errors.Is(err, internal.ErrNotInCatalog), errors.Is(err, internal.ErrNotInRegistry), errors.Is(err, internal.ErrInvalidRegistration),
+				errors.Is(err, internal.ErrInvalidVIN), errors.Is(err, internal.ErrVINMismatch):
+				response.Text(w, http.StatusUnprocessableEntity, err.Error())
+			case errors.Is(err, context.DeadlineExceeded):
+				response.Text(w, http.StatusGatewayTimeout, err.Error())
+			default:
+				response.Text(w, http.StatusInternalServerError, err.Error())
 			}
 			return
 		}
 
 		// response
+		vehicle = outVehicle(r, vehicle)
 		data := VehicleJSON{
 			ID:              vehicle.Id,
 			Brand:           vehicle.Brand,
 			Model:           vehicle.Model,
 			Registration:    vehicle.Registration,
+			VIN:             vehicle.VIN,
 			Color:           vehicle.Color,
 			FabricationYear: vehicle.FabricationYear,
 			Capacity:        vehicle.Capacity,
@@ -137,6 +348,7 @@ func (h *VehicleDefault) CreateVehicle() http.HandlerFunc {
 			Height:          vehicle.Height,
 			Length:          vehicle.Length,
 			Width:           vehicle.Width,
+			Status:          string(internal.VehicleStatusInService),
 		}
 
 		response.JSON(w, http.StatusCreated, map[string]any{
@@ -159,15 +371,20 @@ func (h *VehicleDefault) FindByColorAndYear() http.HandlerFunc {
 			return
 		}
 
-		vehiclesFounded, err := h.sv.FindByColorAndYear(color, year)
+		vehiclesFounded, err := h.sv.FindByColorAndYear(r.Context(), color, year)
 		if err != nil {
-			response.Text(w, http.StatusNotFound, err.Error())
+			switch {
+			case errors.Is(err, context.DeadlineExceeded):
+				response.Text(w, http.StatusGatewayTimeout, err.Error())
+			default:
+				response.Text(w, http.StatusNotFound, err.Error())
+			}
 			return
 		}
 
 		response.JSON(w, http.StatusOK, map[string]any{
 			"message": "Cars list obtained",
-			"data":    vehiclesFounded,
+			"data":    outVehicles(r, vehiclesFounded),
 		})
 	}
 }
@@ -189,15 +406,20 @@ func (h *VehicleDefault) FindByBrandAndYearRate() http.HandlerFunc {
 			return
 		}
 
-		vehiclesFounded, err := h.sv.FindBetweenBrandAndYearRate(brand, initialYear, finalYear)
+		vehiclesFounded, err := h.sv.FindBetweenBrandAndYearRate(r.Context(), brand, initialYear, finalYear)
 		if err != nil {
-			response.Text(w, http.StatusNotFound, err.Error())
+			switch {
+			case errors.Is(err, context.DeadlineExceeded):
+				response.Text(w, http.StatusGatewayTimeout, err.Error())
+			default:
+				response.Text(w, http.StatusNotFound, err.Error())
+			}
 			return
 		}
 
 		response.JSON(w, http.StatusOK, map[string]any{
 			"message": "cars list founded",
-			"data":    vehiclesFounded,
+			"data":    outVehicles(r, vehiclesFounded),
 		})
 
 	}
@@ -206,15 +428,20 @@ func (h *VehicleDefault) FindByBrandAndYearRate() http.HandlerFunc {
 func (h *VehicleDefault) FindVelocityAverageByBrand() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		brand := chi.URLParam(r, "brand")
-		brandVelocityAverage, err := h.sv.FindVelocityAverageByBrand(brand)
+		brandVelocityAverage, err := h.sv.FindVelocityAverageByBrand(r.Context(), brand)
 		if err != nil {
-			response.Text(w, http.StatusNotFound, err.Error())
+			switch {
+			case errors.Is(err, context.DeadlineExceeded):
+				response.Text(w, http.StatusGatewayTimeout, err.Error())
+			default:
+				response.Text(w, http.StatusNotFound, err.Error())
+			}
 			return
 		}
 
 		response.JSON(w, http.StatusOK, map[string]any{
 			"message":           "Speed average found",
-			"brandSpeedAverage": brandVelocityAverage,
+			"brandSpeedAverage": unitsOf(r).FromCanonical("max_speed", brandVelocityAverage),
 		})
 	}
 }
@@ -233,22 +460,46 @@ func (h *VehicleDefault) CreateVehicles() http.HandlerFunc {
 			return
 		}
 
+		// upsert by registration
+		switch r.URL.Query().Get("upsert") {
+		case "":
+		case "registration":
+			h.upsertByRegistration(w, r, vehicles)
+			return
+		default:
+			response.Text(w, http.StatusBadRequest, "invalid upsert value, it must be registration")
+			return
+		}
+
 		// Validación no funciona :(
 		//if err := validateAllVehiclesKeys(vehicles); err != nil {
 		//	response.Text(w, http.StatusBadRequest, "invalid body. Keys are missing")
 		//	return
 		//}
 
-		if err := h.sv.CreateVehicules(vehicles); err != nil {
+		for i := range vehicles {
+			vehicles[i] = inVehicle(r, vehicles[i])
+		}
+		if err := h.sv.CreateVehicules(r.Context(), vehicles); err != nil {
 			switch {
 			case errors.Is(err, internal.ErrInvalidBody):
 				response.Text(w, http.StatusBadRequest, err.Error())
-			case errors.Is(err, internal.ErrCarAlreadyExists):
+			case errors.Is(err, internal.ErrCarAlreadyExists), errors.Is(err, internal.ErrRegistrationAlreadyExists), errors.Is(err, internal.ErrVINAlreadyExists):
 				response.Text(w, http.StatusConflict, err.Error())
+			case errors.Is(err, internal.ErrNotInCatalog), errors.Is(err, internal.ErrNotInRegistry), errors.Is(err, internal.ErrInvalidRegistration),
+				errors.Is(err, internal.ErrInvalidVIN), errors.Is(err, internal.ErrVINMismatch):
+				response.Text(w, http.StatusUnprocessableEntity, err.Error())
+			case errors.Is(err, context.DeadlineExceeded):
+				response.Text(w, http.StatusGatewayTimeout, err.Error())
+			default:
+				response.Text(w, http.StatusInternalServerError, err.Error())
 			}
 			return
 		}
 
+		for i := range vehicles {
+			vehicles[i] = outVehicle(r, vehicles[i])
+		}
 		response.JSON(w, http.StatusCreated, map[string]any{
 			"message": "Vehicules created successfully",
 			"data":    vehicles,
@@ -286,7 +537,7 @@ func (h *VehicleDefault) UpdateMaxSpeed() http.HandlerFunc {
 			return
 		}
 
-		vehicleUpdated, err := h.sv.UpdateMaxSpeed(id, newMaxSpeed)
+		vehicleUpdated, err := h.sv.UpdateMaxSpeed(r.Context(), id, inValue(r, "max_speed", newMaxSpeed))
 		if err != nil {
 
 			if errors.Is(err, internal.ErrVehicleNotFounded) {
@@ -294,13 +545,23 @@ func (h *VehicleDefault) UpdateMaxSpeed() http.HandlerFunc {
 				return
 			}
 
+			if errors.Is(err, context.DeadlineExceeded) {
+				response.Text(w, http.StatusGatewayTimeout, err.Error())
+				return
+			}
+
+			if errors.Is(err, internal.ErrAuditFailed) {
+				response.Text(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+
 			response.Text(w, http.StatusBadRequest, err.Error())
 			return
 		}
 
 		response.JSON(w, http.StatusOK, map[string]any{
 			"message":        "max_speed updated successfully",
-			"vehicleUpdated": vehicleUpdated,
+			"vehicleUpdated": outVehicle(r, vehicleUpdated),
 		})
 
 	}
@@ -310,15 +571,20 @@ func (h *VehicleDefault) FindVehiclesByFuelType() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		fuelType := chi.URLParam(r, "type")
 
-		vehiclesFounded, err := h.sv.FindVehiclesByFuelType(fuelType)
+		vehiclesFounded, err := h.sv.FindVehiclesByFuelType(r.Context(), fuelType)
 		if err != nil {
-			response.Text(w, http.StatusNotFound, err.Error())
+			switch {
+			case errors.Is(err, context.DeadlineExceeded):
+				response.Text(w, http.StatusGatewayTimeout, err.Error())
+			default:
+				response.Text(w, http.StatusNotFound, err.Error())
+			}
 			return
 		}
 
 		response.JSON(w, http.StatusOK, map[string]any{
 			"message": "Vehicles found!",
-			"data":    vehiclesFounded,
+			"data":    outVehicles(r, vehiclesFounded),
 		})
 	}
 }
@@ -332,8 +598,15 @@ func (h *VehicleDefault) Delete() http.HandlerFunc {
 			return
 		}
 
-		if err := h.sv.Delete(id); err != nil {
-			response.Text(w, http.StatusNotFound, err.Error())
+		if err := h.sv.Delete(r.Context(), id); err != nil {
+			switch {
+			case errors.Is(err, context.DeadlineExceeded):
+				response.Text(w, http.StatusGatewayTimeout, err.Error())
+			case errors.Is(err, internal.ErrAuditFailed):
+				response.Text(w, http.StatusInternalServerError, err.Error())
+			default:
+				response.Text(w, http.StatusNotFound, err.Error())
+			}
 			return
 		}
 
@@ -347,15 +620,20 @@ func (h *VehicleDefault) FindVehiculesByTransmissionType() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		transmissionType := chi.URLParam(r, "type")
 
-		vehiclesFound, err := h.sv.FindVehiculesByTransmissionType(transmissionType)
+		vehiclesFound, err := h.sv.FindVehiculesByTransmissionType(r.Context(), transmissionType)
 		if err != nil {
-			response.Text(w, http.StatusNotFound, err.Error())
+			switch {
+			case errors.Is(err, context.DeadlineExceeded):
+				response.Text(w, http.StatusGatewayTimeout, err.Error())
+			default:
+				response.Text(w, http.StatusNotFound, err.Error())
+			}
 			return
 		}
 
 		response.JSON(w, http.StatusOK, map[string]any{
 			"message": "vehicles found!",
-			"data":    vehiclesFound,
+			"data":    outVehicles(r, vehiclesFound),
 		})
 	}
 }
@@ -382,15 +660,24 @@ func (h *VehicleDefault) UpdateFuelType() http.HandlerFunc {
 			return
 		}
 
-		vehicleUpdated, err := h.sv.UpdateFuelType(id, newFuelType)
+		vehicleUpdated, err := h.sv.UpdateFuelType(r.Context(), id, newFuelType)
 		if err != nil {
-			response.Text(w, http.StatusNotFound, err.Error())
+			switch {
+			case errors.Is(err, internal.ErrNotInCatalog):
+				response.Text(w, http.StatusUnprocessableEntity, err.Error())
+			case errors.Is(err, context.DeadlineExceeded):
+				response.Text(w, http.StatusGatewayTimeout, err.Error())
+			case errors.Is(err, internal.ErrAuditFailed):
+				response.Text(w, http.StatusInternalServerError, err.Error())
+			default:
+				response.Text(w, http.StatusNotFound, err.Error())
+			}
 			return
 		}
 
 		response.JSON(w, http.StatusOK, map[string]any{
 			"message":        "fuel type updated successfully in vehicle",
-			"vehicleUpdated": vehicleUpdated,
+			"vehicleUpdated": outVehicle(r, vehicleUpdated),
 		})
 
 	}
@@ -400,9 +687,14 @@ func (h *VehicleDefault) AverageBrandCapacity() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		brand := chi.URLParam(r, "brand")
 
-		averageBrandCapacity, err := h.sv.AverageBrandCapacity(brand)
+		averageBrandCapacity, err := h.sv.AverageBrandCapacity(r.Context(), brand)
 		if err != nil {
-			response.Text(w, http.StatusNotFound, err.Error())
+			switch {
+			case errors.Is(err, context.DeadlineExceeded):
+				response.Text(w, http.StatusGatewayTimeout, err.Error())
+			default:
+				response.Text(w, http.StatusNotFound, err.Error())
+			}
 			return
 		}
 
@@ -431,15 +723,22 @@ func (h *VehicleDefault) FindVehiclesByDimension() http.HandlerFunc {
 			return
 		}
 
-		vehiclesFounded, err := h.sv.FindVehiclesByDimensions(minLengthValue, maxLengthValue, minWidthValue, maxWidthValue)
+		minLengthValue, maxLengthValue = inValue(r, "length", minLengthValue), inValue(r, "length", maxLengthValue)
+		minWidthValue, maxWidthValue = inValue(r, "width", minWidthValue), inValue(r, "width", maxWidthValue)
+		vehiclesFounded, err := h.sv.FindVehiclesByDimensions(r.Context(), minLengthValue, maxLengthValue, minWidthValue, maxWidthValue)
 		if err != nil {
-			response.Text(w, http.StatusNotFound, err.Error())
+			switch {
+			case errors.Is(err, context.DeadlineExceeded):
+				response.Text(w, http.StatusGatewayTimeout, err.Error())
+			default:
+				response.Text(w, http.StatusNotFound, err.Error())
+			}
 			return
 		}
 
 		response.JSON(w, http.StatusOK, map[string]any{
 			"message": "Vehicles founded successfully!",
-			"data":    vehiclesFounded,
+			"data":    outVehicles(r, vehiclesFounded),
 		})
 
 	}
@@ -462,15 +761,20 @@ func (h *VehicleDefault) FindVehiclesByWeightRate() http.HandlerFunc {
 			return
 		}
 
-		vehiclesFounded, err := h.sv.FindVehiclesByWeightRate(minWeight, maxWeight)
+		vehiclesFounded, err := h.sv.FindVehiclesByWeightRate(r.Context(), inValue(r, "weight", minWeight), inValue(r, "weight", maxWeight))
 		if err != nil {
-			response.Text(w, http.StatusNotFound, err.Error())
+			switch {
+			case errors.Is(err, context.DeadlineExceeded):
+				response.Text(w, http.StatusGatewayTimeout, err.Error())
+			default:
+				response.Text(w, http.StatusNotFound, err.Error())
+			}
 			return
 		}
 
 		response.JSON(w, http.StatusOK, map[string]any{
 			"message": "Vehicles founded successfully!",
-			"data":    vehiclesFounded,
+			"data":    outVehicles(r, vehiclesFounded),
 		})
 
 	}
@@ -485,6 +789,19 @@ func validateIfKeysExist(data map[string]any, keys ...string) error {
 	return nil
 }
 
+// parseAsOf parses the optional as_of query param in RFC 3339 format
+func parseAsOf(r *http.Request) (asOf time.Time, err error) {
+	s := r.URL.Query().Get("as_of")
+	if s == "" {
+		return
+	}
+	asOf, err = time.Parse(time.RFC3339, s)
+	if err != nil {
+		err = errors.New("invalid as_of value, it must be a RFC 3339 timestamp")
+	}
+	return
+}
+
 func parseFloatValues(input string) (float64, float64, error) {
 	values := strings.Split(input, "-")
 	if len(values) != 2 {
@@ -504,3 +821,26 @@ func parseFloatValues(input string) (float64, float64, error) {
 	return minValue, maxValue, nil
 
 }
+
+// newVehicleJSON is a function that serializes a vehicle in JSON format
+func newVehicleJSON(v internal.Vehicle) VehicleJSON {
+	return VehicleJSON{
+		ID:              v.Id,
+		Brand:           v.Brand,
+		Model:           v.Model,
+		Registration:    v.Registration,
+		VIN:             v.VIN,
+		Color:           v.Color,
+		FabricationYear: v.FabricationYear,
+		Capacity:        v.Capacity,
+		MaxSpeed:        v.MaxSpeed,
+		FuelType:        v.FuelType,
+		Transmission:    v.Transmission,
+		Weight:          v.Weight,
+		Height:          v.Height,
+		Length:          v.Length,
+		Width:           v.Width,
+		Status:          string(v.Status),
+		DeletedAt:       v.DeletedAt,
+	}
+}
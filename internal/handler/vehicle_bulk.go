@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"app/internal"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/bootcamp-go/web/response"
+)
+
+// VehicleFilterJSON is a struct that represents a filter over vehicles in JSON format
+type VehicleFilterJSON struct {
+	Brand        string   `json:"brand"`
+	Model        string   `json:"model"`
+	Color        string   `json:"color"`
+	FuelType     string   `json:"fuel_type"`
+	Transmission string   `json:"transmission"`
+	Year         *int     `json:"year"`
+	YearFrom     *int     `json:"year_from"`
+	YearTo       *int     `json:"year_to"`
+	Passengers   *int     `json:"passengers"`
+	Status       string   `json:"status"`
+	MinLength    *float64 `json:"min_length"`
+	MaxLength    *float64 `json:"max_length"`
+	MinWidth     *float64 `json:"min_width"`
+	MaxWidth     *float64 `json:"max_width"`
+	MinWeight    *float64 `json:"min_weight"`
+	MaxWeight    *float64 `json:"max_weight"`
+}
+
+// VehiclePatchJSON is a struct that represents a partial update of a vehicle in JSON format
+type VehiclePatchJSON struct {
+	Brand           *string  `json:"brand"`
+	Model           *string  `json:"model"`
+	Registration    *string  `json:"registration"`
+	VIN             *string  `json:"vin"`
+	Color           *string  `json:"color"`
+	FabricationYear *int     `json:"year"`
+	Capacity        *int     `json:"passengers"`
+	MaxSpeed        *float64 `json:"max_speed"`
+	FuelType        *string  `json:"fuel_type"`
+	Transmission    *string  `json:"transmission"`
+	Weight          *float64 `json:"weight"`
+	Height          *float64 `json:"height"`
+	Length          *float64 `json:"length"`
+	Width           *float64 `json:"width"`
+}
+
+// BulkUpdateJSON is a struct that represents the body of POST /vehicles/bulk-update
+type BulkUpdateJSON struct {
+	Filter VehicleFilterJSON `json:"filter"`
+	Patch  VehiclePatchJSON  `json:"patch"`
+	DryRun bool              `json:"dry_run"`
+}
+
+// BulkDeleteJSON is a struct that represents the body of POST /vehicles/bulk-delete
+type BulkDeleteJSON struct {
+	Filter VehicleFilterJSON `json:"filter"`
+	DryRun bool              `json:"dry_run"`
+}
+
+// BulkUpdate is a method that returns a handler for the route POST /vehicles/bulk-update
+func (h *VehicleDefault) BulkUpdate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		var body BulkUpdateJSON
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid body")
+			return
+		}
+
+		// process
+		ids, err := h.sv.BulkUpdate(r.Context(), inFilter(r, body.Filter.toDomain()), inPatch(r, body.Patch.toDomain()), body.DryRun)
+		if err != nil {
+			writeBulkError(w, err)
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "vehicles updated successfully",
+			"dry_run": body.DryRun,
+			"count":   len(ids),
+			"ids":     ids,
+		})
+	}
+}
+
+// BulkDelete is a method that returns a handler for the route POST /vehicles/bulk-delete
+func (h *VehicleDefault) BulkDelete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		var body BulkDeleteJSON
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid body")
+			return
+		}
+
+		// process
+		ids, err := h.sv.BulkDelete(r.Context(), inFilter(r, body.Filter.toDomain()), body.DryRun)
+		if err != nil {
+			writeBulkError(w, err)
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "vehicles deleted successfully",
+			"dry_run": body.DryRun,
+			"count":   len(ids),
+			"ids":     ids,
+		})
+	}
+}
+
+// writeBulkError writes the response of an error of a bulk operation
+func writeBulkError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, internal.ErrEmptyFilter), errors.Is(err, internal.ErrEmptyPatch):
+		response.Text(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, internal.ErrNotInCatalog), errors.Is(err, internal.ErrNotInRegistry), errors.Is(err, internal.ErrInvalidRegistration),
+		errors.Is(err, internal.ErrInvalidVIN), errors.Is(err, internal.ErrVINMismatch):
+		response.Text(w, http.StatusUnprocessableEntity, err.Error())
+	case errors.Is(err, internal.ErrRegistrationAlreadyExists), errors.Is(err, internal.ErrVINAlreadyExists):
+		response.Text(w, http.StatusConflict, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		response.Text(w, http.StatusGatewayTimeout, err.Error())
+	default:
+		response.Text(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// toDomain is a method that converts the filter to the domain
+func (f VehicleFilterJSON) toDomain() internal.VehicleFilter {
+	filter := internal.VehicleFilter{
+		Brand:        f.Brand,
+		Model:        f.Model,
+		Color:        f.Color,
+		FuelType:     f.FuelType,
+		Transmission: f.Transmission,
+		YearFrom:     f.YearFrom,
+		YearTo:       f.YearTo,
+		MinCapacity:  f.Passengers,
+		Status:       internal.VehicleStatus(internal.NormalizeKey(f.Status)),
+		MinLength:    f.MinLength,
+		MaxLength:    f.MaxLength,
+		MinWidth:     f.MinWidth,
+		MaxWidth:     f.MaxWidth,
+		MinWeight:    f.MinWeight,
+		MaxWeight:    f.MaxWeight,
+	}
+	// an exact year is a range of one year
+	if f.Year != nil {
+		filter.YearFrom, filter.YearTo = f.Year, f.Year
+	}
+	return filter
+}
+
+// toDomain is a method that converts the patch to the domain
+func (p VehiclePatchJSON) toDomain() internal.VehiclePatch {
+	return internal.VehiclePatch{
+		Brand:           p.Brand,
+		Model:           p.Model,
+		Registration:    p.Registration,
+		VIN:             p.VIN,
+		Color:           p.Color,
+		FabricationYear: p.FabricationYear,
+		Capacity:        p.Capacity,
+		MaxSpeed:        p.MaxSpeed,
+		FuelType:        p.FuelType,
+		Transmission:    p.Transmission,
+		Weight:          p.Weight,
+		Height:          p.Height,
+		Length:          p.Length,
+		Width:           p.Width,
+	}
+}
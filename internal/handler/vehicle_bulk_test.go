@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"app/internal/service"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestVehicleDefault_Bulk(t *testing.T) {
+	cases := []struct {
+		name   string
+		target string
+		body   string
+		code   int
+		// count is the number of vehicles matched and left is the number of red vehicles after the request
+		count, left int
+	}{
+		{"update", "/vehicles/bulk-update", `{"filter":{"color":"red"},"patch":{"color":"blue"}}`, http.StatusOK, 2, 0},
+		{"update in a dry run", "/vehicles/bulk-update", `{"filter":{"color":"red"},"patch":{"color":"blue"},"dry_run":true}`, http.StatusOK, 2, 2},
+		{"update without filter", "/vehicles/bulk-update", `{"patch":{"color":"blue"}}`, http.StatusBadRequest, 0, 2},
+		{"update without patch", "/vehicles/bulk-update", `{"filter":{"color":"red"}}`, http.StatusBadRequest, 0, 2},
+		{"update to a taken registration", "/vehicles/bulk-update", `{"filter":{"color":"red"},"patch":{"registration":"AB123CD"}}`, http.StatusConflict, 0, 2},
+		{"update with an invalid body", "/vehicles/bulk-update", `{"filter":`, http.StatusBadRequest, 0, 2},
+		{"delete", "/vehicles/bulk-delete", `{"filter":{"color":"red"}}`, http.StatusOK, 2, 0},
+		{"delete in a dry run", "/vehicles/bulk-delete", `{"filter":{"color":"red"},"dry_run":true}`, http.StatusOK, 2, 2},
+		{"delete without filter", "/vehicles/bulk-delete", `{"dry_run":true}`, http.StatusBadRequest, 0, 2},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			rp := repository.NewVehicleMap(map[int]internal.Vehicle{
+				1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", Color: "red"}},
+				2: {Id: 2, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", Color: "red"}},
+				3: {Id: 3, VehicleAttributes: internal.VehicleAttributes{Brand: "Fiat", Color: "blue"}},
+			})
+			hd := NewVehicleDefault(service.NewVehicleDefault(rp, nil, nil, nil, nil, nil, nil, nil), nil)
+			rt := chi.NewRouter()
+			rt.Post("/vehicles/bulk-update", hd.BulkUpdate())
+			rt.Post("/vehicles/bulk-delete", hd.BulkDelete())
+
+			res := httptest.NewRecorder()
+			rt.ServeHTTP(res, httptest.NewRequest(http.MethodPost, c.target, strings.NewReader(c.body)))
+			if res.Code != c.code {
+				t.Fatalf("code = %d, want %d: %s", res.Code, c.code, res.Body.String())
+			}
+			if res.Code == http.StatusOK {
+				var body struct {
+					Count int `json:"count"`
+				}
+				if err := json.NewDecoder(res.Body).Decode(&body); err != nil || body.Count != c.count {
+					t.Fatalf("count = %d %v, want %d", body.Count, err, c.count)
+				}
+			}
+
+			red, _ := rp.FindWhere(context.Background(), internal.VehicleFilter{Color: "red"})
+			if len(red) != c.left {
+				t.Fatalf("red vehicles = %d, want %d", len(red), c.left)
+			}
+		})
+	}
+}
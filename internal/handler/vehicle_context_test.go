@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"app/internal/service"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestVehicleDefault_DeadlineExceeded(t *testing.T) {
+	db := map[int]internal.Vehicle{
+		1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", Color: "red", FabricationYear: 2020}},
+	}
+	hd := NewVehicleDefault(service.NewVehicleDefault(repository.NewVehicleMap(db), nil, nil, nil, nil, nil, nil, nil), nil)
+	rt := chi.NewRouter()
+	rt.Get("/vehicles", hd.GetAll())
+	rt.Get("/vehicles/color/{color}/year/{year}", hd.FindByColorAndYear())
+
+	cases := []struct {
+		name   string
+		target string
+	}{
+		{"all", "/vehicles"},
+		{"by color and year", "/vehicles/color/red/year/2020"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+			defer cancel()
+
+			res := httptest.NewRecorder()
+			rt.ServeHTTP(res, httptest.NewRequest(http.MethodGet, c.target, nil).WithContext(ctx))
+			if res.Code != http.StatusGatewayTimeout {
+				t.Fatalf("code = %d, want %d: %s", res.Code, http.StatusGatewayTimeout, res.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"app/internal/service"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestVehicleDefault_AsOf(t *testing.T) {
+	// the revisions are stored at the current time, so the moments are taken around them
+	before := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	rp := repository.NewVehicleMap(map[int]internal.Vehicle{
+		1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", Model: "Focus", MaxSpeed: 200}},
+	})
+	if _, err := rp.UpdateMaxSpeed(context.Background(), 1, 180); err != nil {
+		t.Fatal(err)
+	}
+	after := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	hd := NewVehicleDefault(service.NewVehicleDefault(rp, nil, nil, nil, nil, nil, nil, nil), nil)
+
+	rt := chi.NewRouter()
+	rt.Get("/vehicles", hd.GetAll())
+	rt.Get("/vehicles/{id}", hd.GetByID())
+	rt.Get("/vehicles/{id}/history", hd.History())
+
+	cases := []struct {
+		name   string
+		target string
+		code   int
+		// count is the number of vehicles or revisions returned
+		count int
+	}{
+		{"one now", "/vehicles/1", http.StatusOK, 1},
+		{"one after", "/vehicles/1?as_of=" + after, http.StatusOK, 1},
+		{"one before", "/vehicles/1?as_of=" + before, http.StatusNotFound, 0},
+		{"one at an invalid moment", "/vehicles/1?as_of=yesterday", http.StatusBadRequest, 0},
+		{"all after", "/vehicles?as_of=" + after, http.StatusOK, 1},
+		{"all before", "/vehicles?as_of=" + before, http.StatusOK, 0},
+		{"all at an invalid moment", "/vehicles?as_of=2026-01-01", http.StatusBadRequest, 0},
+		{"history", "/vehicles/1/history", http.StatusOK, 2},
+		{"history of a missing vehicle", "/vehicles/2/history", http.StatusNotFound, 0},
+		{"history of an invalid id", "/vehicles/a/history", http.StatusBadRequest, 0},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			res := httptest.NewRecorder()
+			rt.ServeHTTP(res, httptest.NewRequest(http.MethodGet, c.target, nil))
+			if res.Code != c.code {
+				t.Fatalf("code = %d, want %d: %s", res.Code, c.code, res.Body.String())
+			}
+			if res.Code != http.StatusOK {
+				return
+			}
+
+			var body struct {
+				Data any `json:"data"`
+			}
+			if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			// a vehicle, a list of revisions or a set of vehicles by id
+			var count int
+			switch data := body.Data.(type) {
+			case []any:
+				count = len(data)
+			case map[string]any:
+				count = len(data)
+				if _, ok := data["id"]; ok {
+					count = 1
+				}
+			}
+			if count != c.count {
+				t.Fatalf("count = %d, want %d: %s", count, c.count, body.Data)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"app/internal"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// parseFilterQuery parses the attribute criteria of the query params, the same ones accepted
+// by the bulk endpoints: brand, model, color, fuel_type, transmission, year, year_from, year_to,
+// passengers (the minimum capacity), status and min_/max_ length, width and weight, in the unit system of the request
+func parseFilterQuery(r *http.Request) (f internal.VehicleFilter, err error) {
+	q := r.URL.Query()
+
+	f.Brand = q.Get("brand")
+	f.Model = q.Get("model")
+	f.Color = q.Get("color")
+	f.FuelType = q.Get("fuel_type")
+	f.Transmission = q.Get("transmission")
+	if s := q.Get("status"); s != "" {
+		if f.Status, err = internal.ParseVehicleStatus(s); err != nil {
+			return
+		}
+	}
+
+	ints := []struct {
+		name string
+		dst  **int
+	}{
+		{"year_from", &f.YearFrom},
+		{"year_to", &f.YearTo},
+		{"passengers", &f.MinCapacity},
+	}
+	for _, p := range ints {
+		if s := q.Get(p.name); s != "" {
+			v, e := strconv.Atoi(s)
+			if e != nil {
+				return f, fmt.Errorf("invalid %s value, it must be an int value", p.name)
+			}
+			*p.dst = &v
+		}
+	}
+	if s := q.Get("year"); s != "" {
+		year, e := strconv.Atoi(s)
+		if e != nil {
+			return f, fmt.Errorf("invalid year value, it must be an int value")
+		}
+		f.YearFrom, f.YearTo = &year, &year
+	}
+
+	floats := []struct {
+		name string
+		dst  **float64
+	}{
+		{"min_length", &f.MinLength},
+		{"max_length", &f.MaxLength},
+		{"min_width", &f.MinWidth},
+		{"max_width", &f.MaxWidth},
+		{"min_weight", &f.MinWeight},
+		{"max_weight", &f.MaxWeight},
+	}
+	for _, p := range floats {
+		if s := q.Get(p.name); s != "" {
+			v, e := strconv.ParseFloat(s, 64)
+			if e != nil {
+				return f, fmt.Errorf("invalid %s value, it must be a float64 value", p.name)
+			}
+			*p.dst = &v
+		}
+	}
+	f = inFilter(r, f)
+	return
+}
+
+// parseAssignmentQuery parses the criteria over the people in charge of the vehicles:
+// owner_id, driver_id and unassigned (vehicles without driver)
+func parseAssignmentQuery(r *http.Request) (f internal.AssignmentFilter, err error) {
+	q := r.URL.Query()
+
+	ids := []struct {
+		name string
+		dst  **int
+	}{
+		{"owner_id", &f.OwnerID},
+		{"driver_id", &f.DriverID},
+	}
+	for _, p := range ids {
+		if s := q.Get(p.name); s != "" {
+			v, e := strconv.Atoi(s)
+			if e != nil {
+				return f, fmt.Errorf("invalid %s value, it must be an int value", p.name)
+			}
+			*p.dst = &v
+		}
+	}
+	if s := q.Get("unassigned"); s != "" {
+		if f.Unassigned, err = strconv.ParseBool(s); err != nil {
+			return f, fmt.Errorf("invalid unassigned value, it must be a bool value")
+		}
+	}
+	return
+}
+
+// parseList splits a comma separated query param, ignoring empty items
+func parseList(r *http.Request, name string) (list []string) {
+	for _, item := range strings.Split(r.URL.Query().Get(name), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return
+}
+
+// parseFields parses a list of numeric fields, where "dimensions" stands for height, length and width
+func parseFields(r *http.Request, name string) (fields []string) {
+	for _, field := range parseList(r, name) {
+		if field == "dimensions" {
+			fields = append(fields, "height", "length", "width")
+			continue
+		}
+		fields = append(fields, field)
+	}
+	return
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"app/internal"
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/bootcamp-go/web/response"
+	"github.com/go-chi/chi/v5"
+)
+
+// FindByRegistration is a method that returns a handler for the route GET /vehicles/registration/{plate}
+func (h *VehicleDefault) FindByRegistration() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// process
+		v, err := h.sv.FindByRegistration(r.Context(), chi.URLParam(r, "plate"))
+		if err != nil {
+			switch {
+			case errors.Is(err, internal.ErrVehicleNotFounded):
+				response.Text(w, http.StatusNotFound, err.Error())
+			case errors.Is(err, internal.ErrRegistrationAmbiguous):
+				response.Text(w, http.StatusConflict, err.Error())
+			case errors.Is(err, context.DeadlineExceeded):
+				response.Text(w, http.StatusGatewayTimeout, err.Error())
+			default:
+				response.Text(w, http.StatusInternalServerError, err.Error())
+			}
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    newVehicleJSON(outVehicle(r, v)),
+		})
+	}
+}
+
+// upsertByRegistration is a method that answers POST /vehicles/batch?upsert=registration,
+// replacing the vehicles whose registration is in use and creating the others
+func (h *VehicleDefault) upsertByRegistration(w http.ResponseWriter, r *http.Request, vehicles []internal.Vehicle) {
+	// process
+	for i := range vehicles {
+		vehicles[i] = inVehicle(r, vehicles[i])
+	}
+	created, updated, err := h.sv.UpsertByRegistration(r.Context(), vehicles)
+	if err != nil {
+		switch {
+		case errors.Is(err, internal.ErrCarAlreadyExists), errors.Is(err, internal.ErrRegistrationAlreadyExists), errors.Is(err, internal.ErrVINAlreadyExists), errors.Is(err, internal.ErrRegistrationAmbiguous):
+			response.Text(w, http.StatusConflict, err.Error())
+		case errors.Is(err, internal.ErrNotInCatalog), errors.Is(err, internal.ErrNotInRegistry), errors.Is(err, internal.ErrInvalidRegistration),
+			errors.Is(err, internal.ErrInvalidVIN), errors.Is(err, internal.ErrVINMismatch):
+			response.Text(w, http.StatusUnprocessableEntity, err.Error())
+		case errors.Is(err, context.DeadlineExceeded):
+			response.Text(w, http.StatusGatewayTimeout, err.Error())
+		default:
+			response.Text(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	// response
+	data := map[string][]VehicleJSON{
+		"created": make([]VehicleJSON, len(created)),
+		"updated": make([]VehicleJSON, len(updated)),
+	}
+	for i, v := range created {
+		data["created"][i] = newVehicleJSON(outVehicle(r, v))
+	}
+	for i, v := range updated {
+		data["updated"][i] = newVehicleJSON(outVehicle(r, v))
+	}
+	response.JSON(w, http.StatusOK, map[string]any{
+		"message": "vehicles upserted successfully",
+		"data":    data,
+	})
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"app/internal"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/bootcamp-go/web/response"
+)
+
+const (
+	// searchDefaultLimit is the number of results returned when no limit is given
+	searchDefaultLimit = 20
+	// searchMaxLimit is the maximum number of results returned
+	searchMaxLimit = 100
+)
+
+// VehicleSearchResultJSON is a struct that represents a search result in JSON format
+type VehicleSearchResultJSON struct {
+	Score   float64     `json:"score"`
+	Vehicle VehicleJSON `json:"vehicle"`
+}
+
+// Search is a method that returns a handler for the route GET /vehicles/search?q=&limit=
+func (h *VehicleDefault) Search() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		query := r.URL.Query().Get("q")
+		limit := searchDefaultLimit
+		if s := r.URL.Query().Get("limit"); s != "" {
+			l, err := strconv.Atoi(s)
+			if err != nil || l <= 0 || l > searchMaxLimit {
+				response.Text(w, http.StatusBadRequest, "invalid limit value, it must be an int between 1 and "+strconv.Itoa(searchMaxLimit))
+				return
+			}
+			limit = l
+		}
+
+		// process
+		results, err := h.sv.Search(r.Context(), query, limit)
+		if err != nil {
+			switch {
+			case errors.Is(err, internal.ErrEmptySearchQuery):
+				response.Text(w, http.StatusBadRequest, err.Error())
+			case errors.Is(err, context.DeadlineExceeded):
+				response.Text(w, http.StatusGatewayTimeout, err.Error())
+			default:
+				response.Text(w, http.StatusInternalServerError, err.Error())
+			}
+			return
+		}
+
+		// response
+		data := make([]VehicleSearchResultJSON, len(results))
+		for i, result := range results {
+			data[i] = VehicleSearchResultJSON{
+				Score:   result.Score,
+				Vehicle: newVehicleJSON(outVehicle(r, result.Vehicle)),
+			}
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    data,
+		})
+	}
+}
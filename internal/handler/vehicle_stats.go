@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"app/internal"
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/bootcamp-go/web/response"
+)
+
+// StatsGroupJSON is a struct that represents the aggregates of a group of vehicles in JSON format
+type StatsGroupJSON struct {
+	Group  *string                       `json:"group,omitempty"`
+	Count  int                           `json:"count"`
+	Fields map[string]map[string]float64 `json:"fields"`
+}
+
+// Stats is a method that returns a handler for the route
+// GET /vehicles/stats?group_by=&fields=&metrics=&percentiles= plus the filter query params
+func (h *VehicleDefault) Stats() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		filter, err := parseFilterQuery(r)
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		query := internal.StatsQuery{
+			GroupBy: r.URL.Query().Get("group_by"),
+			Fields:  parseFields(r, "fields"),
+		}
+
+		// metrics: count, min, max, avg, sum, median and pNN
+		metrics := parseList(r, "metrics")
+		if len(metrics) == 0 {
+			metrics = []string{"count", "min", "max", "avg", "sum"}
+		}
+		for _, p := range parseList(r, "percentiles") {
+			metrics = append(metrics, "p"+p)
+		}
+		for _, m := range metrics {
+			if m == "median" {
+				query.Median = true
+				continue
+			}
+			if slices.Contains([]string{"count", "min", "max", "avg", "sum"}, m) {
+				continue
+			}
+			p, err := strconv.ParseFloat(strings.TrimPrefix(m, "p"), 64)
+			if !strings.HasPrefix(m, "p") || err != nil {
+				response.Text(w, http.StatusBadRequest, "invalid metric "+m)
+				return
+			}
+			query.Percentiles = append(query.Percentiles, p)
+		}
+
+		// process
+		stats, err := h.sv.Stats(r.Context(), filter, query)
+		if err != nil {
+			switch {
+			case errors.Is(err, internal.ErrInvalidGroupBy), errors.Is(err, internal.ErrInvalidStatsField), errors.Is(err, internal.ErrInvalidPercentile):
+				response.Text(w, http.StatusBadRequest, err.Error())
+			case errors.Is(err, context.DeadlineExceeded):
+				response.Text(w, http.StatusGatewayTimeout, err.Error())
+			default:
+				response.Text(w, http.StatusInternalServerError, err.Error())
+			}
+			return
+		}
+
+		// response
+		data := make([]StatsGroupJSON, len(stats))
+		for i, g := range stats {
+			data[i] = StatsGroupJSON{Count: g.Count, Fields: make(map[string]map[string]float64)}
+			if query.GroupBy != "" {
+				data[i].Group = &stats[i].Key
+			}
+			for field, fs := range g.Fields {
+				fs = fs.In(unitsOf(r), field)
+				values := make(map[string]float64)
+				for _, m := range metrics {
+					switch m {
+					case "count":
+						continue
+					case "min":
+						values[m] = fs.Min
+					case "max":
+						values[m] = fs.Max
+					case "avg":
+						values[m] = fs.Avg
+					case "sum":
+						values[m] = fs.Sum
+					case "median":
+						values[m] = fs.Median
+					default:
+						p, _ := strconv.ParseFloat(strings.TrimPrefix(m, "p"), 64)
+						values[m] = fs.Percentiles[p]
+					}
+				}
+				data[i].Fields[field] = values
+			}
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "stats computed successfully",
+			"data":    data,
+		})
+	}
+}
+
+// HistogramGroupJSON is a struct that represents the distribution of a group of vehicles in JSON format
+type HistogramGroupJSON struct {
+	Group   *string               `json:"group,omitempty"`
+	Count   int                   `json:"count"`
+	Buckets []HistogramBucketJSON `json:"buckets"`
+	Below   int                   `json:"below"`
+	Above   int                   `json:"above"`
+}
+
+// HistogramBucketJSON is a struct that represents a bucket of a histogram in JSON format
+type HistogramBucketJSON struct {
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
+	Count int     `json:"count"`
+}
+
+// Histogram is a method that returns a handler for the route
+// GET /vehicles/stats/histogram?field=&type=fixed|explicit|quantile&buckets=&width=&group_by= plus the filter query params.
+// buckets is a number of buckets, or the comma separated boundaries for the explicit type
+func (h *VehicleDefault) Histogram() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		filter, err := parseFilterQuery(r)
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		q := r.URL.Query()
+		query := internal.HistogramQuery{
+			Field:   q.Get("field"),
+			GroupBy: q.Get("group_by"),
+			Mode:    q.Get("type"),
+		}
+		buckets := parseList(r, "buckets")
+		if query.Mode == "" {
+			query.Mode = internal.HistogramFixed
+			if len(buckets) > 1 {
+				query.Mode = internal.HistogramExplicit
+			}
+		}
+		switch {
+		case query.Mode == internal.HistogramExplicit:
+			for _, b := range buckets {
+				boundary, err := strconv.ParseFloat(b, 64)
+				if err != nil || math.IsNaN(boundary) || math.IsInf(boundary, 0) {
+					response.Text(w, http.StatusBadRequest, "invalid buckets value, boundaries must be float64 values")
+					return
+				}
+				query.Boundaries = append(query.Boundaries, inValue(r, query.Field, boundary))
+			}
+		case q.Get("width") != "":
+			if query.Width, err = strconv.ParseFloat(q.Get("width"), 64); err != nil || math.IsNaN(query.Width) || math.IsInf(query.Width, 0) || query.Width <= 0 {
+				response.Text(w, http.StatusBadRequest, "invalid width value, it must be a positive float64 value")
+				return
+			}
+			query.Width = inValue(r, query.Field, query.Width)
+		default:
+			query.Buckets = 10
+			if len(buckets) == 1 {
+				if query.Buckets, err = strconv.Atoi(buckets[0]); err != nil {
+					response.Text(w, http.StatusBadRequest, "invalid buckets value, it must be an int value")
+					return
+				}
+			}
+		}
+
+		// process
+		histogram, err := h.sv.Histogram(r.Context(), filter, query)
+		if err != nil {
+			switch {
+			case errors.Is(err, internal.ErrInvalidStatsField), errors.Is(err, internal.ErrInvalidGroupBy), errors.Is(err, internal.ErrInvalidHistogram):
+				response.Text(w, http.StatusBadRequest, err.Error())
+			case errors.Is(err, context.DeadlineExceeded):
+				response.Text(w, http.StatusGatewayTimeout, err.Error())
+			default:
+				response.Text(w, http.StatusInternalServerError, err.Error())
+			}
+			return
+		}
+
+		// response
+		data := make([]HistogramGroupJSON, len(histogram))
+		for i, g := range histogram {
+			data[i] = HistogramGroupJSON{
+				Count:   g.Count,
+				Buckets: make([]HistogramBucketJSON, len(g.Buckets)),
+				Below:   g.Below,
+				Above:   g.Above,
+			}
+			if query.GroupBy != "" {
+				data[i].Group = &histogram[i].Key
+			}
+			for b, bucket := range g.Buckets {
+				data[i].Buckets[b] = HistogramBucketJSON{
+					Lower: unitsOf(r).FromCanonical(query.Field, bucket.Lower),
+					Upper: unitsOf(r).FromCanonical(query.Field, bucket.Upper),
+					Count: bucket.Count,
+				}
+			}
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "histogram computed successfully",
+			"field":   query.Field,
+			"type":    query.Mode,
+			"data":    data,
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"app/internal/service"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestVehicleDefault_Histogram(t *testing.T) {
+	db := map[int]internal.Vehicle{
+		1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", MaxSpeed: 100}},
+		2: {Id: 2, VehicleAttributes: internal.VehicleAttributes{Brand: "Fiat", MaxSpeed: 180}},
+	}
+	hd := NewVehicleDefault(service.NewVehicleDefault(repository.NewVehicleMap(db), nil, nil, nil, nil, nil, nil, nil), nil)
+
+	cases := []struct {
+		name  string
+		query string
+		code  int
+	}{
+		{"fixed width", "field=max_speed&width=50", http.StatusOK},
+		{"tiny width", "field=max_speed&width=1e-300", http.StatusBadRequest},
+		{"infinite width", "field=max_speed&width=Inf", http.StatusBadRequest},
+		{"NaN width", "field=max_speed&width=NaN", http.StatusBadRequest},
+		{"zero width", "field=max_speed&width=0", http.StatusBadRequest},
+		{"infinite boundary", "field=max_speed&type=explicit&buckets=0,Inf", http.StatusBadRequest},
+		{"unknown field", "field=color", http.StatusBadRequest},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/vehicles/stats/histogram?"+c.query, nil)
+			res := httptest.NewRecorder()
+			hd.Histogram()(res, req)
+			if res.Code != c.code {
+				t.Fatalf("code = %d, want %d: %s", res.Code, c.code, res.Body.String())
+			}
+		})
+	}
+}
+
+func TestVehicleDefault_Stats(t *testing.T) {
+	db := map[int]internal.Vehicle{
+		1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", MaxSpeed: 100}},
+		2: {Id: 2, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", MaxSpeed: 200}},
+		3: {Id: 3, VehicleAttributes: internal.VehicleAttributes{Brand: "Fiat", MaxSpeed: 180}},
+	}
+	hd := NewVehicleDefault(service.NewVehicleDefault(repository.NewVehicleMap(db), nil, nil, nil, nil, nil, nil, nil), nil)
+
+	cases := []struct {
+		name  string
+		query string
+		code  int
+		// want are the metrics of max_speed of the first group
+		want map[string]float64
+	}{
+		{"default metrics", "fields=max_speed", http.StatusOK, map[string]float64{"min": 100, "max": 200, "avg": 160, "sum": 480}},
+		{"grouped", "fields=max_speed&group_by=brand&metrics=max", http.StatusOK, map[string]float64{"max": 180}},
+		{"median and percentiles", "fields=max_speed&metrics=median,p90&percentiles=50", http.StatusOK, map[string]float64{"median": 180, "p90": 196, "p50": 180}},
+		{"NaN percentile", "percentiles=NaN", http.StatusBadRequest, nil},
+		{"NaN metric", "metrics=pNaN", http.StatusBadRequest, nil},
+		{"infinite metric", "metrics=pInf", http.StatusBadRequest, nil},
+		{"percentile above 100", "percentiles=101", http.StatusBadRequest, nil},
+		{"unknown metric", "metrics=mode", http.StatusBadRequest, nil},
+		{"unknown group", "group_by=max_speed", http.StatusBadRequest, nil},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/vehicles/stats?"+c.query, nil)
+			res := httptest.NewRecorder()
+			hd.Stats()(res, req)
+			if res.Code != c.code {
+				t.Fatalf("code = %d, want %d: %s", res.Code, c.code, res.Body.String())
+			}
+			if c.want == nil {
+				return
+			}
+
+			var body struct {
+				Data []StatsGroupJSON `json:"data"`
+			}
+			if err := json.NewDecoder(res.Body).Decode(&body); err != nil || len(body.Data) == 0 {
+				t.Fatalf("body = %v %v", body, err)
+			}
+			got := body.Data[0].Fields["max_speed"]
+			for m, v := range c.want {
+				if math.Abs(got[m]-v) > 1e-9 {
+					t.Fatalf("%s = %v, want %v", m, got[m], v)
+				}
+			}
+		})
+	}
+}
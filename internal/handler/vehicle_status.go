@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"app/internal"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/bootcamp-go/web/response"
+	"github.com/go-chi/chi/v5"
+)
+
+// VehicleTransitionJSON is a struct that represents the body of POST /vehicles/{id}/transitions
+type VehicleTransitionJSON struct {
+	To     string `json:"to"`
+	Reason string `json:"reason"`
+}
+
+// Transition is a method that returns a handler for the route POST /vehicles/{id}/transitions
+func (h *VehicleDefault) Transition() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "Invalid ID format. ID must be an int number.")
+			return
+		}
+		var body VehicleTransitionJSON
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid body")
+			return
+		}
+		if body.To == "" || strings.TrimSpace(body.Reason) == "" {
+			response.Text(w, http.StatusBadRequest, "invalid body. Keys are missing")
+			return
+		}
+		to, err := internal.ParseVehicleStatus(body.To)
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// process
+		change, err := h.sv.Transition(r.Context(), internal.VehicleTransition{VehicleID: id, To: to, Reason: body.Reason})
+		if err != nil {
+			switch {
+			case errors.Is(err, internal.ErrVehicleNotFounded):
+				response.Text(w, http.StatusNotFound, err.Error())
+			case errors.Is(err, internal.ErrInvalidTransition):
+				response.Text(w, http.StatusConflict, err.Error())
+			case errors.Is(err, context.DeadlineExceeded):
+				response.Text(w, http.StatusGatewayTimeout, err.Error())
+			default:
+				response.Text(w, http.StatusInternalServerError, err.Error())
+			}
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "vehicle status changed successfully",
+			"from":    change.Before.Status,
+			"to":      change.After.Status,
+			"allowed": change.After.Status.Transitions(),
+			"data":    newVehicleJSON(outVehicle(r, change.After)),
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"app/internal/service"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestVehicleDefault_Transition(t *testing.T) {
+	cases := []struct {
+		name   string
+		status internal.VehicleStatus
+		target string
+		body   string
+		code   int
+		// want is the status of the vehicle 1 after the request
+		want internal.VehicleStatus
+	}{
+		{"allowed", internal.VehicleStatusInService, "/vehicles/1/transitions", `{"to":"in_maintenance","reason":"brakes"}`, http.StatusOK, internal.VehicleStatusInMaintenance},
+		{"back into service", internal.VehicleStatusRetired, "/vehicles/1/transitions", `{"to":"in_service","reason":"needed"}`, http.StatusOK, internal.VehicleStatusInService},
+		{"to the same status", internal.VehicleStatusInService, "/vehicles/1/transitions", `{"to":"in_service","reason":"none"}`, http.StatusConflict, internal.VehicleStatusInService},
+		{"out of a final status", internal.VehicleStatusSold, "/vehicles/1/transitions", `{"to":"in_service","reason":"bought back"}`, http.StatusConflict, internal.VehicleStatusSold},
+		{"not allowed", internal.VehicleStatusReserved, "/vehicles/1/transitions", `{"to":"sold","reason":"offer"}`, http.StatusConflict, internal.VehicleStatusReserved},
+		{"unknown status", internal.VehicleStatusInService, "/vehicles/1/transitions", `{"to":"stolen","reason":"gone"}`, http.StatusBadRequest, internal.VehicleStatusInService},
+		{"without reason", internal.VehicleStatusInService, "/vehicles/1/transitions", `{"to":"retired","reason":"  "}`, http.StatusBadRequest, internal.VehicleStatusInService},
+		{"invalid body", internal.VehicleStatusInService, "/vehicles/1/transitions", `{"to":`, http.StatusBadRequest, internal.VehicleStatusInService},
+		{"unknown vehicle", internal.VehicleStatusInService, "/vehicles/2/transitions", `{"to":"retired","reason":"old"}`, http.StatusNotFound, internal.VehicleStatusInService},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx := context.Background()
+			rp := repository.NewVehicleMap(map[int]internal.Vehicle{
+				1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford"}, Status: c.status},
+			})
+			au := repository.NewAuditSlice(nil)
+			hd := NewVehicleDefault(service.NewVehicleDefault(rp, au, nil, nil, nil, nil, nil, nil), nil)
+			rt := chi.NewRouter()
+			rt.Post("/vehicles/{id}/transitions", hd.Transition())
+
+			res := httptest.NewRecorder()
+			rt.ServeHTTP(res, httptest.NewRequest(http.MethodPost, c.target, strings.NewReader(c.body)))
+			if res.Code != c.code {
+				t.Fatalf("code = %d, want %d: %s", res.Code, c.code, res.Body.String())
+			}
+			if v, _ := rp.FindOne(ctx, 1); v.Status != c.want {
+				t.Fatalf("status = %s, want %s", v.Status, c.want)
+			}
+
+			// the transitions are audited with their reason
+			entries, _ := au.FindAll(ctx, time.Time{}, time.Time{})
+			if c.code != http.StatusOK {
+				if len(entries) != 0 {
+					t.Fatalf("audit entries = %d, want none", len(entries))
+				}
+				return
+			}
+			if len(entries) != 1 || entries[0].Action != internal.AuditActionTransition || entries[0].Reason == "" {
+				t.Fatalf("audit entries = %+v", entries)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"app/internal"
+	"app/internal/middleware"
+	"app/internal/repository"
+	"app/internal/service"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestVehicleDefault_Trash(t *testing.T) {
+	type step struct {
+		method, target string
+		code           int
+		// deleted are the ids of the vehicles listed with a deletion moment, when the response lists vehicles
+		deleted []int
+		// listed is the number of vehicles listed
+		listed int
+	}
+	cases := []struct {
+		name  string
+		steps []step
+	}{
+		{"listings", []step{
+			{http.MethodGet, "/vehicles", http.StatusOK, []int{}, 2},
+			{http.MethodGet, "/vehicles?include_deleted=true", http.StatusOK, []int{1}, 3},
+			{http.MethodGet, "/vehicles/trash", http.StatusOK, []int{1}, 1},
+			{http.MethodGet, "/vehicles/1", http.StatusNotFound, nil, 0},
+			{http.MethodGet, "/vehicles/1?include_deleted=true", http.StatusOK, []int{1}, 1},
+		}},
+		{"restore", []step{
+			{http.MethodPost, "/vehicles/1/restore", http.StatusOK, nil, 0},
+			{http.MethodGet, "/vehicles?include_deleted=true", http.StatusOK, []int{}, 3},
+			{http.MethodPost, "/vehicles/1/restore", http.StatusNotFound, nil, 0},
+		}},
+		{"restore errors", []step{
+			{http.MethodPost, "/vehicles/2/restore", http.StatusNotFound, nil, 0},
+			{http.MethodPost, "/vehicles/9/restore", http.StatusNotFound, nil, 0},
+			{http.MethodPost, "/vehicles/a/restore", http.StatusBadRequest, nil, 0},
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			rp := repository.NewVehicleMap(map[int]internal.Vehicle{
+				1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford"}},
+				2: {Id: 2, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford"}},
+				3: {Id: 3, VehicleAttributes: internal.VehicleAttributes{Brand: "Fiat"}},
+			})
+			if _, err := rp.Delete(context.Background(), 1); err != nil {
+				t.Fatal(err)
+			}
+			hd := NewVehicleDefault(service.NewVehicleDefault(rp, nil, nil, nil, nil, nil, nil, nil), nil)
+			rt := chi.NewRouter()
+			rt.Use(middleware.IncludeDeleted)
+			rt.Get("/vehicles", hd.GetAll())
+			rt.Get("/vehicles/trash", hd.Trash())
+			rt.Get("/vehicles/{id}", hd.GetByID())
+			rt.Post("/vehicles/{id}/restore", hd.Restore())
+
+			for _, s := range c.steps {
+				res := httptest.NewRecorder()
+				rt.ServeHTTP(res, httptest.NewRequest(s.method, s.target, nil))
+				if res.Code != s.code {
+					t.Fatalf("%s %s: code = %d, want %d: %s", s.method, s.target, res.Code, s.code, res.Body.String())
+				}
+				if s.deleted == nil {
+					continue
+				}
+
+				// a vehicle or a set of vehicles by id
+				var body struct {
+					Data json.RawMessage `json:"data"`
+				}
+				if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+					t.Fatal(err)
+				}
+				vehicles := make(map[string]VehicleJSON)
+				var one VehicleJSON
+				if err := json.Unmarshal(body.Data, &one); err == nil && one.ID != 0 {
+					vehicles["one"] = one
+				} else if err := json.Unmarshal(body.Data, &vehicles); err != nil {
+					t.Fatalf("%s: %v", s.target, err)
+				}
+				if len(vehicles) != s.listed {
+					t.Fatalf("%s: %d vehicles, want %d", s.target, len(vehicles), s.listed)
+				}
+				deleted := 0
+				for _, v := range vehicles {
+					if v.DeletedAt != nil {
+						deleted++
+						if v.ID != s.deleted[0] {
+							t.Fatalf("%s: vehicle %d listed as deleted, want %v", s.target, v.ID, s.deleted)
+						}
+					}
+				}
+				if deleted != len(s.deleted) {
+					t.Fatalf("%s: %d vehicles listed as deleted, want %v", s.target, deleted, s.deleted)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"app/internal"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/bootcamp-go/web/response"
+	"github.com/go-chi/chi/v5"
+)
+
+// VINDecodeJSON is a struct that represents the body of POST /vin/decode
+type VINDecodeJSON struct {
+	VIN string `json:"vin"`
+}
+
+// VINInfoJSON is a struct that represents the information decoded from a VIN in JSON format
+type VINInfoJSON struct {
+	VIN             string `json:"vin"`
+	WMI             string `json:"wmi"`
+	VDS             string `json:"vds"`
+	VIS             string `json:"vis"`
+	Region          string `json:"region"`
+	Manufacturer    string `json:"manufacturer"`
+	CheckDigit      string `json:"check_digit"`
+	CheckDigitValid bool   `json:"check_digit_valid"`
+	ModelYears      []int  `json:"model_years"`
+	Serial          string `json:"serial"`
+}
+
+// FindByVIN is a method that returns a handler for the route GET /vehicles/vin/{vin}
+func (h *VehicleDefault) FindByVIN() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// process
+		v, err := h.sv.FindByVIN(r.Context(), chi.URLParam(r, "vin"))
+		if err != nil {
+			switch {
+			case errors.Is(err, internal.ErrVehicleNotFounded):
+				response.Text(w, http.StatusNotFound, err.Error())
+			case errors.Is(err, internal.ErrVINAmbiguous):
+				response.Text(w, http.StatusConflict, err.Error())
+			case errors.Is(err, context.DeadlineExceeded):
+				response.Text(w, http.StatusGatewayTimeout, err.Error())
+			default:
+				response.Text(w, http.StatusInternalServerError, err.Error())
+			}
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    newVehicleJSON(outVehicle(r, v)),
+		})
+	}
+}
+
+// DecodeVIN is a method that returns a handler for the route POST /vin/decode.
+// A wrong check digit is reported in the response, a malformed VIN is rejected
+func (h *VehicleDefault) DecodeVIN() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		var body VINDecodeJSON
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid body")
+			return
+		}
+
+		h.decodeVIN(w, r, body.VIN)
+	}
+}
+
+// DecodeVINByPath is a method that returns a handler for the route GET /vin/decode/{vin},
+// the same as POST /vin/decode for the callers allowed to read only, anonymous ones included
+func (h *VehicleDefault) DecodeVINByPath() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h.decodeVIN(w, r, chi.URLParam(r, "vin"))
+	}
+}
+
+// decodeVIN writes the information decoded from a VIN
+func (h *VehicleDefault) decodeVIN(w http.ResponseWriter, r *http.Request, number string) {
+	// process
+	info, err := h.sv.DecodeVIN(r.Context(), number)
+	if err != nil {
+		switch {
+		case errors.Is(err, internal.ErrInvalidVIN):
+			response.Text(w, http.StatusUnprocessableEntity, err.Error())
+		case errors.Is(err, context.DeadlineExceeded):
+			response.Text(w, http.StatusGatewayTimeout, err.Error())
+		default:
+			response.Text(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	// response
+	response.JSON(w, http.StatusOK, map[string]any{
+		"message": "vin decoded successfully",
+		"data": VINInfoJSON{
+			VIN:             info.VIN,
+			WMI:             info.WMI,
+			VDS:             info.VDS,
+			VIS:             info.VIS,
+			Region:          info.Region,
+			Manufacturer:    info.Manufacturer,
+			CheckDigit:      string(info.CheckDigit),
+			CheckDigitValid: info.CheckDigitValid,
+			ModelYears:      info.ModelYears,
+			Serial:          info.Serial,
+		},
+	})
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"app/internal"
+	"app/internal/auth"
+	"app/internal/middleware"
+	"app/internal/repository"
+	"app/internal/service"
+	"app/internal/vin"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestVehicleDefault_DecodeVIN(t *testing.T) {
+	sv := service.NewVehicleDefault(repository.NewVehicleMap(nil), nil, nil, nil, nil, nil, vin.NewDecoder(nil), nil)
+	hd := NewVehicleDefault(sv, nil)
+
+	// the routes behind the middlewares of the application, without credentials
+	read := middleware.NewAuthorization(auth.NewRBAC(nil)).Require(internal.PermissionVehiclesRead)
+	rt := chi.NewRouter()
+	rt.Use(middleware.NewAuthentication(auth.NewAPIKeyMap(nil), false).Handler)
+	rt.With(read).Get("/vin/decode/{vin}", hd.DecodeVINByPath())
+	rt.With(read).Post("/vin/decode", hd.DecodeVIN())
+
+	cases := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		code   int
+	}{
+		{"anonymous read", http.MethodGet, "/vin/decode/1HGCM82633A004352", "", http.StatusOK},
+		{"anonymous read of a malformed vin", http.MethodGet, "/vin/decode/1HGCM826", "", http.StatusUnprocessableEntity},
+		{"anonymous post", http.MethodPost, "/vin/decode", `{"vin":"1HGCM82633A004352"}`, http.StatusUnauthorized},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := httptest.NewRequest(c.method, c.target, strings.NewReader(c.body))
+			res := httptest.NewRecorder()
+			rt.ServeHTTP(res, req)
+			if res.Code != c.code {
+				t.Fatalf("code = %d, want %d: %s", res.Code, c.code, res.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+var (
+	ErrIdempotencyKeyReused   = errors.New("idempotency key already used with a different payload")
+	ErrIdempotencyKeyInFlight = errors.New("a request with the same idempotency key is being processed")
+)
+
+// IdempotentResponse is a struct that represents the stored response of an idempotent request
+type IdempotentResponse struct {
+	// Fingerprint identifies the request (method, path, query, unit system and body) that produced the response
+	Fingerprint string
+	// Completed is false while the first request is being processed
+	Completed bool
+	// StatusCode is the status code of the response
+	StatusCode int
+	// Header is the header of the response
+	Header http.Header
+	// Body is the body of the response
+	Body []byte
+	// ExpiresAt is the moment the key can be used again
+	ExpiresAt time.Time
+}
+
+// IdempotencyRepository is an interface that represents the storage of the idempotency keys
+type IdempotencyRepository interface {
+	// Reserve registers a key as in flight for the given fingerprint.
+	// When the key is already known its stored response is returned with found set to true
+	Reserve(ctx context.Context, key, fingerprint string, ttl time.Duration) (stored IdempotentResponse, found bool, err error)
+	// Complete stores the response of a reserved key
+	Complete(ctx context.Context, key string, res IdempotentResponse) (err error)
+	// Release forgets a reserved key so it can be retried
+	Release(ctx context.Context, key string) (err error)
+}
@@ -25,6 +25,7 @@ type VehicleJSON struct {
 	Brand           string  `json:"brand"`
 	Model           string  `json:"model"`
 	Registration    string  `json:"registration"`
+	VIN             string  `json:"vin"`
 	Color           string  `json:"color"`
 	FabricationYear int     `json:"year"`
 	Capacity        int     `json:"passengers"`
@@ -35,6 +36,7 @@ type VehicleJSON struct {
 	Height          float64 `json:"height"`
 	Length          float64 `json:"length"`
 	Width           float64 `json:"width"`
+	Status          string  `json:"status"`
 }
 
 // Load is a method that loads the vehicles
@@ -56,12 +58,20 @@ func (l *VehicleJSONFile) Load() (v map[int]internal.Vehicle, err error) {
 	// serialize vehicles
 	v = make(map[int]internal.Vehicle)
 	for _, vh := range vehiclesJSON {
+		// the vehicles without status are in service
+		status := internal.VehicleStatusInService
+		if vh.Status != "" {
+			if status, err = internal.ParseVehicleStatus(vh.Status); err != nil {
+				return nil, err
+			}
+		}
 		v[vh.Id] = internal.Vehicle{
 			Id: vh.Id,
 			VehicleAttributes: internal.VehicleAttributes{
 				Brand:           vh.Brand,
 				Model:           vh.Model,
 				Registration:    vh.Registration,
+				VIN:             vh.VIN,
 				Color:           vh.Color,
 				FabricationYear: vh.FabricationYear,
 				Capacity:        vh.Capacity,
@@ -75,6 +85,7 @@ func (l *VehicleJSONFile) Load() (v map[int]internal.Vehicle, err error) {
 					Width:  vh.Width,
 				},
 			},
+			Status: status,
 		}
 	}
 
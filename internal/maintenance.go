@@ -0,0 +1,127 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrMaintenanceRecordNotFound   = errors.New("maintenance record not found")
+	ErrMaintenanceScheduleNotFound = errors.New("maintenance schedule not found")
+	ErrInvalidMaintenance          = errors.New("invalid maintenance")
+)
+
+// MaintenanceRecord is a struct that represents a service done to a vehicle
+type MaintenanceRecord struct {
+	// Id is the unique identifier of the record, given by the repository
+	Id int
+	// VehicleID is the id of the vehicle serviced
+	VehicleID int
+	// Date is the moment of the service
+	Date time.Time
+	// Odometer is the distance travelled by the vehicle at the service, in km
+	Odometer float64
+	// Type is the kind of service (e.g. oil change), schedules match it by normalized key
+	Type string
+	// Cost is the cost of the service
+	Cost float64
+	// Notes are free comments about the service
+	Notes string
+}
+
+// MaintenanceSchedule is a struct that represents a service that recurs every some time or distance,
+// whichever comes first. A zero interval is not applied
+type MaintenanceSchedule struct {
+	// Id is the unique identifier of the schedule, given by the repository
+	Id int
+	// VehicleID is the id of the vehicle
+	VehicleID int
+	// Type is the kind of service
+	Type string
+	// IntervalDays is the number of days between services
+	IntervalDays int
+	// IntervalDistance is the distance between services, in km
+	IntervalDistance float64
+	// Since and SinceOdometer are the moment and the odometer the first service is counted from
+	Since         time.Time
+	SinceOdometer float64
+}
+
+// MaintenanceDue is a struct that represents when the next service of a schedule is due
+type MaintenanceDue struct {
+	// Schedule is the schedule of the service
+	Schedule MaintenanceSchedule
+	// LastService is the last service of the type of the schedule (nil when never serviced)
+	LastService *MaintenanceRecord
+	// Date is the moment the service is due (nil without interval of days)
+	Date *time.Time
+	// Odometer is the odometer at which the service is due, in km (nil without interval of distance)
+	Odometer *float64
+	// CurrentOdometer is the last known odometer of the vehicle, in km
+	CurrentOdometer float64
+	// Overdue is true when the date or the odometer is already reached
+	Overdue bool
+}
+
+// NextDue is a method that returns when the next service of the schedule is due after the last one
+// of its type (nil when never serviced)
+func (s MaintenanceSchedule) NextDue(last *MaintenanceRecord, currentOdometer float64, now time.Time) MaintenanceDue {
+	due := MaintenanceDue{Schedule: s, LastService: last, CurrentOdometer: currentOdometer}
+
+	since, sinceOdometer := s.Since, s.SinceOdometer
+	if last != nil {
+		since, sinceOdometer = last.Date, last.Odometer
+	}
+	if s.IntervalDays > 0 {
+		date := since.AddDate(0, 0, s.IntervalDays)
+		due.Date = &date
+		due.Overdue = !now.Before(date)
+	}
+	if s.IntervalDistance > 0 {
+		odometer := sinceOdometer + s.IntervalDistance
+		due.Odometer = &odometer
+		due.Overdue = due.Overdue || currentOdometer >= odometer
+	}
+	return due
+}
+
+// Within is a method that checks if the service is overdue or due within the given time or distance
+func (d MaintenanceDue) Within(now time.Time, window time.Duration, distance float64) bool {
+	return d.Overdue ||
+		(d.Date != nil && !now.Add(window).Before(*d.Date)) ||
+		(d.Odometer != nil && d.CurrentOdometer+distance >= *d.Odometer)
+}
+
+// MaintenanceRepository is an interface that represents a repository of maintenance records and schedules
+type MaintenanceRepository interface {
+	// FindRecords returns the records of a vehicle, oldest first
+	FindRecords(ctx context.Context, vehicleID int) (r []MaintenanceRecord, err error)
+	// CreateRecord adds a record with the next id
+	CreateRecord(ctx context.Context, record MaintenanceRecord) (r MaintenanceRecord, err error)
+	// DeleteRecord removes a record
+	DeleteRecord(ctx context.Context, id int) (err error)
+	// FindSchedules returns the schedules of a vehicle, or of every vehicle when vehicleID is 0, sorted by id
+	FindSchedules(ctx context.Context, vehicleID int) (s []MaintenanceSchedule, err error)
+	// CreateSchedule adds a schedule with the next id
+	CreateSchedule(ctx context.Context, schedule MaintenanceSchedule) (s MaintenanceSchedule, err error)
+	// DeleteSchedule removes a schedule
+	DeleteSchedule(ctx context.Context, id int) (err error)
+	VehicleDependentRepository
+}
+
+// MaintenanceService is an interface that represents a service of maintenance
+type MaintenanceService interface {
+	// FindByVehicle returns the records of a vehicle and when the services of its schedules are due
+	FindByVehicle(ctx context.Context, vehicleID int) (r []MaintenanceRecord, due []MaintenanceDue, err error)
+	// CreateRecord records a service of a vehicle
+	CreateRecord(ctx context.Context, record MaintenanceRecord) (r MaintenanceRecord, err error)
+	// DeleteRecord removes a record of a vehicle
+	DeleteRecord(ctx context.Context, vehicleID int, id int) (err error)
+	// CreateSchedule adds a recurring service to a vehicle
+	CreateSchedule(ctx context.Context, schedule MaintenanceSchedule) (s MaintenanceSchedule, err error)
+	// DeleteSchedule removes a recurring service of a vehicle
+	DeleteSchedule(ctx context.Context, vehicleID int, id int) (err error)
+	// FindDue returns the services overdue or due within the given time or distance, overdue ones and earliest dates first
+	FindDue(ctx context.Context, window time.Duration, distance float64) (d []MaintenanceDue, err error)
+}
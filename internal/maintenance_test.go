@@ -0,0 +1,77 @@
+package internal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMaintenanceSchedule_NextDue(t *testing.T) {
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	schedule := MaintenanceSchedule{Type: "oil", IntervalDays: 180, IntervalDistance: 10000, Since: since, SinceOdometer: 5000}
+	last := &MaintenanceRecord{Type: "oil", Date: since.AddDate(0, 3, 0), Odometer: 9000}
+
+	cases := []struct {
+		name         string
+		schedule     MaintenanceSchedule
+		last         *MaintenanceRecord
+		odometer     float64
+		now          time.Time
+		wantDate     *time.Time
+		wantOdometer *float64
+		wantOverdue  bool
+	}{
+		{"from the start", schedule, nil, 6000, since, ptr(since.AddDate(0, 0, 180)), ptr(15000.0), false},
+		{"from the last service", schedule, last, 10000, since, ptr(last.Date.AddDate(0, 0, 180)), ptr(19000.0), false},
+		{"overdue by date", schedule, nil, 6000, since.AddDate(0, 0, 180), ptr(since.AddDate(0, 0, 180)), ptr(15000.0), true},
+		{"overdue by distance", schedule, nil, 15000, since, ptr(since.AddDate(0, 0, 180)), ptr(15000.0), true},
+		{"by date only", MaintenanceSchedule{IntervalDays: 30, Since: since}, nil, 1e6, since, ptr(since.AddDate(0, 0, 30)), nil, false},
+		{"by distance only", MaintenanceSchedule{IntervalDistance: 1000, Since: since}, nil, 999, since.AddDate(10, 0, 0), nil, ptr(1000.0), false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			due := c.schedule.NextDue(c.last, c.odometer, c.now)
+			if !equalPtr(due.Date, c.wantDate, time.Time.Equal) || !equalPtr(due.Odometer, c.wantOdometer, func(a, b float64) bool { return a == b }) {
+				t.Fatalf("NextDue() date %v odometer %v, want %v %v", due.Date, due.Odometer, c.wantDate, c.wantOdometer)
+			}
+			if due.Overdue != c.wantOverdue || due.LastService != c.last || due.CurrentOdometer != c.odometer {
+				t.Fatalf("NextDue() = %+v", due)
+			}
+		})
+	}
+}
+
+func TestMaintenanceDue_Within(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	date := now.AddDate(0, 0, 10)
+	odometer := 10000.0
+
+	cases := []struct {
+		name     string
+		due      MaintenanceDue
+		window   time.Duration
+		distance float64
+		want     bool
+	}{
+		{"overdue", MaintenanceDue{Overdue: true}, 0, 0, true},
+		{"date within the window", MaintenanceDue{Date: &date}, 10 * 24 * time.Hour, 0, true},
+		{"date beyond the window", MaintenanceDue{Date: &date}, 9 * 24 * time.Hour, 0, false},
+		{"odometer within the distance", MaintenanceDue{Odometer: &odometer, CurrentOdometer: 9500}, 0, 500, true},
+		{"odometer beyond the distance", MaintenanceDue{Odometer: &odometer, CurrentOdometer: 9500}, 0, 499, false},
+		{"no schedule", MaintenanceDue{}, 24 * time.Hour, 1000, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := c.due.Within(now, c.window, c.distance); got != c.want {
+				t.Fatalf("Within() = %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+// equalPtr compares two optional values
+func equalPtr[T any](a, b *T, equal func(a, b T) bool) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return equal(*a, *b)
+}
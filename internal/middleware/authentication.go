@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"app/internal"
+	"errors"
+	"net/http"
+
+	"github.com/bootcamp-go/web/response"
+)
+
+// NewAuthentication is a function that returns a new instance of Authentication
+func NewAuthentication(au internal.Authenticator, requireForReads bool) *Authentication {
+	return &Authentication{au: au, requireForReads: requireForReads}
+}
+
+// Authentication is a struct that identifies the caller of every request
+type Authentication struct {
+	// au is the authenticator used to identify the caller
+	au internal.Authenticator
+	// requireForReads rejects anonymous GET, HEAD and OPTIONS requests too
+	requireForReads bool
+}
+
+// Handler is a method that returns the middleware.
+// Invalid credentials are always rejected, missing credentials only on writes unless requireForReads is set
+func (m *Authentication) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p, err := m.au.Authenticate(r)
+		switch {
+		case err == nil:
+			r = r.WithContext(internal.ContextWithPrincipal(r.Context(), p))
+		case errors.Is(err, internal.ErrUnauthenticated):
+			if m.requireForReads || !isRead(r.Method) {
+				unauthorized(w, err)
+				return
+			}
+		default:
+			unauthorized(w, err)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// isRead returns true for methods that do not modify resources
+func isRead(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// unauthorized writes a 401 response with the authentication challenge
+func unauthorized(w http.ResponseWriter, err error) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="vehicles"`)
+	response.Error(w, http.StatusUnauthorized, err.Error())
+}
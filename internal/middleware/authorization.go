@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"app/internal"
+	"net/http"
+
+	"github.com/bootcamp-go/web/response"
+)
+
+// NewAuthorization is a function that returns a new instance of Authorization
+func NewAuthorization(az internal.Authorizer) *Authorization {
+	return &Authorization{az: az}
+}
+
+// Authorization is a struct that enforces the permissions required by each route
+type Authorization struct {
+	// az is the policy used to take the decisions
+	az internal.Authorizer
+}
+
+// Require is a method that returns a middleware that only lets through callers granted the permission
+func (m *Authorization) Require(permission string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			p, ok := internal.PrincipalFromContext(r.Context())
+			if err := m.az.Authorize(p, permission); err != nil {
+				// anonymous callers are asked to authenticate instead
+				if !ok {
+					unauthorized(w, internal.ErrUnauthenticated)
+					return
+				}
+				response.Error(w, http.StatusForbidden, err.Error())
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
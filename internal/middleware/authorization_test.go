@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"app/internal"
+	"app/internal/auth"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAuthorization_Require(t *testing.T) {
+	cases := []struct {
+		name       string
+		principal  *internal.Principal
+		permission string
+		want       int
+	}{
+		{"anonymous granted", nil, internal.PermissionVehiclesRead, http.StatusOK},
+		{"anonymous denied", nil, internal.PermissionVehiclesCreate, http.StatusUnauthorized},
+		{"authenticated granted", &internal.Principal{Subject: "o", Roles: []string{"fleet-operator"}}, internal.PermissionVehiclesCreate, http.StatusOK},
+		{"authenticated denied", &internal.Principal{Subject: "v", Roles: []string{"viewer"}}, internal.PermissionVehiclesCreate, http.StatusForbidden},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			mw := NewAuthorization(auth.NewRBAC(nil))
+			hd := mw.Require(c.permission)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+			req := httptest.NewRequest("POST", "/vehicles", nil)
+			if c.principal != nil {
+				req = req.WithContext(internal.ContextWithPrincipal(req.Context(), *c.principal))
+			}
+			res := httptest.NewRecorder()
+			hd.ServeHTTP(res, req)
+			if res.Code != c.want {
+				t.Fatalf("code = %d, want %d", res.Code, c.want)
+			}
+			if c.want == http.StatusUnauthorized && res.Header().Get("WWW-Authenticate") == "" {
+				t.Fatal("no WWW-Authenticate")
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"app/internal"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/bootcamp-go/web/response"
+)
+
+// HeaderIdempotencyKey is the header carrying the idempotency key
+const HeaderIdempotencyKey = "Idempotency-Key"
+
+// NewIdempotency is a function that returns a new instance of Idempotency
+func NewIdempotency(rp internal.IdempotencyRepository, ttl time.Duration) *Idempotency {
+	return &Idempotency{rp: rp, ttl: ttl}
+}
+
+// Idempotency is a struct that replays the first response of the requests sharing an Idempotency-Key
+type Idempotency struct {
+	// rp is the storage of the keys
+	rp internal.IdempotencyRepository
+	// ttl is how long a key is remembered
+	ttl time.Duration
+}
+
+// Handler is a method that returns the middleware.
+// Requests without the header are not affected; keys are scoped by client
+func (m *Idempotency) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(HeaderIdempotencyKey)
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > 255 {
+			response.Error(w, http.StatusBadRequest, "idempotency key too long")
+			return
+		}
+
+		// fingerprint of the request, the body is restored for the handler.
+		// The query and the unit system change what the request does, so they are part of it
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid body")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		head := r.Method + " " + r.URL.Path + "?" + r.URL.RawQuery + "\n" + HeaderAcceptUnits + ": " + r.Header.Get(HeaderAcceptUnits) + "\n"
+		sum := sha256.Sum256(append([]byte(head), body...))
+		fingerprint := hex.EncodeToString(sum[:])
+
+		scopedKey := clientKey(r) + ":" + key
+		stored, found, err := m.rp.Reserve(r.Context(), scopedKey, fingerprint, m.ttl)
+		if err != nil {
+			response.Error(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if found {
+			switch {
+			case stored.Fingerprint != fingerprint:
+				response.Error(w, http.StatusUnprocessableEntity, internal.ErrIdempotencyKeyReused.Error())
+			case !stored.Completed:
+				response.Error(w, http.StatusConflict, internal.ErrIdempotencyKeyInFlight.Error())
+			default:
+				replay(w, stored)
+			}
+			return
+		}
+
+		// first request: record the response.
+		// Server errors, timeouts included, and panics are not remembered so that the request can be retried
+		rec := &recorder{ResponseWriter: w, statusCode: http.StatusOK}
+		defer func() {
+			if p := recover(); p != nil {
+				m.rp.Release(r.Context(), scopedKey)
+				panic(p)
+			}
+			if rec.statusCode >= http.StatusInternalServerError {
+				m.rp.Release(r.Context(), scopedKey)
+				return
+			}
+
+			// only the headers describing the body are replayed
+			header := make(http.Header)
+			for _, k := range []string{"Content-Type", "Location"} {
+				if v := w.Header().Values(k); len(v) > 0 {
+					header[k] = v
+				}
+			}
+			m.rp.Complete(r.Context(), scopedKey, internal.IdempotentResponse{
+				StatusCode: rec.statusCode,
+				Header:     header,
+				Body:       rec.body.Bytes(),
+			})
+		}()
+		next.ServeHTTP(rec, r)
+	})
+}
+
+// replay writes a stored response
+func replay(w http.ResponseWriter, stored internal.IdempotentResponse) {
+	for k, v := range stored.Header {
+		w.Header()[k] = v
+	}
+	w.Header().Set("Idempotent-Replayed", "true")
+	w.WriteHeader(stored.StatusCode)
+	w.Write(stored.Body)
+}
+
+// recorder is a http.ResponseWriter that keeps a copy of the response
+type recorder struct {
+	http.ResponseWriter
+	// statusCode is the status code written
+	statusCode int
+	// body is the copy of the body written
+	body bytes.Buffer
+}
+
+// WriteHeader is a method that records the status code
+func (r *recorder) WriteHeader(statusCode int) {
+	r.statusCode = statusCode
+	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write is a method that records the body
+func (r *recorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"app/internal/repository"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIdempotency_Handler(t *testing.T) {
+	type request struct {
+		target, body, units string
+		key                 string
+	}
+	first := request{target: "/vehicles?dry_run=false", body: `{"id":1}`, key: "k"}
+
+	cases := []struct {
+		name       string
+		status     int
+		second     request
+		wantStatus int
+		wantCalls  int
+		wantReplay bool
+	}{
+		{"replayed", http.StatusCreated, first, http.StatusCreated, 1, true},
+		{"other body", http.StatusCreated, request{target: first.target, body: `{"id":2}`, key: "k"}, http.StatusUnprocessableEntity, 1, false},
+		{"other query", http.StatusCreated, request{target: "/vehicles?dry_run=true", body: first.body, key: "k"}, http.StatusUnprocessableEntity, 1, false},
+		{"other units", http.StatusCreated, request{target: first.target, body: first.body, units: "imperial", key: "k"}, http.StatusUnprocessableEntity, 1, false},
+		{"other key", http.StatusCreated, request{target: first.target, body: first.body, key: "other"}, http.StatusCreated, 2, false},
+		{"server error retried", http.StatusInternalServerError, first, http.StatusInternalServerError, 2, false},
+		{"timeout retried", http.StatusGatewayTimeout, first, http.StatusGatewayTimeout, 2, false},
+		{"client error replayed", http.StatusBadRequest, first, http.StatusBadRequest, 1, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			calls := 0
+			hd := NewIdempotency(repository.NewIdempotencyMap(), time.Hour).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(c.status)
+				w.Write([]byte(`{"message":"done"}`))
+			}))
+
+			var res *httptest.ResponseRecorder
+			for _, rq := range []request{first, c.second} {
+				req := httptest.NewRequest(http.MethodPost, rq.target, strings.NewReader(rq.body))
+				req.RemoteAddr = "192.0.2.1:1234"
+				req.Header.Set(HeaderIdempotencyKey, rq.key)
+				if rq.units != "" {
+					req.Header.Set(HeaderAcceptUnits, rq.units)
+				}
+				res = httptest.NewRecorder()
+				hd.ServeHTTP(res, req)
+			}
+
+			if res.Code != c.wantStatus || calls != c.wantCalls {
+				t.Fatalf("second response %d after %d calls, want %d after %d", res.Code, calls, c.wantStatus, c.wantCalls)
+			}
+			if replayed := res.Header().Get("Idempotent-Replayed") == "true"; replayed != c.wantReplay {
+				t.Fatalf("replayed = %v, want %v", replayed, c.wantReplay)
+			}
+			if c.wantReplay && res.Body.String() != `{"message":"done"}` {
+				t.Fatalf("replayed body %q", res.Body.String())
+			}
+		})
+	}
+}
+
+func TestIdempotency_InFlight(t *testing.T) {
+	rp := repository.NewIdempotencyMap()
+	var res *httptest.ResponseRecorder
+	hd := NewIdempotency(rp, time.Hour).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the same request arrives while the first one is processed
+		req := httptest.NewRequest(http.MethodPost, "/vehicles", strings.NewReader("{}"))
+		req.RemoteAddr = r.RemoteAddr
+		req.Header.Set(HeaderIdempotencyKey, "k")
+		res = httptest.NewRecorder()
+		NewIdempotency(rp, time.Hour).Handler(http.NotFoundHandler()).ServeHTTP(res, req)
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/vehicles", strings.NewReader("{}"))
+	req.RemoteAddr = "192.0.2.1:1234"
+	req.Header.Set(HeaderIdempotencyKey, "k")
+	hd.ServeHTTP(httptest.NewRecorder(), req.WithContext(context.Background()))
+
+	if res.Code != http.StatusConflict {
+		t.Fatalf("concurrent request status %d, want %d", res.Code, http.StatusConflict)
+	}
+}
+
+func TestIdempotency_Panic(t *testing.T) {
+	calls := 0
+	hd := NewIdempotency(repository.NewIdempotencyMap(), time.Hour).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			panic("boom")
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	serve := func() (res *httptest.ResponseRecorder) {
+		defer func() { recover() }()
+		req := httptest.NewRequest(http.MethodPost, "/vehicles", strings.NewReader("{}"))
+		req.RemoteAddr = "192.0.2.1:1234"
+		req.Header.Set(HeaderIdempotencyKey, "k")
+		res = httptest.NewRecorder()
+		hd.ServeHTTP(res, req)
+		return
+	}
+
+	serve()
+	// the key of the panicking request was released, so the retry runs
+	if res := serve(); res.Code != http.StatusCreated || calls != 2 {
+		t.Fatalf("retry status %d after %d calls, want %d after 2", res.Code, calls, http.StatusCreated)
+	}
+}
@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"app/internal"
+	"net/http"
+	"strconv"
+)
+
+// IncludeDeleted is a middleware that makes the queries of the request return soft deleted
+// vehicles too when the include_deleted query param is true
+func IncludeDeleted(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if include, _ := strconv.ParseBool(r.URL.Query().Get("include_deleted")); include {
+			r = r.WithContext(internal.ContextWithIncludeDeleted(r.Context()))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
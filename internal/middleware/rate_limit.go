@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"app/internal"
+	"app/internal/ratelimit"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bootcamp-go/web/response"
+	"github.com/go-chi/chi/v5"
+)
+
+// NewRateLimit is a function that returns a new instance of RateLimit
+func NewRateLimit(defaultLimit internal.RateLimit, routes map[string]internal.RateLimit) *RateLimit {
+	// default routes
+	defaultRoutes := make(map[string]internal.RateLimit)
+	if routes != nil {
+		defaultRoutes = routes
+	}
+	return &RateLimit{defaultLimit: defaultLimit, routes: defaultRoutes, limiters: make(map[string]internal.RateLimiter)}
+}
+
+// RateLimit is a struct that limits the requests of every client per route
+type RateLimit struct {
+	// defaultLimit is the limit of the routes without a specific one
+	defaultLimit internal.RateLimit
+	// routes is a map of limits by route ("METHOD /pattern")
+	routes map[string]internal.RateLimit
+	// mu protects limiters
+	mu sync.Mutex
+	// limiters is a map of the limiter of every route, created on its first request
+	limiters map[string]internal.RateLimiter
+}
+
+// Handler is a method that returns the middleware limiting the route of the request, named after its method
+// and chi pattern (e.g. "GET /vehicles/{id}"). It must run once the route is matched, in the inline middlewares.
+// Clients are identified by their principal when authenticated or by their IP otherwise
+func (m *RateLimit) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		d := m.limiter(route(r)).Allow(clientKey(r))
+
+		w.Header().Set("RateLimit-Limit", strconv.Itoa(d.Limit))
+		w.Header().Set("RateLimit-Remaining", strconv.Itoa(d.Remaining))
+		w.Header().Set("RateLimit-Reset", strconv.Itoa(seconds(d.Reset)))
+		if !d.Allowed {
+			w.Header().Set("Retry-After", strconv.Itoa(seconds(d.RetryAfter)))
+			response.Error(w, http.StatusTooManyRequests, internal.ErrRateLimited.Error())
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// limiter is a method that returns the limiter of a route
+func (m *RateLimit) limiter(route string) internal.RateLimiter {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	rl, ok := m.limiters[route]
+	if !ok {
+		limit, ok := m.routes[route]
+		if !ok {
+			limit = m.defaultLimit
+		}
+		rl = ratelimit.NewTokenBucket(limit)
+		m.limiters[route] = rl
+	}
+	return rl
+}
+
+// route returns the route of a request as "METHOD /pattern".
+// Outside chi there is no pattern, and the requests of a method share the default limit
+func route(r *http.Request) string {
+	if rc := chi.RouteContext(r.Context()); rc != nil {
+		return r.Method + " " + rc.RoutePattern()
+	}
+	return r.Method
+}
+
+// clientKey returns the key identifying the caller of the request
+func clientKey(r *http.Request) string {
+	if p, ok := internal.PrincipalFromContext(r.Context()); ok {
+		return p.Method + ":" + p.Subject
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// seconds rounds a duration up to whole seconds
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"app/internal"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestRateLimit_Handler(t *testing.T) {
+	type request struct {
+		method, target, ip string
+	}
+	cases := []struct {
+		name     string
+		requests []request
+		want     []int
+	}{
+		{"default limit", []request{{"GET", "/vehicles", "a"}, {"GET", "/vehicles", "a"}, {"GET", "/vehicles", "a"}, {"GET", "/vehicles", "a"}},
+			[]int{200, 200, 200, 429}},
+		{"route limit", []request{{"POST", "/vehicles/batch", "a"}, {"POST", "/vehicles/batch", "a"}},
+			[]int{200, 429}},
+		{"a pattern shares the limit", []request{{"GET", "/vehicles/1", "a"}, {"GET", "/vehicles/2", "a"}, {"GET", "/vehicles/3", "a"}, {"GET", "/vehicles/4", "a"}},
+			[]int{200, 200, 200, 429}},
+		{"routes are limited apart", []request{{"POST", "/vehicles/batch", "a"}, {"GET", "/vehicles/1", "a"}, {"GET", "/vehicles", "a"}},
+			[]int{200, 200, 200}},
+		{"methods are limited apart", []request{{"POST", "/vehicles/batch", "a"}, {"GET", "/vehicles/batch", "a"}},
+			[]int{200, 200}},
+		{"clients are limited apart", []request{{"POST", "/vehicles/batch", "a"}, {"POST", "/vehicles/batch", "b"}},
+			[]int{200, 200}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			mw := NewRateLimit(internal.RateLimit{Requests: 3, Period: time.Minute}, map[string]internal.RateLimit{
+				"POST /vehicles/batch": {Requests: 1, Period: time.Minute},
+			})
+			ok := func(w http.ResponseWriter, r *http.Request) {}
+			rt := chi.NewRouter()
+			rt.Route("/vehicles", func(rt chi.Router) {
+				rt.With(mw.Handler).Get("/", ok)
+				rt.With(mw.Handler).Get("/{id}", ok)
+				rt.With(mw.Handler).Post("/batch", ok)
+			})
+
+			for i, rq := range c.requests {
+				req := httptest.NewRequest(rq.method, rq.target, nil)
+				req.RemoteAddr = rq.ip + ":1234"
+				res := httptest.NewRecorder()
+				rt.ServeHTTP(res, req)
+				if res.Code != c.want[i] {
+					t.Fatalf("request %d %s %s: code = %d, want %d", i, rq.method, rq.target, res.Code, c.want[i])
+				}
+				if res.Code == http.StatusTooManyRequests && res.Header().Get("Retry-After") == "" {
+					t.Fatalf("request %d: no Retry-After", i)
+				}
+			}
+		})
+	}
+}
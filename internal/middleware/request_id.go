@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"app/internal"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// HeaderRequestID is the header used to propagate the request id
+const HeaderRequestID = "X-Request-ID"
+
+// RequestID is a middleware that takes the request id from the X-Request-ID header,
+// or generates one, stores it in the context and echoes it in the response
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(HeaderRequestID)
+		if id == "" || len(id) > 128 {
+			b := make([]byte, 16)
+			rand.Read(b)
+			id = hex.EncodeToString(b)
+		}
+
+		w.Header().Set(HeaderRequestID, id)
+		next.ServeHTTP(w, r.WithContext(internal.ContextWithRequestID(r.Context(), id)))
+	})
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"app/internal"
+	"net/http"
+	"strings"
+
+	"github.com/bootcamp-go/web/response"
+)
+
+const (
+	// HeaderAcceptUnits is the header where the client asks for a unit system
+	HeaderAcceptUnits = "Accept-Units"
+	// HeaderContentUnits is the header where the unit system of the response is announced
+	HeaderContentUnits = "Content-Units"
+	// HeaderContentUnitSymbols is the header where the unit of every measure of the response is announced,
+	// as field=symbol pairs separated by commas
+	HeaderContentUnitSymbols = "Content-Unit-Symbols"
+)
+
+// symbolFields are the measures whose unit is announced: the attributes of the vehicles and the odometer distances
+var symbolFields = append(append([]string{}, internal.MeasuredFields...), "distance")
+
+// unitSymbols returns the value of the Content-Unit-Symbols header for a unit system
+func unitSymbols(u internal.UnitSystem) string {
+	pairs := make([]string, len(symbolFields))
+	for i, field := range symbolFields {
+		pairs[i] = field + "=" + u.Symbol(field)
+	}
+	return strings.Join(pairs, ", ")
+}
+
+// Units is a middleware that sets the unit system of the request from the units query param
+// or, when missing, the Accept-Units header. Requests and responses are in that system
+func Units(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s := r.URL.Query().Get("units")
+		if s == "" {
+			s = r.Header.Get(HeaderAcceptUnits)
+		}
+		u, err := internal.ParseUnitSystem(s)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		w.Header().Set(HeaderContentUnits, string(u))
+		w.Header().Set(HeaderContentUnitSymbols, unitSymbols(u))
+		w.Header().Add("Vary", HeaderAcceptUnits)
+		next.ServeHTTP(w, r.WithContext(internal.ContextWithUnits(r.Context(), u)))
+	})
+}
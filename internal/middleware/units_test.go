@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"app/internal"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUnits(t *testing.T) {
+	cases := []struct {
+		name    string
+		query   string
+		header  string
+		code    int
+		want    internal.UnitSystem
+		symbols string
+	}{
+		{"default", "", "", http.StatusOK, internal.UnitsMetric, "max_speed=km/h, weight=kg, height=cm, length=cm, width=cm, distance=km"},
+		{"header", "", "imperial", http.StatusOK, internal.UnitsImperial, "max_speed=mph, weight=lb, height=in, length=in, width=in, distance=mi"},
+		{"query over header", "?units=metric", "imperial", http.StatusOK, internal.UnitsMetric, "max_speed=km/h, weight=kg, height=cm, length=cm, width=cm, distance=km"},
+		{"invalid", "?units=si", "", http.StatusBadRequest, "", ""},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var got internal.UnitSystem
+			hd := Units(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = internal.UnitsFromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/vehicles"+c.query, nil)
+			if c.header != "" {
+				req.Header.Set(HeaderAcceptUnits, c.header)
+			}
+			res := httptest.NewRecorder()
+			hd.ServeHTTP(res, req)
+			if res.Code != c.code || got != c.want {
+				t.Fatalf("code = %d, units = %q, want %d, %q", res.Code, got, c.code, c.want)
+			}
+			if res.Header().Get(HeaderContentUnits) != string(c.want) || res.Header().Get(HeaderContentUnitSymbols) != c.symbols {
+				t.Fatalf("headers = %v", res.Header())
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package normalize
+
+import (
+	"app/internal"
+	"encoding/json"
+	"os"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// DefaultAliases are the aliases used when no alias file is configured, by attribute and alias
+var DefaultAliases = map[string]map[string]string{
+	"brand": {
+		"chevy":    "Chevrolet",
+		"vw":       "Volkswagen",
+		"mercedes": "Mercedes-Benz",
+		"benz":     "Mercedes-Benz",
+		"caddy":    "Cadillac",
+		"rolls":    "Rolls-Royce",
+	},
+	"fuel_type": {
+		"gas":        "gasoline",
+		"petrol":     "gasoline",
+		"bio-diesel": "biodiesel",
+		"bio diesel": "biodiesel",
+	},
+	"transmission": {
+		"auto":          "automatic",
+		"stick":         "manual",
+		"semi-auto":     "semi-automatic",
+		"semiautomatic": "semi-automatic",
+	},
+	"color": {
+		"grey": "Gray",
+	},
+}
+
+// LoadAliasesJSONFile is a function that loads the aliases from a JSON file
+// in the format {"attribute": {"alias": "canonical"}}
+func LoadAliasesJSONFile(path string) (aliases map[string]map[string]string, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	err = json.NewDecoder(file).Decode(&aliases)
+	return
+}
+
+// NewAliasTable is a function that returns a new instance of AliasTable
+func NewAliasTable(aliases map[string]map[string]string) *AliasTable {
+	// default aliases
+	if aliases == nil {
+		aliases = DefaultAliases
+	}
+
+	t := &AliasTable{
+		aliases: make(map[string]map[string]string),
+		known:   make(map[string]map[string]string),
+	}
+	for attribute, table := range aliases {
+		t.aliases[attribute] = make(map[string]string)
+		for alias, canonical := range table {
+			t.aliases[attribute][internal.NormalizeKey(alias)] = strings.TrimSpace(canonical)
+		}
+	}
+	for _, attribute := range internal.NormalizedAttributes {
+		t.known[attribute] = make(map[string]string)
+	}
+	return t
+}
+
+// AliasTable is a struct that normalizes attribute values with alias tables.
+// The display value of a key is the first one seen for it, unless a casing rule applies
+type AliasTable struct {
+	// mu protects known
+	mu sync.RWMutex
+	// aliases are the canonical values by attribute and alias key
+	aliases map[string]map[string]string
+	// known are the display values already seen by attribute and key
+	known map[string]map[string]string
+}
+
+// Value is a method that returns the canonical display value of an attribute
+func (t *AliasTable) Value(attribute, value string) string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	canonical, _ := t.canonical(attribute, value)
+	return canonical
+}
+
+// Vehicle is a method that returns the vehicle with its attributes in canonical form
+func (t *AliasTable) Vehicle(v internal.Vehicle) internal.Vehicle {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, field := range []*struct {
+		attribute string
+		value     *string
+	}{
+		{"brand", &v.Brand},
+		{"model", &v.Model},
+		{"color", &v.Color},
+		{"fuel_type", &v.FuelType},
+		{"transmission", &v.Transmission},
+	} {
+		canonical, key := t.canonical(field.attribute, *field.value)
+		if _, ok := t.known[field.attribute][key]; !ok && key != "" {
+			t.known[field.attribute][key] = canonical
+		}
+		*field.value = canonical
+	}
+	v.Registration = strings.TrimSpace(v.Registration)
+	v.VIN = internal.NormalizeVIN(v.VIN)
+	return v
+}
+
+// canonical is a method that resolves the display value and the key of a value, the caller must hold the lock
+func (t *AliasTable) canonical(attribute, value string) (canonical, key string) {
+	canonical = strings.Join(strings.Fields(value), " ")
+	key = internal.NormalizeKey(canonical)
+	if alias, ok := t.aliases[attribute][key]; ok {
+		canonical, key = alias, internal.NormalizeKey(alias)
+	}
+	if known, ok := t.known[attribute][key]; ok {
+		return known, key
+	}
+
+	// casing rules
+	switch attribute {
+	case "fuel_type", "transmission":
+		canonical = key
+	case "color":
+		canonical = titleCase(key)
+	}
+	return
+}
+
+// titleCase upper cases the first letter of every word
+func titleCase(s string) string {
+	words := strings.Fields(s)
+	for i, word := range words {
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		words[i] = string(runes)
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,80 @@
+package normalize
+
+import (
+	"app/internal"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAliasTable_Value(t *testing.T) {
+	cases := []struct {
+		attribute, value, want string
+	}{
+		{"brand", "chevy", "Chevrolet"},
+		{"brand", " VW ", "Volkswagen"},
+		{"brand", "Mercedes", "Mercedes-Benz"},
+		{"brand", "Ford  Motor", "Ford Motor"},
+		{"fuel_type", "Petrol", "gasoline"},
+		{"fuel_type", "bio   diesel", "biodiesel"},
+		{"fuel_type", "Electric", "electric"},
+		{"transmission", "STICK", "manual"},
+		{"color", "grey", "Gray"},
+		{"color", "dark blue", "Dark Blue"},
+		// an alias of another attribute does not apply
+		{"model", "chevy", "chevy"},
+	}
+	tb := NewAliasTable(nil)
+	for _, c := range cases {
+		t.Run(c.attribute+" "+c.value, func(t *testing.T) {
+			if got := tb.Value(c.attribute, c.value); got != c.want {
+				t.Fatalf("Value(%q, %q) = %q, want %q", c.attribute, c.value, got, c.want)
+			}
+		})
+	}
+}
+
+func TestAliasTable_Vehicle(t *testing.T) {
+	tb := NewAliasTable(nil)
+
+	// the first display value seen for a key is kept for the next ones
+	first := tb.Vehicle(internal.Vehicle{VehicleAttributes: internal.VehicleAttributes{
+		Brand: "vw", Model: "Gol  Trend", Color: "GREY", FuelType: "gas", Transmission: "auto", Registration: " AB123CD ", VIN: "1hgcm82633a004352",
+	}})
+	want := internal.VehicleAttributes{
+		Brand: "Volkswagen", Model: "Gol Trend", Color: "Gray", FuelType: "gasoline", Transmission: "automatic", Registration: "AB123CD", VIN: "1HGCM82633A004352",
+	}
+	if first.VehicleAttributes != want {
+		t.Fatalf("Vehicle() = %+v, want %+v", first.VehicleAttributes, want)
+	}
+	if second := tb.Vehicle(internal.Vehicle{VehicleAttributes: internal.VehicleAttributes{Model: "GOL trend"}}); second.Model != "Gol Trend" {
+		t.Fatalf("Vehicle() model = %q, want the known %q", second.Model, "Gol Trend")
+	}
+	if got := tb.Value("model", "gol TREND"); got != "Gol Trend" {
+		t.Fatalf("Value() = %q, want the known %q", got, "Gol Trend")
+	}
+}
+
+func TestLoadAliasesJSONFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "aliases.json")
+	if err := os.WriteFile(path, []byte(`{"brand": {"Merc": " Mercedes-Benz "}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	aliases, err := LoadAliasesJSONFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tb := NewAliasTable(aliases)
+	if got := tb.Value("brand", "MERC"); got != "Mercedes-Benz" {
+		t.Fatalf("Value() = %q, want %q", got, "Mercedes-Benz")
+	}
+	// the file replaces the default aliases
+	if got := tb.Value("brand", "chevy"); got != "chevy" {
+		t.Fatalf("Value() = %q, want %q", got, "chevy")
+	}
+
+	if _, err := LoadAliasesJSONFile(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Fatal("LoadAliasesJSONFile() of a missing file succeeded")
+	}
+}
@@ -0,0 +1,20 @@
+package internal
+
+import "strings"
+
+// NormalizedAttributes are the categorical attributes of a vehicle that are normalized
+var NormalizedAttributes = []string{"brand", "model", "color", "fuel_type", "transmission"}
+
+// Normalizer is an interface that brings attribute values to their canonical form
+type Normalizer interface {
+	// Value returns the canonical display value of an attribute, resolving aliases
+	Value(attribute, value string) string
+	// Vehicle returns the vehicle with its attributes in canonical form, its values become known
+	Vehicle(v Vehicle) Vehicle
+}
+
+// NormalizeKey is a function that returns the key used to compare attribute values:
+// lowercase, trimmed and with the inner whitespace collapsed
+func NormalizeKey(value string) string {
+	return strings.Join(strings.Fields(strings.ToLower(value)), " ")
+}
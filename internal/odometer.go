@@ -0,0 +1,111 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+)
+
+var (
+	ErrInvalidOdometerReading = errors.New("invalid odometer reading")
+	ErrOdometerDecreasing     = errors.New("odometer readings must not decrease, flag the reading as a replacement if the odometer was replaced or reset")
+)
+
+// OdometerReading is a struct that represents the odometer of a vehicle at a moment
+type OdometerReading struct {
+	// Id is the unique identifier of the reading, given by the repository
+	Id int
+	// VehicleID is the id of the vehicle
+	VehicleID int
+	// Date is the moment of the reading
+	Date time.Time
+	// Value is the distance shown by the odometer, in km
+	Value float64
+	// Replacement is true when the odometer was replaced or reset before the reading,
+	// so that it may be lower than the previous one
+	Replacement bool
+	// Notes are free comments about the reading
+	Notes string
+}
+
+// OdometerUsage is a struct that represents the usage of a vehicle derived from its odometer readings
+type OdometerUsage struct {
+	// Readings is the number of readings the usage is derived from
+	Readings int
+	// First and Last are the first and last readings (nil without readings)
+	First, Last *OdometerReading
+	// Distance is the distance travelled between the first and the last readings, in km,
+	// the replacements of the odometer do not count
+	Distance float64
+	// Days is the number of days between the first and the last readings
+	Days float64
+	// AverageDailyDistance is the distance travelled per day, in km (0 with less than a day of readings)
+	AverageDailyDistance float64
+	// NextService is the service of the vehicle due first after the last reading, projected at the average daily distance
+	// when due by distance (nil without schedules)
+	NextService *MaintenanceDue
+	// NextServiceDate is the date the next service is due or projected to be due
+	NextServiceDate *time.Time
+}
+
+// Usage is a function that derives the usage of a vehicle from its readings, oldest first
+func Usage(readings []OdometerReading) (u OdometerUsage) {
+	u.Readings = len(readings)
+	if len(readings) == 0 {
+		return
+	}
+	u.First, u.Last = &readings[0], &readings[len(readings)-1]
+	for i := 1; i < len(readings); i++ {
+		// a replaced odometer starts counting again from the reading
+		if !readings[i].Replacement {
+			u.Distance += readings[i].Value - readings[i-1].Value
+		}
+	}
+	u.Days = u.Last.Date.Sub(u.First.Date).Hours() / 24
+	if u.Days >= 1 {
+		u.AverageDailyDistance = u.Distance / u.Days
+	}
+	return
+}
+
+// maxProjectedDays is the longest projection a time.Duration can hold, about 292 years
+const maxProjectedDays = float64(math.MaxInt64) / float64(24*time.Hour)
+
+// Projected is a method that returns the date the service is due, or the date its odometer is reached
+// travelling the daily distance from a moment, whichever comes first (nil when none is known).
+// An odometer too far away to be reached in maxProjectedDays is not projected
+func (d MaintenanceDue) Projected(from time.Time, dailyDistance float64) *time.Time {
+	date := d.Date
+	if d.Odometer != nil && dailyDistance > 0 {
+		days := max(*d.Odometer-d.CurrentOdometer, 0) / dailyDistance
+		if days >= maxProjectedDays {
+			return date
+		}
+		projected := from.Add(time.Duration(days * float64(24*time.Hour)))
+		if date == nil || projected.Before(*date) {
+			date = &projected
+		}
+	}
+	return date
+}
+
+// OdometerRepository is an interface that represents a repository of odometer readings
+type OdometerRepository interface {
+	// FindReadings returns the readings of a vehicle, oldest first
+	FindReadings(ctx context.Context, vehicleID int) (r []OdometerReading, err error)
+	// Create adds a reading with the next id. It returns ErrOdometerDecreasing when the reading is lower than the previous one
+	// or greater than the next one, unless they are replacements
+	Create(ctx context.Context, reading OdometerReading) (r OdometerReading, err error)
+	VehicleDependentRepository
+}
+
+// OdometerService is an interface that represents a service of odometer readings
+type OdometerService interface {
+	// FindReadings returns the readings of a vehicle between two moments (zero means unbounded), oldest first
+	FindReadings(ctx context.Context, vehicleID int, from, to time.Time) (r []OdometerReading, err error)
+	// Create adds a reading of a vehicle
+	Create(ctx context.Context, reading OdometerReading) (r OdometerReading, err error)
+	// Usage returns the usage of a vehicle between two moments (zero means unbounded) and its next service
+	Usage(ctx context.Context, vehicleID int, from, to time.Time) (u OdometerUsage, err error)
+}
@@ -0,0 +1,57 @@
+package internal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMaintenanceDue_Projected(t *testing.T) {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	date := from.AddDate(0, 6, 0)
+	odometer := 6000.0
+
+	cases := []struct {
+		name          string
+		due           MaintenanceDue
+		dailyDistance float64
+		want          *time.Time
+	}{
+		{"no schedule", MaintenanceDue{}, 10, nil},
+		{"by date", MaintenanceDue{Date: &date}, 10, &date},
+		{"by distance", MaintenanceDue{Odometer: &odometer, CurrentOdometer: 1000}, 100, ptr(from.AddDate(0, 0, 50))},
+		{"distance first", MaintenanceDue{Date: &date, Odometer: &odometer, CurrentOdometer: 1000}, 100, ptr(from.AddDate(0, 0, 50))},
+		{"date first", MaintenanceDue{Date: &date, Odometer: &odometer, CurrentOdometer: 1000}, 1, &date},
+		{"without usage", MaintenanceDue{Odometer: &odometer, CurrentOdometer: 1000}, 0, nil},
+		{"beyond a duration", MaintenanceDue{Odometer: &odometer, CurrentOdometer: 1000}, 0.0009, nil},
+		{"beyond a duration with date", MaintenanceDue{Date: &date, Odometer: &odometer, CurrentOdometer: 1000}, 0.0009, &date},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := c.due.Projected(from, c.dailyDistance)
+			switch {
+			case got == nil && c.want == nil:
+			case got == nil || c.want == nil || !got.Equal(*c.want):
+				t.Fatalf("Projected() = %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestUsage(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 1, 1+d, 0, 0, 0, 0, time.UTC) }
+	readings := []OdometerReading{
+		{Date: day(0), Value: 1000},
+		{Date: day(5), Value: 1500},
+		{Date: day(6), Value: 100, Replacement: true},
+		{Date: day(10), Value: 600},
+	}
+	u := Usage(readings)
+	if u.Distance != 1000 || u.Days != 10 || u.AverageDailyDistance != 100 {
+		t.Fatalf("Usage() = %+v, want 1000 km over 10 days", u)
+	}
+	if u := Usage(nil); u.Readings != 0 || u.First != nil {
+		t.Fatalf("Usage(nil) = %+v, want zero", u)
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
@@ -0,0 +1,51 @@
+package internal
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrOwnerNotFound = errors.New("owner not found")
+	ErrOwnerInUse    = errors.New("owner has current or upcoming vehicles")
+)
+
+// Owner is a struct that represents the person or company responsible for vehicles
+type Owner struct {
+	// Id is the unique identifier of the owner, given by the repository
+	Id int
+	// Name is the name of the owner
+	Name string
+	// Email is the contact email of the owner
+	Email string
+	// Phone is the contact phone of the owner
+	Phone string
+}
+
+// OwnerRepository is an interface that represents a repository of owners
+type OwnerRepository interface {
+	// FindAll returns the owners sorted by id
+	FindAll(ctx context.Context) (o []Owner, err error)
+	// FindOne returns an owner by its id
+	FindOne(ctx context.Context, id int) (o Owner, err error)
+	// Create adds an owner with the next id
+	Create(ctx context.Context, owner Owner) (o Owner, err error)
+	// Update replaces the data of an owner
+	Update(ctx context.Context, owner Owner) (o Owner, err error)
+	// Delete removes an owner
+	Delete(ctx context.Context, id int) (err error)
+}
+
+// OwnerService is an interface that represents a service of owners
+type OwnerService interface {
+	// FindAll returns the owners sorted by id
+	FindAll(ctx context.Context) (o []Owner, err error)
+	// FindOne returns an owner by its id
+	FindOne(ctx context.Context, id int) (o Owner, err error)
+	// Create adds an owner
+	Create(ctx context.Context, owner Owner) (o Owner, err error)
+	// Update replaces the data of an owner
+	Update(ctx context.Context, owner Owner) (o Owner, err error)
+	// Delete removes an owner unless it has current or upcoming vehicles
+	Delete(ctx context.Context, id int) (err error)
+}
@@ -0,0 +1,44 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+var (
+	ErrUnauthenticated   = errors.New("authentication required")
+	ErrInvalidCredential = errors.New("invalid credentials")
+)
+
+// Principal is a struct that represents an authenticated caller
+type Principal struct {
+	// Subject is the identifier of the caller (api key owner or token subject)
+	Subject string
+	// Roles are the roles granted to the caller
+	Roles []string
+	// Method is the authentication method used (api_key or jwt)
+	Method string
+}
+
+// Authenticator is an interface that represents a way of identifying the caller of a request
+type Authenticator interface {
+	// Authenticate returns the principal of the request.
+	// It returns ErrUnauthenticated when the request carries no credentials for this authenticator
+	// and ErrInvalidCredential when they are present but not valid
+	Authenticate(r *http.Request) (p Principal, err error)
+}
+
+// principalKey is the key used to store the principal in a context
+type principalKey struct{}
+
+// ContextWithPrincipal returns a copy of ctx carrying the principal
+func ContextWithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns the principal stored in ctx, if any
+func PrincipalFromContext(ctx context.Context) (p Principal, ok bool) {
+	p, ok = ctx.Value(principalKey{}).(Principal)
+	return
+}
@@ -0,0 +1,40 @@
+package internal
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrRateLimited = errors.New("rate limit exceeded, retry later")
+)
+
+// RateLimit is a struct that represents how many requests a client can do in a period
+type RateLimit struct {
+	// Requests is the number of requests allowed per period
+	Requests int
+	// Period is the window in which Requests are allowed
+	Period time.Duration
+	// Burst is the maximum number of requests allowed at once (0 means Requests)
+	Burst int
+}
+
+// RateLimitDecision is a struct that represents the outcome of a rate limit check
+type RateLimitDecision struct {
+	// Allowed is true when the request can go through
+	Allowed bool
+	// Limit is the maximum number of requests in a burst
+	Limit int
+	// Remaining is the number of requests left
+	Remaining int
+	// Reset is the time until the quota is fully restored
+	Reset time.Duration
+	// RetryAfter is the time until the next request is allowed (only when not allowed)
+	RetryAfter time.Duration
+}
+
+// RateLimiter is an interface that represents a rate limiter keyed by client
+type RateLimiter interface {
+	// Allow consumes one request of the client identified by key
+	Allow(key string) (d RateLimitDecision)
+}
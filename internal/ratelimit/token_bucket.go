@@ -0,0 +1,106 @@
+package ratelimit
+
+import (
+	"app/internal"
+	"math"
+	"sync"
+	"time"
+)
+
+// NewTokenBucket is a function that returns a new instance of TokenBucket
+func NewTokenBucket(limit internal.RateLimit) *TokenBucket {
+	// default values
+	if limit.Requests <= 0 {
+		limit.Requests = 1
+	}
+	if limit.Period <= 0 {
+		limit.Period = time.Second
+	}
+	if limit.Burst <= 0 {
+		limit.Burst = limit.Requests
+	}
+
+	return &TokenBucket{
+		capacity: float64(limit.Burst),
+		rate:     float64(limit.Requests) / limit.Period.Seconds(),
+		period:   limit.Period,
+		buckets:  make(map[string]*bucket),
+		now:      time.Now,
+	}
+}
+
+// bucket is the state of a single client
+type bucket struct {
+	// tokens is the number of tokens left
+	tokens float64
+	// last is the last time the bucket was refilled
+	last time.Time
+}
+
+// TokenBucket is a struct that implements a RateLimiter with one token bucket per client
+type TokenBucket struct {
+	// mu protects buckets and lastSweep
+	mu sync.Mutex
+	// capacity is the maximum number of tokens of a bucket
+	capacity float64
+	// rate is the number of tokens added per second
+	rate float64
+	// period is the window of the limit, also used to forget idle clients
+	period time.Duration
+	// buckets is a map of buckets by client key
+	buckets map[string]*bucket
+	// lastSweep is the last time idle buckets were removed
+	lastSweep time.Time
+	// now returns the current time
+	now func() time.Time
+}
+
+// Allow is a method that consumes a token of the client bucket
+func (t *TokenBucket) Allow(key string) (d internal.RateLimitDecision) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	t.sweep(now)
+
+	b, ok := t.buckets[key]
+	if !ok {
+		b = &bucket{tokens: t.capacity, last: now}
+		t.buckets[key] = b
+	}
+
+	// refill
+	b.tokens = math.Min(t.capacity, b.tokens+now.Sub(b.last).Seconds()*t.rate)
+	b.last = now
+
+	d.Limit = int(t.capacity)
+	if b.tokens >= 1 {
+		b.tokens--
+		d.Allowed = true
+	} else {
+		d.RetryAfter = t.duration(1 - b.tokens)
+	}
+	d.Remaining = int(math.Floor(b.tokens))
+	d.Reset = t.duration(t.capacity - b.tokens)
+	return
+}
+
+// duration is a method that returns the time needed to refill the given number of tokens
+func (t *TokenBucket) duration(tokens float64) time.Duration {
+	return time.Duration(math.Ceil(tokens / t.rate * float64(time.Second)))
+}
+
+// sweep is a method that removes the buckets that have been idle long enough to be full again
+func (t *TokenBucket) sweep(now time.Time) {
+	if now.Sub(t.lastSweep) < t.period {
+		return
+	}
+	t.lastSweep = now
+
+	idle := t.duration(t.capacity)
+	for key, b := range t.buckets {
+		if now.Sub(b.last) >= idle {
+			delete(t.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package internal
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrInvalidRegistration       = errors.New("invalid registration, it does not match the plate format of any country")
+	ErrRegistrationAlreadyExists = errors.New("registration already in use by another vehicle")
+	ErrRegistrationAmbiguous     = errors.New("registration shared by several vehicles")
+)
+
+// NormalizePlate is a function that returns the key used to compare registrations:
+// upper case and without spaces, dashes or dots
+func NormalizePlate(plate string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\t', '-', '.':
+			return -1
+		}
+		return r
+	}, strings.ToUpper(plate))
+}
+
+// RegistrationValidator is an interface that checks registrations against the plate formats in force
+type RegistrationValidator interface {
+	// Validate returns the country whose format the plate matches, or ErrInvalidRegistration
+	Validate(plate string) (country string, err error)
+}
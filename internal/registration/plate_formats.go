@@ -0,0 +1,79 @@
+package registration
+
+import (
+	"app/internal"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+)
+
+// DefaultFormats are the plate formats used when no formats file is configured, by country.
+// Formats apply to normalized plates (upper case, without spaces, dashes or dots)
+var DefaultFormats = map[string][]string{
+	// Argentina: old AAA999 and Mercosur AA999AA
+	"AR": {`^[A-Z]{3}[0-9]{3}$`, `^[A-Z]{2}[0-9]{3}[A-Z]{2}$`},
+	// Germany: district, letters and digits
+	"DE": {`^[A-Z]{1,3}[A-Z]{1,2}[0-9]{1,4}E?$`},
+	// United Kingdom: current AA99AAA
+	"GB": {`^[A-Z]{2}[0-9]{2}[A-Z]{3}$`},
+	// United States: 2 to 8 letters and digits, it varies by state
+	"US": {`^[A-Z0-9]{2,8}$`},
+}
+
+// LoadFormatsJSONFile is a function that loads the plate formats from a JSON file
+// in the format {"country": ["regexp", ...]}
+func LoadFormatsJSONFile(path string) (formats map[string][]string, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	err = json.NewDecoder(file).Decode(&formats)
+	return
+}
+
+// NewPlateFormats is a function that returns a new instance of PlateFormats
+func NewPlateFormats(formats map[string][]string) (*PlateFormats, error) {
+	// default formats
+	if formats == nil {
+		formats = DefaultFormats
+	}
+
+	p := &PlateFormats{formats: make(map[string][]*regexp.Regexp)}
+	for country, expressions := range formats {
+		p.countries = append(p.countries, country)
+		for _, expression := range expressions {
+			re, err := regexp.Compile(expression)
+			if err != nil {
+				return nil, fmt.Errorf("invalid plate format of %s: %w", country, err)
+			}
+			p.formats[country] = append(p.formats[country], re)
+		}
+	}
+	sort.Strings(p.countries)
+	return p, nil
+}
+
+// PlateFormats is a struct that validates registrations against per-country plate formats
+type PlateFormats struct {
+	// countries are the countries in force, sorted so the first match is deterministic
+	countries []string
+	// formats are the compiled formats by country
+	formats map[string][]*regexp.Regexp
+}
+
+// Validate is a method that returns the first country whose format the plate matches
+func (p *PlateFormats) Validate(plate string) (country string, err error) {
+	key := internal.NormalizePlate(plate)
+	for _, country := range p.countries {
+		for _, re := range p.formats[country] {
+			if re.MatchString(key) {
+				return country, nil
+			}
+		}
+	}
+	return "", internal.ErrInvalidRegistration
+}
@@ -0,0 +1,81 @@
+package registration
+
+import (
+	"app/internal"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPlateFormats_Validate(t *testing.T) {
+	cases := []struct {
+		plate       string
+		wantCountry string
+		wantErr     error
+	}{
+		{"ABC123", "AR", nil},
+		{"ab-123-cd", "AR", nil},
+		{"B.MW 1234", "DE", nil},
+		{"M AB 123E", "DE", nil},
+		{"AB12 CDE", "GB", nil},
+		{"7ABC123", "US", nil},
+		{"A", "", internal.ErrInvalidRegistration},
+		{"ABCD-1234-XY", "", internal.ErrInvalidRegistration},
+		{"AB_123", "", internal.ErrInvalidRegistration},
+		{"", "", internal.ErrInvalidRegistration},
+	}
+	p, err := NewPlateFormats(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range cases {
+		t.Run(c.plate, func(t *testing.T) {
+			country, err := p.Validate(c.plate)
+			if country != c.wantCountry || !errors.Is(err, c.wantErr) {
+				t.Fatalf("Validate(%q) = %q, %v, want %q, %v", c.plate, country, err, c.wantCountry, c.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewPlateFormats(t *testing.T) {
+	if _, err := NewPlateFormats(map[string][]string{"XX": {`^[A-Z`}}); err == nil {
+		t.Fatal("NewPlateFormats() accepted an invalid expression")
+	}
+
+	// the first country in order wins when several formats match
+	p, err := NewPlateFormats(map[string][]string{"ZZ": {`^[0-9]+$`}, "AA": {`^[0-9]{3}$`}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if country, _ := p.Validate("123"); country != "AA" {
+		t.Fatalf("Validate() = %q, want %q", country, "AA")
+	}
+	if country, _ := p.Validate("1234"); country != "ZZ" {
+		t.Fatalf("Validate() = %q, want %q", country, "ZZ")
+	}
+}
+
+func TestLoadFormatsJSONFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "formats.json")
+	if err := os.WriteFile(path, []byte(`{"FR": ["^[A-Z]{2}[0-9]{3}[A-Z]{2}$"]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	formats, err := LoadFormatsJSONFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := NewPlateFormats(formats)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if country, err := p.Validate("AB-123-CD"); country != "FR" || err != nil {
+		t.Fatalf("Validate() = %q, %v, want %q", country, err, "FR")
+	}
+	// the file replaces the default formats
+	if _, err := p.Validate("ABC123"); !errors.Is(err, internal.ErrInvalidRegistration) {
+		t.Fatalf("Validate() error = %v, want %v", err, internal.ErrInvalidRegistration)
+	}
+}
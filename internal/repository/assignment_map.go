@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// NewAssignmentMap is a function that returns a new instance of AssignmentMap
+func NewAssignmentMap() *AssignmentMap {
+	return &AssignmentMap{
+		db:        make(map[int]internal.Assignment),
+		byVehicle: make(map[int][]int),
+	}
+}
+
+// AssignmentMap is a struct that represents an in-memory repository of assignments
+type AssignmentMap struct {
+	// mu protects db, byVehicle and lastID
+	mu sync.RWMutex
+	// db is a map of assignments by id
+	db map[int]internal.Assignment
+	// byVehicle is a map of the ids of the assignments by vehicle id
+	byVehicle map[int][]int
+	// lastID is the last id given to an assignment
+	lastID int
+}
+
+// sortAssignments returns the assignments oldest first
+func sortAssignments(a []internal.Assignment) []internal.Assignment {
+	sort.Slice(a, func(i, j int) bool {
+		if !a[i].From.Equal(a[j].From) {
+			return a[i].From.Before(a[j].From)
+		}
+		return a[i].Id < a[j].Id
+	})
+	return a
+}
+
+// FindByVehicle is a method that returns the assignments of a vehicle with the given role, oldest first
+func (r *AssignmentMap) FindByVehicle(ctx context.Context, vehicleID int, role internal.AssignmentRole) (a []internal.Assignment, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	a = make([]internal.Assignment, 0)
+	for _, id := range r.byVehicle[vehicleID] {
+		if assignment := r.db[id]; role == "" || assignment.Role == role {
+			a = append(a, assignment)
+		}
+	}
+	return sortAssignments(a), nil
+}
+
+// FindByPerson is a method that returns the assignments of an owner or a driver, oldest first
+func (r *AssignmentMap) FindByPerson(ctx context.Context, role internal.AssignmentRole, personID int) (a []internal.Assignment, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	a = make([]internal.Assignment, 0)
+	for _, assignment := range r.db {
+		if assignment.Role == role && assignment.PersonID == personID {
+			a = append(a, assignment)
+		}
+	}
+	return sortAssignments(a), nil
+}
+
+// FindActive is a method that returns the assignments of a role in effect at a moment by vehicle id
+func (r *AssignmentMap) FindActive(ctx context.Context, role internal.AssignmentRole, at time.Time) (a map[int]internal.Assignment, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	a = make(map[int]internal.Assignment)
+	for _, assignment := range r.db {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if assignment.Role == role && assignment.ActiveAt(at) {
+			a[assignment.VehicleID] = assignment
+		}
+	}
+	return
+}
+
+// Create is a method that adds an assignment with the next id, ending the open-ended assignment it takes over
+func (r *AssignmentMap) Create(ctx context.Context, assignment internal.Assignment) (a internal.Assignment, ended *internal.Assignment, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, id := range r.byVehicle[assignment.VehicleID] {
+		other := r.db[id]
+		if other.Role != assignment.Role || !other.Overlaps(assignment) {
+			continue
+		}
+		// the current one is handed over when the new one starts
+		if other.To == nil && other.From.Before(assignment.From) {
+			to := assignment.From
+			other.To = &to
+			ended = &other
+			continue
+		}
+		return internal.Assignment{}, nil, fmt.Errorf("%w: assignment %d from %s", internal.ErrAssignmentOverlap, other.Id, other.From.Format(time.RFC3339))
+	}
+
+	if ended != nil {
+		r.db[ended.Id] = *ended
+	}
+	r.lastID++
+	assignment.Id = r.lastID
+	r.db[assignment.Id] = assignment
+	r.byVehicle[assignment.VehicleID] = append(r.byVehicle[assignment.VehicleID], assignment.Id)
+	return assignment, ended, nil
+}
+
+// End is a method that sets the end of an assignment
+func (r *AssignmentMap) End(ctx context.Context, id int, at time.Time) (a internal.Assignment, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	a, ok := r.db[id]
+	if !ok {
+		return internal.Assignment{}, internal.ErrAssignmentNotFound
+	}
+	if !at.After(a.From) {
+		return internal.Assignment{}, fmt.Errorf("%w: it can not end before it starts", internal.ErrInvalidAssignment)
+	}
+	if a.To != nil && a.To.Before(at) {
+		return internal.Assignment{}, fmt.Errorf("%w: it already ended", internal.ErrInvalidAssignment)
+	}
+	a.To = &at
+	r.db[id] = a
+	return
+}
+
+// DeleteByVehicles is a method that removes the assignments of the vehicles
+func (r *AssignmentMap) DeleteByVehicles(ctx context.Context, vehicleIDs []int) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, vehicleID := range vehicleIDs {
+		for _, id := range r.byVehicle[vehicleID] {
+			delete(r.db, id)
+		}
+		delete(r.byVehicle, vehicleID)
+	}
+	return
+}
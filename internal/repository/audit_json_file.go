@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"app/internal"
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// NewAuditJSONFile is a function that returns a new instance of AuditJSONFile.
+// The entries already in the file are loaded to answer the queries
+func NewAuditJSONFile(path string) (r *AuditJSONFile, err error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return
+	}
+
+	// load existing entries, one JSON document per line
+	db := make([]internal.AuditEntry, 0)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry internal.AuditEntry
+		if err = json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("audit file %s: %w", path, err)
+		}
+		db = append(db, entry)
+	}
+	if err = scanner.Err(); err != nil {
+		file.Close()
+		return
+	}
+
+	r = &AuditJSONFile{AuditSlice: NewAuditSlice(db), file: file}
+	return
+}
+
+// AuditJSONFile is a struct that represents an audit log persisted as JSON lines in a local file
+type AuditJSONFile struct {
+	// AuditSlice keeps the entries in memory to answer the queries
+	*AuditSlice
+	// file is the file where the entries are appended
+	file *os.File
+}
+
+// Append is a method that writes the entries to the file and then keeps them in memory
+func (r *AuditJSONFile) Append(ctx context.Context, entries ...internal.AuditEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// serialize all the entries first so that a batch is written at once
+	var buf []byte
+	for i := range entries {
+		entries[i].Id = len(r.db) + i + 1
+		b, err := json.Marshal(entries[i])
+		if err != nil {
+			return err
+		}
+		buf = append(append(buf, b...), '\n')
+	}
+
+	if _, err := r.file.Write(buf); err != nil {
+		return err
+	}
+	if err := r.file.Sync(); err != nil {
+		return err
+	}
+
+	r.append(entries)
+	return nil
+}
+
+// Close is a method that closes the underlying file
+func (r *AuditJSONFile) Close() error {
+	return r.file.Close()
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"sync"
+	"time"
+)
+
+// NewAuditSlice is a function that returns a new instance of AuditSlice
+func NewAuditSlice(db []internal.AuditEntry) *AuditSlice {
+	// default db
+	defaultDb := make([]internal.AuditEntry, 0)
+	if db != nil {
+		defaultDb = db
+	}
+	return &AuditSlice{db: defaultDb}
+}
+
+// AuditSlice is a struct that represents an in-memory audit log
+type AuditSlice struct {
+	// mu protects db
+	mu sync.RWMutex
+	// db is the list of entries in order of arrival
+	db []internal.AuditEntry
+}
+
+// Append is a method that adds entries to the log
+func (r *AuditSlice) Append(ctx context.Context, entries ...internal.AuditEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.append(entries)
+	return nil
+}
+
+// append is a method that sets the sequence of the entries and stores them, the caller must hold the lock
+func (r *AuditSlice) append(entries []internal.AuditEntry) {
+	for i := range entries {
+		entries[i].Id = len(r.db) + 1
+		r.db = append(r.db, entries[i])
+	}
+}
+
+// FindAll is a method that returns the entries between from and to
+func (r *AuditSlice) FindAll(ctx context.Context, from, to time.Time) (e []internal.AuditEntry, err error) {
+	return r.find(ctx, func(entry internal.AuditEntry) bool {
+		return inRange(entry.Timestamp, from, to)
+	})
+}
+
+// FindByVehicle is a method that returns the entries of a vehicle between from and to
+func (r *AuditSlice) FindByVehicle(ctx context.Context, vehicleID int, from, to time.Time) (e []internal.AuditEntry, err error) {
+	return r.find(ctx, func(entry internal.AuditEntry) bool {
+		return entry.VehicleID == vehicleID && inRange(entry.Timestamp, from, to)
+	})
+}
+
+// find is a method that returns the entries matching the filter
+func (r *AuditSlice) find(ctx context.Context, match func(entry internal.AuditEntry) bool) (e []internal.AuditEntry, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	e = make([]internal.AuditEntry, 0)
+	for _, entry := range r.db {
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
+		if match(entry) {
+			e = append(e, entry)
+		}
+	}
+	return
+}
+
+// inRange checks if t is between from and to, zero values meaning unbounded
+func inRange(t, from, to time.Time) bool {
+	if !from.IsZero() && t.Before(from) {
+		return false
+	}
+	if !to.IsZero() && t.After(to) {
+		return false
+	}
+	return true
+}
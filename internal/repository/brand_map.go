@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"sort"
+	"sync"
+)
+
+// NewBrandMap is a function that returns a new instance of BrandMap with the given brands and models
+func NewBrandMap(brands []internal.Brand, models []internal.VehicleModel) *BrandMap {
+	r := &BrandMap{
+		brands: make(map[string]internal.Brand),
+		models: make(map[string]map[string]internal.VehicleModel),
+	}
+	for _, b := range brands {
+		key := internal.NormalizeKey(b.Name)
+		if _, ok := r.brands[key]; key == "" || ok {
+			continue
+		}
+		r.brands[key] = b
+		r.models[key] = make(map[string]internal.VehicleModel)
+	}
+	for _, m := range models {
+		brandKey, key := internal.NormalizeKey(m.Brand), internal.NormalizeKey(m.Name)
+		brand, ok := r.brands[brandKey]
+		if !ok || key == "" {
+			continue
+		}
+		if _, ok := r.models[brandKey][key]; ok {
+			continue
+		}
+		m.Brand = brand.Name
+		r.models[brandKey][key] = m
+	}
+	return r
+}
+
+// BrandMap is a struct that represents an in-memory registry of brands and models
+type BrandMap struct {
+	// mu protects brands and models
+	mu sync.RWMutex
+	// brands is a map of brands by normalized name
+	brands map[string]internal.Brand
+	// models is a map of models by normalized brand and model names
+	models map[string]map[string]internal.VehicleModel
+}
+
+// FindAll is a method that returns the brands sorted by name
+func (r *BrandMap) FindAll(ctx context.Context) (b []internal.Brand, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	b = make([]internal.Brand, 0, len(r.brands))
+	for _, brand := range r.brands {
+		b = append(b, brand)
+	}
+	sort.Slice(b, func(i, j int) bool { return b[i].Name < b[j].Name })
+	return
+}
+
+// FindOne is a method that returns a brand by its name
+func (r *BrandMap) FindOne(ctx context.Context, name string) (b internal.Brand, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	b, ok := r.brands[internal.NormalizeKey(name)]
+	if !ok {
+		err = internal.ErrBrandNotFound
+	}
+	return
+}
+
+// Create is a method that adds a brand
+func (r *BrandMap) Create(ctx context.Context, brand internal.Brand) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := internal.NormalizeKey(brand.Name)
+	if _, ok := r.brands[key]; ok {
+		return internal.ErrBrandAlreadyExists
+	}
+	r.brands[key] = brand
+	r.models[key] = make(map[string]internal.VehicleModel)
+	return
+}
+
+// Update is a method that replaces the manufacturer info of a brand, its name is kept
+func (r *BrandMap) Update(ctx context.Context, brand internal.Brand) (b internal.Brand, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := internal.NormalizeKey(brand.Name)
+	b, ok := r.brands[key]
+	if !ok {
+		err = internal.ErrBrandNotFound
+		return
+	}
+	b.Manufacturer = brand.Manufacturer
+	b.Country = brand.Country
+	r.brands[key] = b
+	return
+}
+
+// Delete is a method that removes a brand and its models
+func (r *BrandMap) Delete(ctx context.Context, name string) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := internal.NormalizeKey(name)
+	if _, ok := r.brands[key]; !ok {
+		return internal.ErrBrandNotFound
+	}
+	delete(r.brands, key)
+	delete(r.models, key)
+	return
+}
+
+// FindModels is a method that returns the models of a brand sorted by name
+func (r *BrandMap) FindModels(ctx context.Context, brand string) (m []internal.VehicleModel, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	models, ok := r.models[internal.NormalizeKey(brand)]
+	if !ok {
+		return nil, internal.ErrBrandNotFound
+	}
+	m = make([]internal.VehicleModel, 0, len(models))
+	for _, model := range models {
+		m = append(m, model)
+	}
+	sort.Slice(m, func(i, j int) bool { return m[i].Name < m[j].Name })
+	return
+}
+
+// FindModel is a method that returns a model of a brand by its name
+func (r *BrandMap) FindModel(ctx context.Context, brand string, name string) (m internal.VehicleModel, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	models, ok := r.models[internal.NormalizeKey(brand)]
+	if !ok {
+		err = internal.ErrBrandNotFound
+		return
+	}
+	m, ok = models[internal.NormalizeKey(name)]
+	if !ok {
+		err = internal.ErrModelNotFound
+	}
+	return
+}
+
+// CreateModel is a method that adds a model to its brand
+func (r *BrandMap) CreateModel(ctx context.Context, model internal.VehicleModel) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	brandKey := internal.NormalizeKey(model.Brand)
+	models, ok := r.models[brandKey]
+	if !ok {
+		return internal.ErrBrandNotFound
+	}
+	key := internal.NormalizeKey(model.Name)
+	if _, ok := models[key]; ok {
+		return internal.ErrModelAlreadyExists
+	}
+	model.Brand = r.brands[brandKey].Name
+	models[key] = model
+	return
+}
+
+// UpdateModel is a method that replaces the specs of a model, its brand and name are kept
+func (r *BrandMap) UpdateModel(ctx context.Context, model internal.VehicleModel) (m internal.VehicleModel, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	models, ok := r.models[internal.NormalizeKey(model.Brand)]
+	if !ok {
+		err = internal.ErrBrandNotFound
+		return
+	}
+	key := internal.NormalizeKey(model.Name)
+	current, ok := models[key]
+	if !ok {
+		err = internal.ErrModelNotFound
+		return
+	}
+	model.Brand, model.Name = current.Brand, current.Name
+	models[key] = model
+	return model, nil
+}
+
+// DeleteModel is a method that removes a model
+func (r *BrandMap) DeleteModel(ctx context.Context, brand string, name string) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	models, ok := r.models[internal.NormalizeKey(brand)]
+	if !ok {
+		return internal.ErrBrandNotFound
+	}
+	key := internal.NormalizeKey(name)
+	if _, ok := models[key]; !ok {
+		return internal.ErrModelNotFound
+	}
+	delete(models, key)
+	return
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// NewCatalogMap is a function that returns a new instance of CatalogMap with the given entries
+func NewCatalogMap(entries map[internal.CatalogKind][]internal.CatalogEntry) *CatalogMap {
+	r := &CatalogMap{db: make(map[internal.CatalogKind]map[string]internal.CatalogEntry)}
+	for _, kind := range internal.CatalogKinds {
+		r.db[kind] = make(map[string]internal.CatalogEntry)
+	}
+	for kind, list := range entries {
+		if _, ok := r.db[kind]; !ok {
+			continue
+		}
+		for _, entry := range list {
+			key := internal.NormalizeKey(entry.Value)
+			if _, ok := r.db[kind][key]; key == "" || ok {
+				continue
+			}
+			entry.Value = strings.TrimSpace(entry.Value)
+			r.db[kind][key] = entry
+		}
+	}
+	return r
+}
+
+// CatalogMap is a struct that represents an in-memory repository of catalogs
+type CatalogMap struct {
+	// mu protects db
+	mu sync.RWMutex
+	// db is a map of entries by catalog and normalized key
+	db map[internal.CatalogKind]map[string]internal.CatalogEntry
+}
+
+// catalog is a method that returns the entries of a catalog, the caller must hold the lock
+func (r *CatalogMap) catalog(kind internal.CatalogKind) (map[string]internal.CatalogEntry, error) {
+	entries, ok := r.db[kind]
+	if !ok {
+		return nil, internal.ErrCatalogNotFound
+	}
+	return entries, nil
+}
+
+// FindAll is a method that returns the entries of a catalog sorted by value
+func (r *CatalogMap) FindAll(ctx context.Context, kind internal.CatalogKind) (e []internal.CatalogEntry, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries, err := r.catalog(kind)
+	if err != nil {
+		return
+	}
+	e = make([]internal.CatalogEntry, 0, len(entries))
+	for _, entry := range entries {
+		e = append(e, entry)
+	}
+	sort.Slice(e, func(i, j int) bool { return e[i].Value < e[j].Value })
+	return
+}
+
+// FindOne is a method that returns an entry of a catalog by its value
+func (r *CatalogMap) FindOne(ctx context.Context, kind internal.CatalogKind, value string) (e internal.CatalogEntry, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries, err := r.catalog(kind)
+	if err != nil {
+		return
+	}
+	e, ok := entries[internal.NormalizeKey(value)]
+	if !ok {
+		err = internal.ErrCatalogEntryNotFound
+	}
+	return
+}
+
+// Create is a method that adds an entry to a catalog
+func (r *CatalogMap) Create(ctx context.Context, kind internal.CatalogKind, entry internal.CatalogEntry) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries, err := r.catalog(kind)
+	if err != nil {
+		return
+	}
+	key := internal.NormalizeKey(entry.Value)
+	if _, ok := entries[key]; ok {
+		return internal.ErrCatalogEntryAlreadyExists
+	}
+	entries[key] = entry
+	return
+}
+
+// Update is a method that replaces the description of an entry
+func (r *CatalogMap) Update(ctx context.Context, kind internal.CatalogKind, value string, description string) (e internal.CatalogEntry, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries, err := r.catalog(kind)
+	if err != nil {
+		return
+	}
+	key := internal.NormalizeKey(value)
+	e, ok := entries[key]
+	if !ok {
+		err = internal.ErrCatalogEntryNotFound
+		return
+	}
+	e.Description = description
+	entries[key] = e
+	return
+}
+
+// Delete is a method that removes an entry from a catalog
+func (r *CatalogMap) Delete(ctx context.Context, kind internal.CatalogKind, value string) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries, err := r.catalog(kind)
+	if err != nil {
+		return
+	}
+	key := internal.NormalizeKey(value)
+	if _, ok := entries[key]; !ok {
+		return internal.ErrCatalogEntryNotFound
+	}
+	delete(entries, key)
+	return
+}
@@ -0,0 +1,13 @@
+package repository
+
+import "app/internal"
+
+// NewDriverMap is a function that returns a new instance of DriverMap
+func NewDriverMap(drivers map[int]internal.Driver) *DriverMap {
+	return &DriverMap{newPersonMap(drivers, func(d *internal.Driver) *int { return &d.Id }, internal.ErrDriverNotFound)}
+}
+
+// DriverMap is a struct that represents an in-memory repository of drivers
+type DriverMap struct {
+	*personMap[internal.Driver]
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"sync"
+	"time"
+)
+
+// NewIdempotencyMap is a function that returns a new instance of IdempotencyMap
+func NewIdempotencyMap() *IdempotencyMap {
+	return &IdempotencyMap{
+		db:  make(map[string]internal.IdempotentResponse),
+		now: time.Now,
+	}
+}
+
+// IdempotencyMap is a struct that represents an in-memory storage of idempotency keys
+type IdempotencyMap struct {
+	// mu protects db
+	mu sync.Mutex
+	// db is a map of responses by key
+	db map[string]internal.IdempotentResponse
+	// lastSweep is the last time expired keys were removed
+	lastSweep time.Time
+	// now returns the current time
+	now func() time.Time
+}
+
+// Reserve is a method that registers the key as in flight unless it is already known.
+// An expired key is unknown even before it is swept
+func (r *IdempotencyMap) Reserve(ctx context.Context, key, fingerprint string, ttl time.Duration) (stored internal.IdempotentResponse, found bool, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	r.sweep(now)
+
+	if stored, found = r.db[key]; found && !now.After(stored.ExpiresAt) {
+		return
+	}
+
+	r.db[key] = internal.IdempotentResponse{
+		Fingerprint: fingerprint,
+		ExpiresAt:   now.Add(ttl),
+	}
+	return internal.IdempotentResponse{}, false, nil
+}
+
+// Complete is a method that stores the response of a reserved key, keeping its expiration
+func (r *IdempotencyMap) Complete(ctx context.Context, key string, res internal.IdempotentResponse) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reserved, ok := r.db[key]
+	if !ok {
+		return nil
+	}
+	res.Fingerprint = reserved.Fingerprint
+	res.ExpiresAt = reserved.ExpiresAt
+	res.Completed = true
+	r.db[key] = res
+	return nil
+}
+
+// Release is a method that forgets a key
+func (r *IdempotencyMap) Release(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.db, key)
+	return nil
+}
+
+// sweep is a method that removes the expired keys once a minute, the caller must hold the lock
+func (r *IdempotencyMap) sweep(now time.Time) {
+	if now.Sub(r.lastSweep) < time.Minute {
+		return
+	}
+	r.lastSweep = now
+
+	for key, res := range r.db {
+		if now.After(res.ExpiresAt) {
+			delete(r.db, key)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestIdempotencyMap_Reserve(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	rp := NewIdempotencyMap()
+	rp.now = func() time.Time { return now }
+
+	if _, found, _ := rp.Reserve(ctx, "k", "a", time.Hour); found {
+		t.Fatal("a new key was found")
+	}
+	rp.Complete(ctx, "k", internal.IdempotentResponse{StatusCode: 201})
+
+	cases := []struct {
+		name      string
+		after     time.Duration
+		wantFound bool
+	}{
+		{"before expiring", 30 * time.Minute, true},
+		{"at the expiration", time.Hour, true},
+		// within a minute of the last sweep, so only the check on read forgets it
+		{"expired", time.Hour + 30*time.Second, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			rp.now = func() time.Time { return now.Add(c.after) }
+			rp.lastSweep = rp.now()
+			stored, found, err := rp.Reserve(ctx, "k", "b", time.Hour)
+			if err != nil || found != c.wantFound {
+				t.Fatalf("Reserve() found = %v, err %v, want %v", found, err, c.wantFound)
+			}
+			if found && (stored.Fingerprint != "a" || !stored.Completed || stored.StatusCode != 201) {
+				t.Fatalf("Reserve() = %+v, want the completed response", stored)
+			}
+		})
+	}
+
+	// the expired key was reserved again for the new request
+	if stored, found, _ := rp.Reserve(ctx, "k", "c", time.Hour); !found || stored.Fingerprint != "b" || stored.Completed {
+		t.Fatalf("Reserve() = %+v, %v, want the new reservation", stored, found)
+	}
+}
+
+func TestIdempotencyMap_Release(t *testing.T) {
+	ctx := context.Background()
+	rp := NewIdempotencyMap()
+
+	rp.Reserve(ctx, "k", "a", time.Hour)
+	if err := rp.Release(ctx, "k"); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	// a released key is unknown, the retry reserves it again
+	if _, found, _ := rp.Reserve(ctx, "k", "a", time.Hour); found {
+		t.Fatal("a released key was found")
+	}
+	// completing a released key does not store it
+	rp.Release(ctx, "k")
+	rp.Complete(ctx, "k", internal.IdempotentResponse{StatusCode: 201})
+	if _, found, _ := rp.Reserve(ctx, "k", "a", time.Hour); found {
+		t.Fatal("a released key was completed")
+	}
+}
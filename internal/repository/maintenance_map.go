@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"sort"
+	"sync"
+)
+
+// NewMaintenanceMap is a function that returns a new instance of MaintenanceMap
+func NewMaintenanceMap() *MaintenanceMap {
+	return &MaintenanceMap{
+		records:   make(map[int]internal.MaintenanceRecord),
+		schedules: make(map[int]internal.MaintenanceSchedule),
+	}
+}
+
+// MaintenanceMap is a struct that represents an in-memory repository of maintenance records and schedules
+type MaintenanceMap struct {
+	// mu protects the records, the schedules and their last ids
+	mu sync.RWMutex
+	// records is a map of records by id
+	records map[int]internal.MaintenanceRecord
+	// schedules is a map of schedules by id
+	schedules map[int]internal.MaintenanceSchedule
+	// lastRecordID and lastScheduleID are the last ids given
+	lastRecordID, lastScheduleID int
+}
+
+// FindRecords is a method that returns the records of a vehicle, oldest first
+func (r *MaintenanceMap) FindRecords(ctx context.Context, vehicleID int) (rc []internal.MaintenanceRecord, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rc = make([]internal.MaintenanceRecord, 0)
+	for _, record := range r.records {
+		if record.VehicleID == vehicleID {
+			rc = append(rc, record)
+		}
+	}
+	sort.Slice(rc, func(i, j int) bool {
+		if !rc[i].Date.Equal(rc[j].Date) {
+			return rc[i].Date.Before(rc[j].Date)
+		}
+		return rc[i].Id < rc[j].Id
+	})
+	return
+}
+
+// CreateRecord is a method that adds a record with the next id
+func (r *MaintenanceMap) CreateRecord(ctx context.Context, record internal.MaintenanceRecord) (rc internal.MaintenanceRecord, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastRecordID++
+	record.Id = r.lastRecordID
+	r.records[record.Id] = record
+	return record, nil
+}
+
+// DeleteRecord is a method that removes a record
+func (r *MaintenanceMap) DeleteRecord(ctx context.Context, id int) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.records[id]; !ok {
+		return internal.ErrMaintenanceRecordNotFound
+	}
+	delete(r.records, id)
+	return
+}
+
+// FindSchedules is a method that returns the schedules of a vehicle, or of every vehicle when vehicleID is 0, sorted by id
+func (r *MaintenanceMap) FindSchedules(ctx context.Context, vehicleID int) (s []internal.MaintenanceSchedule, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	s = make([]internal.MaintenanceSchedule, 0)
+	for _, schedule := range r.schedules {
+		if vehicleID == 0 || schedule.VehicleID == vehicleID {
+			s = append(s, schedule)
+		}
+	}
+	sort.Slice(s, func(i, j int) bool { return s[i].Id < s[j].Id })
+	return
+}
+
+// CreateSchedule is a method that adds a schedule with the next id
+func (r *MaintenanceMap) CreateSchedule(ctx context.Context, schedule internal.MaintenanceSchedule) (s internal.MaintenanceSchedule, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastScheduleID++
+	schedule.Id = r.lastScheduleID
+	r.schedules[schedule.Id] = schedule
+	return schedule, nil
+}
+
+// DeleteSchedule is a method that removes a schedule
+func (r *MaintenanceMap) DeleteSchedule(ctx context.Context, id int) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.schedules[id]; !ok {
+		return internal.ErrMaintenanceScheduleNotFound
+	}
+	delete(r.schedules, id)
+	return
+}
+
+// DeleteByVehicles is a method that removes the records and the schedules of the vehicles
+func (r *MaintenanceMap) DeleteByVehicles(ctx context.Context, vehicleIDs []int) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	vehicles := make(map[int]struct{}, len(vehicleIDs))
+	for _, vehicleID := range vehicleIDs {
+		vehicles[vehicleID] = struct{}{}
+	}
+	for id, record := range r.records {
+		if _, ok := vehicles[record.VehicleID]; ok {
+			delete(r.records, id)
+		}
+	}
+	for id, schedule := range r.schedules {
+		if _, ok := vehicles[schedule.VehicleID]; ok {
+			delete(r.schedules, id)
+		}
+	}
+	return
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// NewOdometerMap is a function that returns a new instance of OdometerMap
+func NewOdometerMap() *OdometerMap {
+	return &OdometerMap{db: make(map[int][]internal.OdometerReading)}
+}
+
+// OdometerMap is a struct that represents an in-memory repository of odometer readings
+type OdometerMap struct {
+	// mu protects db and lastID
+	mu sync.RWMutex
+	// db is a map of the readings by vehicle id, oldest first
+	db map[int][]internal.OdometerReading
+	// lastID is the last id given to a reading
+	lastID int
+}
+
+// FindReadings is a method that returns the readings of a vehicle, oldest first
+func (r *OdometerMap) FindReadings(ctx context.Context, vehicleID int) (rd []internal.OdometerReading, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rd = make([]internal.OdometerReading, len(r.db[vehicleID]))
+	copy(rd, r.db[vehicleID])
+	return
+}
+
+// Create is a method that adds a reading with the next id, in date order among the readings of the vehicle
+func (r *OdometerMap) Create(ctx context.Context, reading internal.OdometerReading) (rd internal.OdometerReading, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	readings := r.db[reading.VehicleID]
+	// readings of the same moment are kept in arrival order
+	i := sort.Search(len(readings), func(i int) bool { return readings[i].Date.After(reading.Date) })
+
+	// a reading can be backdated, so it is checked against both neighbours
+	if prev := i - 1; prev >= 0 && !reading.Replacement && reading.Value < readings[prev].Value {
+		return internal.OdometerReading{}, fmt.Errorf("%w: the previous reading is %g km on %s", internal.ErrOdometerDecreasing, readings[prev].Value, readings[prev].Date.Format(time.RFC3339))
+	}
+	if i < len(readings) && !readings[i].Replacement && readings[i].Value < reading.Value {
+		return internal.OdometerReading{}, fmt.Errorf("%w: the next reading is %g km on %s", internal.ErrOdometerDecreasing, readings[i].Value, readings[i].Date.Format(time.RFC3339))
+	}
+
+	r.lastID++
+	reading.Id = r.lastID
+	readings = append(readings, internal.OdometerReading{})
+	copy(readings[i+1:], readings[i:])
+	readings[i] = reading
+	r.db[reading.VehicleID] = readings
+	return reading, nil
+}
+
+// DeleteByVehicles is a method that removes the readings of the vehicles
+func (r *OdometerMap) DeleteByVehicles(ctx context.Context, vehicleIDs []int) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, vehicleID := range vehicleIDs {
+		delete(r.db, vehicleID)
+	}
+	return
+}
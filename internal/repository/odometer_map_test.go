@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestOdometerMap_Create(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 1, 1+d, 0, 0, 0, 0, time.UTC) }
+
+	cases := []struct {
+		name    string
+		reading internal.OdometerReading
+		wantErr error
+	}{
+		{"after the last", internal.OdometerReading{Date: day(20), Value: 3000}, nil},
+		{"equal to the last", internal.OdometerReading{Date: day(20), Value: 2000}, nil},
+		{"lower than the last", internal.OdometerReading{Date: day(20), Value: 1500}, internal.ErrOdometerDecreasing},
+		{"replacement lower than the last", internal.OdometerReading{Date: day(20), Value: 10, Replacement: true}, nil},
+		{"backdated between", internal.OdometerReading{Date: day(5), Value: 1500}, nil},
+		{"backdated lower than the previous", internal.OdometerReading{Date: day(5), Value: 500}, internal.ErrOdometerDecreasing},
+		{"backdated higher than the next", internal.OdometerReading{Date: day(5), Value: 2500}, internal.ErrOdometerDecreasing},
+		{"before the first", internal.OdometerReading{Date: day(-1), Value: 900}, nil},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx := context.Background()
+			rp := NewOdometerMap()
+			for _, rd := range []internal.OdometerReading{{Date: day(0), Value: 1000}, {Date: day(10), Value: 2000}} {
+				rd.VehicleID = 1
+				if _, err := rp.Create(ctx, rd); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			c.reading.VehicleID = 1
+			rd, err := rp.Create(ctx, c.reading)
+			if !errors.Is(err, c.wantErr) {
+				t.Fatalf("Create() error = %v, want %v", err, c.wantErr)
+			}
+			readings, _ := rp.FindReadings(ctx, 1)
+			if c.wantErr != nil {
+				if len(readings) != 2 {
+					t.Fatalf("a rejected reading was stored: %+v", readings)
+				}
+				return
+			}
+			if rd.Id != 3 || len(readings) != 3 {
+				t.Fatalf("Create() = %+v, readings %+v", rd, readings)
+			}
+			for i := 1; i < len(readings); i++ {
+				if readings[i].Date.Before(readings[i-1].Date) {
+					t.Fatalf("readings out of date order: %+v", readings)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package repository
+
+import "app/internal"
+
+// NewOwnerMap is a function that returns a new instance of OwnerMap
+func NewOwnerMap(owners map[int]internal.Owner) *OwnerMap {
+	return &OwnerMap{newPersonMap(owners, func(o *internal.Owner) *int { return &o.Id }, internal.ErrOwnerNotFound)}
+}
+
+// OwnerMap is a struct that represents an in-memory repository of owners
+type OwnerMap struct {
+	*personMap[internal.Owner]
+}
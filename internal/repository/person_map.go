@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// newPersonMap is a function that returns a new instance of personMap
+func newPersonMap[T any](people map[int]T, id func(p *T) *int, errNotFound error) *personMap[T] {
+	// default config / values
+	if people == nil {
+		people = make(map[int]T)
+	}
+
+	r := &personMap[T]{db: people, id: id, errNotFound: errNotFound}
+	for id := range people {
+		r.lastID = max(r.lastID, id)
+	}
+	return r
+}
+
+// personMap is a struct that represents an in-memory repository of people, shared by owners and drivers
+type personMap[T any] struct {
+	// mu protects db and lastID
+	mu sync.RWMutex
+	// db is a map of people by id
+	db map[int]T
+	// lastID is the last id given to a person
+	lastID int
+	// id returns the id field of a person
+	id func(p *T) *int
+	// errNotFound is the error returned when a person does not exist
+	errNotFound error
+}
+
+// FindAll is a method that returns the people sorted by id
+func (r *personMap[T]) FindAll(ctx context.Context) (p []T, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	p = make([]T, 0, len(r.db))
+	for _, person := range r.db {
+		p = append(p, person)
+	}
+	sort.Slice(p, func(i, j int) bool { return *r.id(&p[i]) < *r.id(&p[j]) })
+	return
+}
+
+// FindOne is a method that returns a person by its id
+func (r *personMap[T]) FindOne(ctx context.Context, id int) (p T, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	p, ok := r.db[id]
+	if !ok {
+		err = r.errNotFound
+	}
+	return
+}
+
+// Create is a method that adds a person with the next id
+func (r *personMap[T]) Create(ctx context.Context, person T) (p T, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastID++
+	*r.id(&person) = r.lastID
+	r.db[r.lastID] = person
+	return person, nil
+}
+
+// Update is a method that replaces the data of a person
+func (r *personMap[T]) Update(ctx context.Context, person T) (p T, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := *r.id(&person)
+	if _, ok := r.db[id]; !ok {
+		return p, r.errNotFound
+	}
+	r.db[id] = person
+	return person, nil
+}
+
+// Delete is a method that removes a person
+func (r *personMap[T]) Delete(ctx context.Context, id int) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.db[id]; !ok {
+		return r.errNotFound
+	}
+	delete(r.db, id)
+	return
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestOwnerMap(t *testing.T) {
+	ctx := context.Background()
+	rp := NewOwnerMap(map[int]internal.Owner{3: {Id: 3, Name: "Ann"}})
+
+	// the ids continue after the loaded owners
+	o, err := rp.Create(ctx, internal.Owner{Id: 1, Name: "Bob"})
+	if err != nil || o.Id != 4 {
+		t.Fatalf("Create() = %+v, %v", o, err)
+	}
+	if _, err := rp.Update(ctx, internal.Owner{Id: 4, Name: "Bobby"}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	all, _ := rp.FindAll(ctx)
+	if len(all) != 2 || all[0].Id != 3 || all[1].Name != "Bobby" {
+		t.Fatalf("FindAll() = %+v", all)
+	}
+
+	if err := rp.Delete(ctx, 3); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := rp.FindOne(ctx, 3); !errors.Is(err, internal.ErrOwnerNotFound) {
+		t.Fatalf("FindOne() error = %v", err)
+	}
+	if _, err := rp.Update(ctx, internal.Owner{Id: 3}); !errors.Is(err, internal.ErrOwnerNotFound) {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if err := rp.Delete(ctx, 3); !errors.Is(err, internal.ErrOwnerNotFound) {
+		t.Fatalf("Delete() error = %v", err)
+	}
+}
+
+func TestDriverMap(t *testing.T) {
+	ctx := context.Background()
+	rp := NewDriverMap(nil)
+
+	d, err := rp.Create(ctx, internal.Driver{Name: "Bob", LicenseNumber: "B-1"})
+	if err != nil || d.Id != 1 {
+		t.Fatalf("Create() = %+v, %v", d, err)
+	}
+	if d, err := rp.FindOne(ctx, 1); err != nil || d.LicenseNumber != "B-1" {
+		t.Fatalf("FindOne() = %+v, %v", d, err)
+	}
+	if _, err := rp.FindOne(ctx, 2); !errors.Is(err, internal.ErrDriverNotFound) {
+		t.Fatalf("FindOne() error = %v", err)
+	}
+}
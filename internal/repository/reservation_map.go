@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// NewReservationMap is a function that returns a new instance of ReservationMap
+func NewReservationMap() *ReservationMap {
+	return &ReservationMap{
+		db:        make(map[int]internal.Reservation),
+		byVehicle: make(map[int][]int),
+	}
+}
+
+// ReservationMap is a struct that represents an in-memory repository of reservations
+type ReservationMap struct {
+	// mu protects db, byVehicle and lastID
+	mu sync.RWMutex
+	// db is a map of reservations by id
+	db map[int]internal.Reservation
+	// byVehicle is a map of the ids of the reservations by vehicle id
+	byVehicle map[int][]int
+	// lastID is the last id given to a reservation
+	lastID int
+}
+
+// FindByVehicle is a method that returns the reservations of a vehicle, earliest first
+func (r *ReservationMap) FindByVehicle(ctx context.Context, vehicleID int) (rs []internal.Reservation, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rs = make([]internal.Reservation, 0, len(r.byVehicle[vehicleID]))
+	for _, id := range r.byVehicle[vehicleID] {
+		rs = append(rs, r.db[id])
+	}
+	sort.Slice(rs, func(i, j int) bool {
+		if !rs[i].From.Equal(rs[j].From) {
+			return rs[i].From.Before(rs[j].From)
+		}
+		return rs[i].Id < rs[j].Id
+	})
+	return
+}
+
+// FindOverlapping is a method that returns the reservations in effect at some moment of a time range
+func (r *ReservationMap) FindOverlapping(ctx context.Context, from, to time.Time) (rs []internal.Reservation, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rs = make([]internal.Reservation, 0)
+	for _, reservation := range r.db {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if reservation.Overlaps(from, to) {
+			rs = append(rs, reservation)
+		}
+	}
+	return
+}
+
+// Create is a method that adds a reservation with the next id, unless it overlaps another one of the vehicle
+func (r *ReservationMap) Create(ctx context.Context, reservation internal.Reservation) (rs internal.Reservation, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, id := range r.byVehicle[reservation.VehicleID] {
+		if other := r.db[id]; other.Overlaps(reservation.From, reservation.To) {
+			return internal.Reservation{}, fmt.Errorf("%w: reservation %d from %s to %s", internal.ErrReservationConflict,
+				other.Id, other.From.Format(time.RFC3339), other.To.Format(time.RFC3339))
+		}
+	}
+
+	r.lastID++
+	reservation.Id = r.lastID
+	r.db[reservation.Id] = reservation
+	r.byVehicle[reservation.VehicleID] = append(r.byVehicle[reservation.VehicleID], reservation.Id)
+	return reservation, nil
+}
+
+// Cancel is a method that sets the cancellation of a reservation
+func (r *ReservationMap) Cancel(ctx context.Context, id int, at time.Time) (rs internal.Reservation, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rs, ok := r.db[id]
+	if !ok {
+		return internal.Reservation{}, internal.ErrReservationNotFound
+	}
+	if rs.CancelledAt != nil {
+		return internal.Reservation{}, fmt.Errorf("%w: it is already cancelled", internal.ErrInvalidReservation)
+	}
+	rs.CancelledAt = &at
+	r.db[id] = rs
+	return
+}
+
+// DeleteByVehicles is a method that removes the reservations of the vehicles
+func (r *ReservationMap) DeleteByVehicles(ctx context.Context, vehicleIDs []int) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, vehicleID := range vehicleIDs {
+		for _, id := range r.byVehicle[vehicleID] {
+			delete(r.db, id)
+		}
+		delete(r.byVehicle, vehicleID)
+	}
+	return
+}
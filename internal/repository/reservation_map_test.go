@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestReservationMap_Create(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	at := func(hours int) time.Time { return t0.Add(time.Duration(hours) * time.Hour) }
+
+	cases := []struct {
+		name      string
+		vehicleID int
+		from, to  int
+		wantErr   error
+	}{
+		{"free", 1, 4, 6, nil},
+		{"inside", 1, 1, 2, internal.ErrReservationConflict},
+		{"around", 1, -1, 4, internal.ErrReservationConflict},
+		{"overlapping the start", 1, -1, 1, internal.ErrReservationConflict},
+		{"overlapping the end", 1, 2, 4, internal.ErrReservationConflict},
+		{"same range", 1, 0, 3, internal.ErrReservationConflict},
+		// the end is excluded, so back to back reservations do not overlap
+		{"right after", 1, 3, 5, nil},
+		{"right before", 1, -2, 0, nil},
+		{"another vehicle", 2, 0, 3, nil},
+		{"over a cancelled one", 1, 10, 12, nil},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx := context.Background()
+			rp := NewReservationMap()
+			if _, err := rp.Create(ctx, internal.Reservation{VehicleID: 1, From: at(0), To: at(3)}); err != nil {
+				t.Fatal(err)
+			}
+			cancelled, err := rp.Create(ctx, internal.Reservation{VehicleID: 1, From: at(10), To: at(12)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := rp.Cancel(ctx, cancelled.Id, t0); err != nil {
+				t.Fatal(err)
+			}
+
+			r, err := rp.Create(ctx, internal.Reservation{VehicleID: c.vehicleID, From: at(c.from), To: at(c.to)})
+			if !errors.Is(err, c.wantErr) {
+				t.Fatalf("Create() error = %v, want %v", err, c.wantErr)
+			}
+			if err == nil && r.Id != 3 {
+				t.Fatalf("Create() id = %d, want 3", r.Id)
+			}
+			if rs, _ := rp.FindByVehicle(ctx, 1); err != nil && len(rs) != 2 {
+				t.Fatalf("FindByVehicle() = %d reservations after a conflict, want 2", len(rs))
+			}
+		})
+	}
+}
+
+func TestReservationMap_Create_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	rp := NewReservationMap()
+	from := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	// only one of the requests for the same range may get the vehicle
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	created := 0
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := rp.Create(ctx, internal.Reservation{VehicleID: 1, From: from.Add(time.Duration(i) * time.Minute), To: from.Add(time.Hour)})
+			if err == nil {
+				mu.Lock()
+				created++
+				mu.Unlock()
+			} else if !errors.Is(err, internal.ErrReservationConflict) {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if created != 1 {
+		t.Fatalf("created = %d reservations, want 1", created)
+	}
+}
+
+func TestReservationMap_Cancel(t *testing.T) {
+	ctx := context.Background()
+	rp := NewReservationMap()
+	r, _ := rp.Create(ctx, internal.Reservation{VehicleID: 1, From: time.Unix(0, 0), To: time.Unix(3600, 0)})
+
+	cases := []struct {
+		name    string
+		id      int
+		wantErr error
+	}{
+		{"in effect", r.Id, nil},
+		{"already cancelled", r.Id, internal.ErrInvalidReservation},
+		{"unknown", 2, internal.ErrReservationNotFound},
+	}
+	for _, c := range cases {
+		if _, err := rp.Cancel(ctx, c.id, time.Unix(60, 0)); !errors.Is(err, c.wantErr) {
+			t.Fatalf("%s: Cancel() error = %v, want %v", c.name, err, c.wantErr)
+		}
+	}
+}
@@ -1,6 +1,11 @@
 package repository
 
-import "app/internal"
+import (
+	"app/internal"
+	"context"
+	"sync"
+	"time"
+)
 
 // NewVehicleMap is a function that returns a new instance of VehicleMap
 func NewVehicleMap(db map[int]internal.Vehicle) *VehicleMap {
@@ -9,28 +14,71 @@ func NewVehicleMap(db map[int]internal.Vehicle) *VehicleMap {
 	if db != nil {
 		defaultDb = db
 	}
-	return &VehicleMap{db: defaultDb}
+
+	r := &VehicleMap{
+		db:         defaultDb,
+		history:    make(map[int][]internal.VehicleRevision),
+		aggregates: make(map[aggregateKey]internal.Aggregate),
+		values:     make(map[aggregateKey]*valueCounts),
+		indexes:    newVehicleIndexes(defaultDb),
+		now:        time.Now,
+	}
+	// the initial data is the first revision of every vehicle
+	for _, vehicle := range defaultDb {
+		r.commit(vehicle, false)
+		r.aggregate(vehicle)
+	}
+	return r
 }
 
 // VehicleMap is a struct that represents a vehicle repository
 type VehicleMap struct {
+	// mu protects db, history, aggregates, values and indexes
+	mu sync.RWMutex
 	// db is a map of vehicles
 	db map[int]internal.Vehicle
+	// history is a map of the revisions of every vehicle, oldest first
+	history map[int][]internal.VehicleRevision
+	// aggregates are the running aggregates of the vehicles not in the trash
+	aggregates map[aggregateKey]internal.Aggregate
+	// values are the distinct values behind every aggregate, to keep its minimum and maximum on removal
+	values map[aggregateKey]*valueCounts
+	// indexes are the secondary indexes of db
+	indexes *vehicleIndexes
+	// now returns the current time
+	now func() time.Time
 }
 
 // FindAll is a method that returns a map of all vehicles
-func (r *VehicleMap) FindAll() (v map[int]internal.Vehicle, err error) {
+func (r *VehicleMap) FindAll(ctx context.Context) (v map[int]internal.Vehicle, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	v = make(map[int]internal.Vehicle)
 
 	// copy db
 	for key, value := range r.db {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if !r.visible(ctx, value) {
+			continue
+		}
 		v[key] = value
 	}
 
 	return
 }
 
-func (r *VehicleMap) FindByID(vehicleId int) (exist bool) {
+func (r *VehicleMap) FindByID(ctx context.Context, vehicleId int) (exist bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.exists(vehicleId)
+}
+
+// exists is a method that checks if a vehicle exists, the caller must hold the lock
+func (r *VehicleMap) exists(vehicleId int) bool {
 	_, ok := r.db[vehicleId]
 	if !ok {
 		return false
@@ -39,22 +87,50 @@ func (r *VehicleMap) FindByID(vehicleId int) (exist bool) {
 
 }
 
-func (r *VehicleMap) CreateVehicle(newVehicle internal.Vehicle) error {
-	carExists := r.FindByID(newVehicle.Id)
+// FindOne is a method that returns a vehicle by its identifier
+func (r *VehicleMap) FindOne(ctx context.Context, vehicleID int) (v internal.Vehicle, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	v, ok := r.db[vehicleID]
+	if !ok || !r.visible(ctx, v) {
+		return internal.Vehicle{}, internal.ErrVehicleNotFounded
+	}
+	return
+}
+
+func (r *VehicleMap) CreateVehicle(ctx context.Context, newVehicle internal.Vehicle) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	carExists := r.exists(newVehicle.Id)
 	if carExists {
 		return internal.ErrCarAlreadyExists
 	}
+	if err := r.unique(newVehicle, nil); err != nil {
+		return err
+	}
 
-	r.db[newVehicle.Id] = newVehicle
+	r.put(newVehicle)
+	r.commit(newVehicle, false)
 
 	return nil
 }
 
-func (r *VehicleMap) FindByColorAndYear(color string, year int) (v map[int]internal.Vehicle, err error) {
+func (r *VehicleMap) FindByColorAndYear(ctx context.Context, color string, year int) (v map[int]internal.Vehicle, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	vehicles := make(map[int]internal.Vehicle)
 
-	for _, vehicle := range r.db {
-		if vehicle.Color == color || vehicle.FabricationYear == year {
+	for _, vehicle := range r.lookup(unionIDs(r.indexes.hash["color"].ids(internal.NormalizeKey(color)), r.indexes.sorted["year"].between(float64(year), float64(year)))) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if !r.visible(ctx, vehicle) {
+			continue
+		}
+		if internal.NormalizeKey(vehicle.Color) == internal.NormalizeKey(color) || vehicle.FabricationYear == year {
 			vehicles[vehicle.Id] = vehicle
 		}
 	}
@@ -66,11 +142,20 @@ func (r *VehicleMap) FindByColorAndYear(color string, year int) (v map[int]inter
 	return vehicles, nil
 }
 
-func (r *VehicleMap) FindBetweenBrandAndYearRate(brand string, initialYear int, finalYear int) (v map[int]internal.Vehicle, err error) {
+func (r *VehicleMap) FindBetweenBrandAndYearRate(ctx context.Context, brand string, initialYear int, finalYear int) (v map[int]internal.Vehicle, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	vehicles := make(map[int]internal.Vehicle)
 
-	for _, vehicle := range r.db {
-		if vehicle.Brand == brand && vehicle.FabricationYear >= initialYear && vehicle.FabricationYear <= finalYear {
+	for _, vehicle := range r.lookup(r.indexes.hash["brand"].ids(internal.NormalizeKey(brand))) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if !r.visible(ctx, vehicle) {
+			continue
+		}
+		if internal.NormalizeKey(vehicle.Brand) == internal.NormalizeKey(brand) && vehicle.FabricationYear >= initialYear && vehicle.FabricationYear <= finalYear {
 			vehicles[vehicle.Id] = vehicle
 		}
 	}
@@ -82,18 +167,36 @@ func (r *VehicleMap) FindBetweenBrandAndYearRate(brand string, initialYear int,
 	return vehicles, nil
 }
 
-func (r *VehicleMap) FindVelocityAverageByBrand(brand string) (float64, error) {
+func (r *VehicleMap) FindVelocityAverageByBrand(ctx context.Context, brand string) (float64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// the aggregates only cover the vehicles not in the trash
+	if !internal.IncludeDeletedFromContext(ctx) {
+		a, ok := r.aggregates[aggregateKey{attribute: "brand", value: brand, field: "max_speed"}]
+		if !ok {
+			return 0, internal.ErrVehicleNotFounded
+		}
+		return a.Avg(), nil
+	}
+
 	var average float64
 	var totalCars float64
 
-	for _, vehicle := range r.db {
-		if vehicle.Brand == brand {
+	for _, vehicle := range r.lookup(r.indexes.hash["brand"].ids(internal.NormalizeKey(brand))) {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		if !r.visible(ctx, vehicle) {
+			continue
+		}
+		if internal.NormalizeKey(vehicle.Brand) == internal.NormalizeKey(brand) {
 			average += vehicle.MaxSpeed
 			totalCars++
 		}
 	}
 
-	if average == 0.0 {
+	if totalCars == 0 {
 		return 0, internal.ErrVehicleNotFounded
 	}
 
@@ -103,43 +206,72 @@ func (r *VehicleMap) FindVelocityAverageByBrand(brand string) (float64, error) {
 
 }
 
-func (r *VehicleMap) CreateVehicules(newVehicles []internal.Vehicle) error {
+func (r *VehicleMap) CreateVehicules(ctx context.Context, newVehicles []internal.Vehicle) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
-	// Validate if some id in new vehicules exist
+	// Validate if some id or identifier in new vehicules exist
+	claimed := make(map[string]int)
 	for _, vehicle := range newVehicles {
-		if exist := r.FindByID(vehicle.Id); exist {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if exist := r.exists(vehicle.Id); exist {
 			return internal.ErrCarAlreadyExists
 		}
+		if err := r.unique(vehicle, claimed); err != nil {
+			return err
+		}
+	}
+
+	// Last chance to abort before the "db" is modified
+	if err := ctx.Err(); err != nil {
+		return err
 	}
 
 	// Add new vehicules to "db"
+	r.putAll(newVehicles)
 	for _, vehicle := range newVehicles {
-		r.db[vehicle.Id] = vehicle
+		r.commit(vehicle, false)
 	}
 
 	return nil
 
 }
 
-func (r *VehicleMap) UpdateMaxSpeed(vehicleID int, newMaxSpeed float64) (internal.Vehicle, error) {
-	exist := r.FindByID(vehicleID)
-	if !exist {
-		return internal.Vehicle{}, internal.ErrVehicleNotFounded
+func (r *VehicleMap) UpdateMaxSpeed(ctx context.Context, vehicleID int, newMaxSpeed float64) (change internal.VehicleChange, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.active(vehicleID) {
+		return internal.VehicleChange{}, internal.ErrVehicleNotFounded
 	}
 
 	vehicle := r.db[vehicleID]
+	change.Before = vehicle
 	vehicle.MaxSpeed = newMaxSpeed
 
-	r.db[vehicleID] = vehicle
+	r.put(vehicle)
+	r.commit(vehicle, false)
 
-	return vehicle, nil
+	change.After = vehicle
+	return
 }
 
-func (r *VehicleMap) FindVehiclesByFuelType(fuelType string) (v map[int]internal.Vehicle, err error) {
+func (r *VehicleMap) FindVehiclesByFuelType(ctx context.Context, fuelType string) (v map[int]internal.Vehicle, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	vehicles := make(map[int]internal.Vehicle)
 
-	for _, vehicle := range r.db {
-		if vehicle.FuelType == fuelType {
+	for _, vehicle := range r.lookup(r.indexes.hash["fuel_type"].ids(internal.NormalizeKey(fuelType))) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if !r.visible(ctx, vehicle) {
+			continue
+		}
+		if internal.NormalizeKey(vehicle.FuelType) == internal.NormalizeKey(fuelType) {
 			vehicles[vehicle.Id] = vehicle
 		}
 	}
@@ -151,19 +283,40 @@ func (r *VehicleMap) FindVehiclesByFuelType(fuelType string) (v map[int]internal
 	return vehicles, nil
 }
 
-func (r *VehicleMap) Delete(vehicleID int) error {
-	exist := r.FindByID(vehicleID)
-	if !exist {
-		return internal.ErrVehicleNotFounded
+func (r *VehicleMap) Delete(ctx context.Context, vehicleID int) (change internal.VehicleChange, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.active(vehicleID) {
+		return internal.VehicleChange{}, internal.ErrVehicleNotFounded
 	}
-	delete(r.db, vehicleID)
-	return nil
+
+	// soft delete: the vehicle stays in the trash until restored or purged
+	vehicle := r.db[vehicleID]
+	change.Before = vehicle
+	deletedAt := r.now().UTC()
+	vehicle.DeletedAt = &deletedAt
+
+	r.put(vehicle)
+	r.commit(vehicle, true)
+
+	change.After = vehicle
+	return
 }
 
-func (r *VehicleMap) FindVehiculesByTransmissionType(transmissionType string) (v map[int]internal.Vehicle, err error) {
+func (r *VehicleMap) FindVehiculesByTransmissionType(ctx context.Context, transmissionType string) (v map[int]internal.Vehicle, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	vehicles := make(map[int]internal.Vehicle)
-	for _, vehicle := range r.db {
-		if vehicle.Transmission == transmissionType {
+	for _, vehicle := range r.lookup(r.indexes.hash["transmission"].ids(internal.NormalizeKey(transmissionType))) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if !r.visible(ctx, vehicle) {
+			continue
+		}
+		if internal.NormalizeKey(vehicle.Transmission) == internal.NormalizeKey(transmissionType) {
 			vehicles[vehicle.Id] = vehicle
 		}
 	}
@@ -175,26 +328,49 @@ func (r *VehicleMap) FindVehiculesByTransmissionType(transmissionType string) (v
 	return vehicles, nil
 }
 
-func (r *VehicleMap) UpdateFuelType(vehicleID int, newFuelType string) (internal.Vehicle, error) {
-	exist := r.FindByID(vehicleID)
-	if !exist {
-		return internal.Vehicle{}, internal.ErrVehicleNotFounded
+func (r *VehicleMap) UpdateFuelType(ctx context.Context, vehicleID int, newFuelType string) (change internal.VehicleChange, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.active(vehicleID) {
+		return internal.VehicleChange{}, internal.ErrVehicleNotFounded
 	}
 
 	vehicle := r.db[vehicleID]
+	change.Before = vehicle
 	vehicle.FuelType = newFuelType
 
-	r.db[vehicleID] = vehicle
+	r.put(vehicle)
+	r.commit(vehicle, false)
 
-	return vehicle, nil
+	change.After = vehicle
+	return
 }
 
-func (r *VehicleMap) AverageBrandCapacity(brand string) (float64, error) {
+func (r *VehicleMap) AverageBrandCapacity(ctx context.Context, brand string) (float64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// the aggregates only cover the vehicles not in the trash
+	if !internal.IncludeDeletedFromContext(ctx) {
+		a, ok := r.aggregates[aggregateKey{attribute: "brand", value: brand, field: "passengers"}]
+		if !ok {
+			return 0, internal.ErrVehicleNotFounded
+		}
+		return a.Avg(), nil
+	}
+
 	var average float64
 	var numberOfVehicles float64
 
-	for _, vehicle := range r.db {
-		if vehicle.Brand == brand {
+	for _, vehicle := range r.lookup(r.indexes.hash["brand"].ids(internal.NormalizeKey(brand))) {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		if !r.visible(ctx, vehicle) {
+			continue
+		}
+		if internal.NormalizeKey(vehicle.Brand) == internal.NormalizeKey(brand) {
 			average += float64(vehicle.Capacity)
 			numberOfVehicles++
 		}
@@ -207,7 +383,10 @@ func (r *VehicleMap) AverageBrandCapacity(brand string) (float64, error) {
 	return average, nil
 }
 
-func (r *VehicleMap) FindVehiclesByDimensions(minLength, maxLength, minWidth, maxWidth float64) (v map[int]internal.Vehicle, err error) {
+func (r *VehicleMap) FindVehiclesByDimensions(ctx context.Context, minLength, maxLength, minWidth, maxWidth float64) (v map[int]internal.Vehicle, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	vehicles := make(map[int]internal.Vehicle)
 
 	// * Should be
@@ -219,7 +398,13 @@ func (r *VehicleMap) FindVehiclesByDimensions(minLength, maxLength, minWidth, ma
 	//}
 
 	// * Which I think is what the problem refers to.
-	for _, vehicle := range r.db {
+	for _, vehicle := range r.lookup(r.indexes.sorted["height"].between(minLength, maxLength)) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if !r.visible(ctx, vehicle) {
+			continue
+		}
 		if vehicle.Height >= minLength && vehicle.Height <= maxLength &&
 			vehicle.Width >= minWidth && vehicle.Width <= maxWidth {
 			vehicles[vehicle.Id] = vehicle
@@ -233,10 +418,19 @@ func (r *VehicleMap) FindVehiclesByDimensions(minLength, maxLength, minWidth, ma
 	return vehicles, nil
 }
 
-func (r *VehicleMap) FindVehiclesByWeightRate(minWeight, maxWeight float64) (v map[int]internal.Vehicle, err error) {
+func (r *VehicleMap) FindVehiclesByWeightRate(ctx context.Context, minWeight, maxWeight float64) (v map[int]internal.Vehicle, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	vehicles := make(map[int]internal.Vehicle)
 
-	for _, vehicle := range r.db {
+	for _, vehicle := range r.lookup(r.indexes.sorted["weight"].between(minWeight, maxWeight)) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if !r.visible(ctx, vehicle) {
+			continue
+		}
 		if vehicle.Weight >= minWeight && vehicle.Weight <= maxWeight {
 			vehicles[vehicle.Id] = vehicle
 		}
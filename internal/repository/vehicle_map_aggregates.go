@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// aggregateKey identifies the aggregate of a numeric field over the vehicles sharing the value of a categorical attribute.
+// The aggregates over all the vehicles have an empty attribute
+type aggregateKey struct {
+	attribute string
+	value     string
+	field     string
+}
+
+// valueCounts is the multiset of the values of an aggregate, as the count of every distinct value
+// and the distinct values in ascending order
+type valueCounts struct {
+	counts map[float64]int
+	sorted []float64
+}
+
+// add is a method that adds an occurrence of a value
+func (vc *valueCounts) add(value float64) {
+	if vc.counts[value]++; vc.counts[value] > 1 {
+		return
+	}
+	i := sort.SearchFloat64s(vc.sorted, value)
+	vc.sorted = append(vc.sorted, 0)
+	copy(vc.sorted[i+1:], vc.sorted[i:])
+	vc.sorted[i] = value
+}
+
+// remove is a method that removes an occurrence of a value
+func (vc *valueCounts) remove(value float64) {
+	if vc.counts[value]--; vc.counts[value] > 0 {
+		return
+	}
+	delete(vc.counts, value)
+	if i := sort.SearchFloat64s(vc.sorted, value); i < len(vc.sorted) && vc.sorted[i] == value {
+		vc.sorted = append(vc.sorted[:i], vc.sorted[i+1:]...)
+	}
+}
+
+// put is a method that stores a vehicle keeping the aggregates and indexes up to date, the caller must hold the lock
+func (r *VehicleMap) put(vehicle internal.Vehicle) {
+	r.putAll([]internal.Vehicle{vehicle})
+}
+
+// putAll is a method that stores vehicles of distinct ids keeping the aggregates and indexes up to date,
+// the sorted indexes are updated once for the whole batch. The caller must hold the lock
+func (r *VehicleMap) putAll(vehicles []internal.Vehicle) {
+	olds := make([]internal.Vehicle, 0, len(vehicles))
+	for _, vehicle := range vehicles {
+		if old, ok := r.db[vehicle.Id]; ok {
+			r.unaggregate(old)
+			olds = append(olds, old)
+		}
+		r.db[vehicle.Id] = vehicle
+		r.aggregate(vehicle)
+	}
+	r.indexes.removeAll(olds)
+	r.indexes.addAll(vehicles)
+}
+
+// aggregateKeys returns the keys of the aggregates a vehicle contributes to
+func aggregateKeys(vehicle internal.Vehicle, field string) []aggregateKey {
+	keys := []aggregateKey{{field: field}}
+	for _, attribute := range internal.CategoricalFields {
+		value, _ := internal.CategoricalField(vehicle, attribute)
+		keys = append(keys, aggregateKey{attribute: attribute, value: value, field: field})
+	}
+	return keys
+}
+
+// aggregate is a method that adds a vehicle to the aggregates, vehicles in the trash are not aggregated
+func (r *VehicleMap) aggregate(vehicle internal.Vehicle) {
+	if vehicle.DeletedAt != nil {
+		return
+	}
+	for _, field := range internal.NumericFields {
+		value, _ := internal.NumericField(vehicle, field)
+		for _, key := range aggregateKeys(vehicle, field) {
+			a, ok := r.aggregates[key]
+			if !ok {
+				a = internal.Aggregate{Min: value, Max: value}
+				r.values[key] = &valueCounts{counts: make(map[float64]int)}
+			}
+			r.values[key].add(value)
+			a.Count++
+			a.Sum += value
+			a.Min = math.Min(a.Min, value)
+			a.Max = math.Max(a.Max, value)
+			r.aggregates[key] = a
+		}
+	}
+}
+
+// unaggregate is a method that removes a vehicle from the aggregates.
+// When it held the last occurrence of the minimum or the maximum, the next distinct value takes its place
+func (r *VehicleMap) unaggregate(vehicle internal.Vehicle) {
+	if vehicle.DeletedAt != nil {
+		return
+	}
+	for _, field := range internal.NumericFields {
+		value, _ := internal.NumericField(vehicle, field)
+		for _, key := range aggregateKeys(vehicle, field) {
+			a := r.aggregates[key]
+			a.Count--
+			a.Sum -= value
+			if a.Count == 0 {
+				delete(r.aggregates, key)
+				delete(r.values, key)
+				continue
+			}
+			values := r.values[key]
+			values.remove(value)
+			a.Min, a.Max = values.sorted[0], values.sorted[len(values.sorted)-1]
+			r.aggregates[key] = a
+		}
+	}
+}
+
+// Aggregates is a method that returns the aggregates of a numeric field grouped by a categorical attribute
+// (or a single group with an empty key when groupBy is empty), without scanning the vehicles
+func (r *VehicleMap) Aggregates(ctx context.Context, groupBy string, field string) (a map[string]internal.Aggregate, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	a = make(map[string]internal.Aggregate)
+	for key, value := range r.aggregates {
+		if key.attribute == groupBy && key.field == field {
+			a[key.value] = value
+		}
+	}
+	return
+}
+
+// CheckAggregates is a method that recomputes the aggregates from scratch and compares them
+// with the ones maintained incrementally
+func (r *VehicleMap) CheckAggregates() error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	expected := &VehicleMap{aggregates: make(map[aggregateKey]internal.Aggregate), values: make(map[aggregateKey]*valueCounts)}
+	for _, vehicle := range r.db {
+		expected.aggregate(vehicle)
+	}
+
+	if len(expected.aggregates) != len(r.aggregates) {
+		return fmt.Errorf("%w: %d aggregates, expected %d", internal.ErrAggregatesInconsistent, len(r.aggregates), len(expected.aggregates))
+	}
+	for key, want := range expected.aggregates {
+		got := r.aggregates[key]
+		if got.Count != want.Count || got.Min != want.Min || got.Max != want.Max || math.Abs(got.Sum-want.Sum) > 1e-6*math.Max(1, math.Abs(want.Sum)) {
+			return fmt.Errorf("%w: %+v is %+v, expected %+v", internal.ErrAggregatesInconsistent, key, got, want)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"testing"
+	"time"
+)
+
+// testVehicle returns a vehicle with the given id, brand and maximum speed
+func testVehicle(id int, brand string, maxSpeed float64) internal.Vehicle {
+	return internal.Vehicle{Id: id, VehicleAttributes: internal.VehicleAttributes{
+		Brand:           brand,
+		Model:           "Model",
+		Color:           "red",
+		FabricationYear: 2000 + id%20,
+		Capacity:        2 + id%5,
+		MaxSpeed:        maxSpeed,
+		FuelType:        "gas",
+		Transmission:    "manual",
+		Weight:          1000 + float64(id),
+		Dimensions:      internal.Dimensions{Height: 1.5, Length: 4, Width: 2},
+	}}
+}
+
+func TestVehicleMap_CheckAggregates(t *testing.T) {
+	ctx := context.Background()
+	rp := NewVehicleMap(map[int]internal.Vehicle{
+		1: testVehicle(1, "Ford", 200),
+		2: testVehicle(2, "Ford", 150),
+	})
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	rp.now = func() time.Time { return now }
+
+	steps := []struct {
+		name string
+		run  func() error
+	}{
+		{"load", func() error { return nil }},
+		{"create", func() error { return rp.CreateVehicle(ctx, testVehicle(3, "Ford", 250)) }},
+		{"create another brand", func() error { return rp.CreateVehicle(ctx, testVehicle(4, "Fiat", 120)) }},
+		{"create in bulk", func() error {
+			return rp.CreateVehicules(ctx, []internal.Vehicle{testVehicle(5, "Fiat", 120), testVehicle(6, "Ford", 250)})
+		}},
+		{"update the maximum", func() error { _, err := rp.UpdateMaxSpeed(ctx, 3, 100); return err }},
+		{"update a repeated maximum", func() error { _, err := rp.UpdateMaxSpeed(ctx, 6, 90); return err }},
+		{"update the fuel type", func() error { _, err := rp.UpdateFuelType(ctx, 1, "diesel"); return err }},
+		{"delete the minimum", func() error { _, err := rp.Delete(ctx, 6); return err }},
+		{"delete a repeated value", func() error { _, err := rp.Delete(ctx, 4); return err }},
+		{"delete the last of a brand", func() error { _, err := rp.Delete(ctx, 5); return err }},
+		{"restore", func() error { _, err := rp.Restore(ctx, 4); return err }},
+		{"purge", func() error { _, err := rp.Purge(ctx, now.Add(time.Second)); return err }},
+	}
+	for _, step := range steps {
+		if err := step.run(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if err := rp.CheckAggregates(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+	}
+
+	a, _ := rp.Aggregates(ctx, "brand", "max_speed")
+	want := map[string]internal.Aggregate{
+		"Ford": {Count: 3, Sum: 450, Min: 100, Max: 200},
+		"Fiat": {Count: 1, Sum: 120, Min: 120, Max: 120},
+	}
+	if len(a) != len(want) {
+		t.Fatalf("Aggregates() = %+v, want %+v", a, want)
+	}
+	for key, w := range want {
+		if a[key] != w {
+			t.Fatalf("Aggregates()[%q] = %+v, want %+v", key, a[key], w)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"sort"
+)
+
+// UpdateWhere is a method that applies the patch to every vehicle matching the filter at once.
+// With dryRun the changes are computed but not applied
+func (r *VehicleMap) UpdateWhere(ctx context.Context, filter internal.VehicleFilter, patch internal.VehiclePatch, dryRun bool) (changes []internal.VehicleChange, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	matches, err := r.match(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// an identifier can not be given to several vehicles
+	var identifiers []string
+	if patch.Registration != nil {
+		identifiers = append(identifiers, "registration")
+	}
+	if patch.VIN != nil {
+		identifiers = append(identifiers, "vin")
+	}
+
+	claimed := make(map[string]int)
+	changes = make([]internal.VehicleChange, len(matches))
+	for i, vehicle := range matches {
+		changes[i] = internal.VehicleChange{Before: vehicle, After: patch.Apply(vehicle)}
+		if len(identifiers) == 0 {
+			continue
+		}
+		if err := r.unique(changes[i].After, claimed, identifiers...); err != nil {
+			return nil, err
+		}
+	}
+	if dryRun {
+		return
+	}
+
+	// Last chance to abort before the "db" is modified
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	after := make([]internal.Vehicle, len(changes))
+	for i, change := range changes {
+		after[i] = change.After
+	}
+	r.putAll(after)
+	for _, vehicle := range after {
+		r.commit(vehicle, false)
+	}
+	return
+}
+
+// DeleteWhere is a method that soft deletes every vehicle matching the filter at once.
+// With dryRun the vehicles are returned but not deleted
+func (r *VehicleMap) DeleteWhere(ctx context.Context, filter internal.VehicleFilter, dryRun bool) (deleted []internal.Vehicle, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deleted, err = r.match(ctx, filter)
+	if err != nil || dryRun {
+		return
+	}
+
+	// Last chance to abort before the "db" is modified
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	deletedAt := r.now().UTC()
+	for i := range deleted {
+		deleted[i].DeletedAt = &deletedAt
+	}
+	r.putAll(deleted)
+	for _, vehicle := range deleted {
+		r.commit(vehicle, true)
+	}
+	return
+}
+
+// match is a method that returns the vehicles not in the trash matching the filter, sorted by id.
+// The caller must hold the lock
+func (r *VehicleMap) match(ctx context.Context, filter internal.VehicleFilter) (v []internal.Vehicle, err error) {
+	v = make([]internal.Vehicle, 0)
+	for _, vehicle := range r.scan(filter) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if vehicle.DeletedAt == nil && filter.Match(vehicle) {
+			v = append(v, vehicle)
+		}
+	}
+	sort.Slice(v, func(i, j int) bool { return v[i].Id < v[j].Id })
+	return
+}
+
+// FindWhere is a method that returns the vehicles matching the filter, sorted by id
+func (r *VehicleMap) FindWhere(ctx context.Context, filter internal.VehicleFilter) (v []internal.Vehicle, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	v = make([]internal.Vehicle, 0)
+	for _, vehicle := range r.scan(filter) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if r.visible(ctx, vehicle) && filter.Match(vehicle) {
+			v = append(v, vehicle)
+		}
+	}
+	sort.Slice(v, func(i, j int) bool { return v[i].Id < v[j].Id })
+	return
+}
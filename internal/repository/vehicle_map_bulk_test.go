@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestVehicleMap_UpdateWhere(t *testing.T) {
+	blue, plate := "blue", "AB123CD"
+	cases := []struct {
+		name    string
+		filter  internal.VehicleFilter
+		patch   internal.VehiclePatch
+		dryRun  bool
+		wantIDs []int
+		wantErr error
+	}{
+		{"update", internal.VehicleFilter{Brand: "Ford"}, internal.VehiclePatch{Color: &blue}, false, []int{1, 2}, nil},
+		{"dry run", internal.VehicleFilter{Brand: "Ford"}, internal.VehiclePatch{Color: &blue}, true, []int{1, 2}, nil},
+		{"no match", internal.VehicleFilter{Brand: "Volvo"}, internal.VehiclePatch{Color: &blue}, false, []int{}, nil},
+		{"one registration for several vehicles", internal.VehicleFilter{Brand: "Ford"}, internal.VehiclePatch{Registration: &plate}, false, nil, internal.ErrRegistrationAlreadyExists},
+		{"one registration for several vehicles in a dry run", internal.VehicleFilter{Brand: "Ford"}, internal.VehiclePatch{Registration: &plate}, true, nil, internal.ErrRegistrationAlreadyExists},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx := context.Background()
+			rp := NewVehicleMap(map[int]internal.Vehicle{
+				1: testVehicle(1, "Ford", 200),
+				2: testVehicle(2, "Ford", 150),
+				3: testVehicle(3, "Fiat", 120),
+			})
+			before, _ := rp.FindAll(ctx)
+
+			changes, err := rp.UpdateWhere(ctx, c.filter, c.patch, c.dryRun)
+			if !errors.Is(err, c.wantErr) {
+				t.Fatalf("UpdateWhere() error = %v, want %v", err, c.wantErr)
+			}
+			ids := make([]int, 0)
+			for _, change := range changes {
+				ids = append(ids, change.After.Id)
+				if change.After.Color != blue || change.Before.Color != "red" {
+					t.Fatalf("change of %d = %s to %s", change.After.Id, change.Before.Color, change.After.Color)
+				}
+			}
+			if err == nil && !reflect.DeepEqual(ids, c.wantIDs) {
+				t.Fatalf("UpdateWhere() ids = %v, want %v", ids, c.wantIDs)
+			}
+
+			// a dry run or an error leave the vehicles and their history as they were
+			applied := err == nil && !c.dryRun && len(ids) > 0
+			after, _ := rp.FindAll(ctx)
+			if changed := !reflect.DeepEqual(before, after); changed != applied {
+				t.Fatalf("vehicles changed = %t, want %t", changed, applied)
+			}
+			wantRevisions := 1
+			if applied {
+				wantRevisions = 2
+			}
+			if h, _ := rp.FindHistory(ctx, 1); len(h) != wantRevisions {
+				t.Fatalf("history of 1 = %d revisions, want %d", len(h), wantRevisions)
+			}
+			if err := rp.CheckAggregates(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestVehicleMap_DeleteWhere(t *testing.T) {
+	cases := []struct {
+		name    string
+		filter  internal.VehicleFilter
+		dryRun  bool
+		wantIDs []int
+		wantLen int
+	}{
+		{"delete", internal.VehicleFilter{Brand: "Ford"}, false, []int{1, 2}, 1},
+		{"dry run", internal.VehicleFilter{Brand: "Ford"}, true, []int{1, 2}, 3},
+		{"no match", internal.VehicleFilter{Brand: "Volvo"}, false, []int{}, 3},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx := context.Background()
+			rp := NewVehicleMap(map[int]internal.Vehicle{
+				1: testVehicle(1, "Ford", 200),
+				2: testVehicle(2, "Ford", 150),
+				3: testVehicle(3, "Fiat", 120),
+			})
+
+			deleted, err := rp.DeleteWhere(ctx, c.filter, c.dryRun)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ids := make([]int, 0)
+			for _, vehicle := range deleted {
+				ids = append(ids, vehicle.Id)
+			}
+			if !reflect.DeepEqual(ids, c.wantIDs) {
+				t.Fatalf("DeleteWhere() ids = %v, want %v", ids, c.wantIDs)
+			}
+
+			all, _ := rp.FindAll(ctx)
+			trash, _ := rp.FindTrash(ctx)
+			if len(all) != c.wantLen || len(trash) != 3-c.wantLen {
+				t.Fatalf("FindAll() = %d vehicles and FindTrash() = %d, want %d and %d", len(all), len(trash), c.wantLen, 3-c.wantLen)
+			}
+			if err := rp.CheckAggregates(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestVehicleMap_CancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	withTrash := internal.ContextWithIncludeDeleted(ctx)
+	rp := NewVehicleMap(map[int]internal.Vehicle{1: testVehicle(1, "Ford", 100), 2: testVehicle(2, "Fiat", 180)})
+
+	cases := []struct {
+		name string
+		run  func() error
+	}{
+		{"find all", func() error { _, err := rp.FindAll(ctx); return err }},
+		{"find by color and year", func() error { _, err := rp.FindByColorAndYear(ctx, "red", 2001); return err }},
+		{"find by brand and years", func() error { _, err := rp.FindBetweenBrandAndYearRate(ctx, "Ford", 2000, 2020); return err }},
+		// the averages of the active vehicles come from the aggregates at once, only the ones scanning the trash stop
+		{"speed average", func() error { _, err := rp.FindVelocityAverageByBrand(withTrash, "Ford"); return err }},
+		{"capacity average", func() error { _, err := rp.AverageBrandCapacity(withTrash, "Ford"); return err }},
+		{"find by fuel type", func() error { _, err := rp.FindVehiclesByFuelType(ctx, "gas"); return err }},
+		{"find by dimensions", func() error { _, err := rp.FindVehiclesByDimensions(ctx, 0, 1000, 0, 1000); return err }},
+		{"find by weight", func() error { _, err := rp.FindVehiclesByWeightRate(ctx, 0, 10000); return err }},
+		{"create many", func() error {
+			return rp.CreateVehicules(ctx, []internal.Vehicle{testVehicle(3, "Kia", 150), testVehicle(4, "Kia", 160)})
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if err := c.run(); !errors.Is(err, context.Canceled) {
+				t.Fatalf("error = %v, want %v", err, context.Canceled)
+			}
+		})
+	}
+
+	// the cancelled writes left the vehicles untouched
+	v, err := rp.FindAll(context.Background())
+	if err != nil || len(v) != 2 {
+		t.Fatalf("FindAll() = %v, %v, want the 2 loaded vehicles", v, err)
+	}
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"time"
+)
+
+// commit is a method that stores a new revision of a vehicle, the caller must hold the lock
+func (r *VehicleMap) commit(vehicle internal.Vehicle, deleted bool) {
+	revisions := r.history[vehicle.Id]
+	r.history[vehicle.Id] = append(revisions, internal.VehicleRevision{
+		Version:   len(revisions) + 1,
+		Timestamp: r.now().UTC(),
+		Deleted:   deleted,
+		Vehicle:   vehicle,
+	})
+}
+
+// FindHistory is a method that returns all the revisions of a vehicle, oldest first
+func (r *VehicleMap) FindHistory(ctx context.Context, vehicleID int) (h []internal.VehicleRevision, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	revisions, ok := r.history[vehicleID]
+	if !ok {
+		return nil, internal.ErrVehicleNotFounded
+	}
+
+	h = make([]internal.VehicleRevision, len(revisions))
+	copy(h, revisions)
+	return
+}
+
+// FindAllAsOf is a method that returns the vehicles as they were at the given moment
+func (r *VehicleMap) FindAllAsOf(ctx context.Context, asOf time.Time) (v map[int]internal.Vehicle, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	v = make(map[int]internal.Vehicle)
+	for id, revisions := range r.history {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if revision, ok := revisionAsOf(revisions, asOf); ok {
+			v[id] = revision.Vehicle
+		}
+	}
+	return
+}
+
+// FindOneAsOf is a method that returns a vehicle as it was at the given moment
+func (r *VehicleMap) FindOneAsOf(ctx context.Context, vehicleID int, asOf time.Time) (v internal.Vehicle, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	revision, ok := revisionAsOf(r.history[vehicleID], asOf)
+	if !ok {
+		err = internal.ErrVehicleNotFounded
+		return
+	}
+	v = revision.Vehicle
+	return
+}
+
+// revisionAsOf returns the last revision stored up to asOf, unless it is a deletion
+func revisionAsOf(revisions []internal.VehicleRevision, asOf time.Time) (revision internal.VehicleRevision, ok bool) {
+	for _, rev := range revisions {
+		if rev.Timestamp.After(asOf) {
+			break
+		}
+		revision, ok = rev, true
+	}
+	if ok && revision.Deleted {
+		ok = false
+	}
+	return
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestVehicleMap_History(t *testing.T) {
+	ctx := context.Background()
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := t0
+	rp := NewVehicleMap(nil)
+	rp.now = func() time.Time { return now }
+	if err := rp.CreateVehicules(ctx, []internal.Vehicle{testVehicle(1, "Ford", 200), testVehicle(2, "Fiat", 150)}); err != nil {
+		t.Fatal(err)
+	}
+	now = t0.Add(time.Hour)
+	if _, err := rp.UpdateMaxSpeed(ctx, 1, 100); err != nil {
+		t.Fatal(err)
+	}
+	now = t0.Add(2 * time.Hour)
+	if _, err := rp.Delete(ctx, 2); err != nil {
+		t.Fatal(err)
+	}
+	now = t0.Add(3 * time.Hour)
+	if err := rp.CreateVehicle(ctx, testVehicle(3, "Fiat", 120)); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("history", func(t *testing.T) {
+		cases := []struct {
+			id       int
+			versions []int
+			deleted  []bool
+			wantErr  error
+		}{
+			{1, []int{1, 2}, []bool{false, false}, nil},
+			{2, []int{1, 2}, []bool{false, true}, nil},
+			{3, []int{1}, []bool{false}, nil},
+			{4, nil, nil, internal.ErrVehicleNotFounded},
+		}
+		for _, c := range cases {
+			h, err := rp.FindHistory(ctx, c.id)
+			if !errors.Is(err, c.wantErr) {
+				t.Fatalf("FindHistory(%d) error = %v, want %v", c.id, err, c.wantErr)
+			}
+			if len(h) != len(c.versions) {
+				t.Fatalf("FindHistory(%d) = %d revisions, want %d", c.id, len(h), len(c.versions))
+			}
+			for i, rev := range h {
+				if rev.Version != c.versions[i] || rev.Deleted != c.deleted[i] {
+					t.Fatalf("FindHistory(%d)[%d] = version %d deleted %t", c.id, i, rev.Version, rev.Deleted)
+				}
+			}
+		}
+	})
+
+	t.Run("as of", func(t *testing.T) {
+		cases := []struct {
+			name string
+			asOf time.Time
+			// speeds are the maximum speeds of the vehicles present by id
+			speeds map[int]float64
+		}{
+			{"before anything", t0.Add(-time.Second), map[int]float64{}},
+			{"at the creation", t0, map[int]float64{1: 200, 2: 150}},
+			{"between revisions", t0.Add(90 * time.Minute), map[int]float64{1: 100, 2: 150}},
+			{"after the deletion", t0.Add(2 * time.Hour), map[int]float64{1: 100}},
+			{"now", t0.Add(4 * time.Hour), map[int]float64{1: 100, 3: 120}},
+		}
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				all, err := rp.FindAllAsOf(ctx, c.asOf)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(all) != len(c.speeds) {
+					t.Fatalf("FindAllAsOf() = %d vehicles, want %d", len(all), len(c.speeds))
+				}
+				for id := 1; id <= 3; id++ {
+					speed, ok := c.speeds[id]
+					v, err := rp.FindOneAsOf(ctx, id, c.asOf)
+					if !ok {
+						if !errors.Is(err, internal.ErrVehicleNotFounded) {
+							t.Fatalf("FindOneAsOf(%d) error = %v, want not found", id, err)
+						}
+						continue
+					}
+					if err != nil || v.MaxSpeed != speed || all[id].MaxSpeed != speed {
+						t.Fatalf("vehicle %d: FindOneAsOf() = %v %v, FindAllAsOf() = %v, want speed %v", id, v.MaxSpeed, err, all[id].MaxSpeed, speed)
+					}
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"slices"
+	"sort"
+)
+
+// uniqueAttributes are the identifiers two vehicles out of the trash can not share, with the error returned when they do
+var uniqueAttributes = []struct {
+	attribute string
+	err       error
+}{
+	{"registration", internal.ErrRegistrationAlreadyExists},
+	{"vin", internal.ErrVINAlreadyExists},
+}
+
+// owners is a method that returns the ids of the vehicles not in the trash with the given key of an identifier,
+// sorted. The caller must hold the lock
+func (r *VehicleMap) owners(attribute, key string) (ids []int) {
+	if key == "" {
+		return nil
+	}
+	for _, id := range r.indexes.hash[attribute].ids(key) {
+		if r.active(id) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	return
+}
+
+// unique is a method that checks that no other vehicle uses the identifiers of a vehicle, neither in the repository
+// nor among the ones claimed by the same operation. Only the given attributes are checked, all of them when none is.
+// Vehicles in the trash release their identifiers and empty ones are never taken. The caller must hold the lock
+func (r *VehicleMap) unique(vehicle internal.Vehicle, claimed map[string]int, attributes ...string) error {
+	for _, u := range uniqueAttributes {
+		if len(attributes) > 0 && !slices.Contains(attributes, u.attribute) {
+			continue
+		}
+		key := hashedValue(vehicle, u.attribute)
+		if key == "" {
+			continue
+		}
+		if id, ok := claimed[u.attribute+":"+key]; ok && id != vehicle.Id {
+			return u.err
+		}
+		for _, id := range r.owners(u.attribute, key) {
+			if id != vehicle.Id {
+				return u.err
+			}
+		}
+		if claimed != nil {
+			claimed[u.attribute+":"+key] = vehicle.Id
+		}
+	}
+	return nil
+}
+
+// findUnique is a method that returns the vehicle with the given key of an identifier
+func (r *VehicleMap) findUnique(ctx context.Context, attribute, key string, errAmbiguous error) (v internal.Vehicle, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var found []internal.Vehicle
+	for _, vehicle := range r.lookup(r.indexes.hash[attribute].ids(key)) {
+		if key != "" && r.visible(ctx, vehicle) {
+			found = append(found, vehicle)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		err = internal.ErrVehicleNotFounded
+	case 1:
+		v = found[0]
+	default:
+		err = errAmbiguous
+	}
+	return
+}
+
+// FindByRegistration is a method that returns the vehicle with the given registration
+func (r *VehicleMap) FindByRegistration(ctx context.Context, plate string) (v internal.Vehicle, err error) {
+	return r.findUnique(ctx, "registration", internal.NormalizePlate(plate), internal.ErrRegistrationAmbiguous)
+}
+
+// FindByVIN is a method that returns the vehicle with the given VIN
+func (r *VehicleMap) FindByVIN(ctx context.Context, vin string) (v internal.Vehicle, err error) {
+	return r.findUnique(ctx, "vin", internal.NormalizeVIN(vin), internal.ErrVINAmbiguous)
+}
+
+// UpsertByRegistration is a method that replaces the vehicles whose registration is already in use,
+// keeping their id, and creates the others, all at once
+func (r *VehicleMap) UpsertByRegistration(ctx context.Context, vehicles []internal.Vehicle) (created []internal.Vehicle, updated []internal.VehicleChange, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	claimed := make(map[string]int)
+	ids := make(map[int]struct{})
+	// plates are the registrations of the batch, whatever vehicle they resolve to
+	plates := make(map[string]struct{})
+	for _, vehicle := range vehicles {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+
+		plate := internal.NormalizePlate(vehicle.Registration)
+		if _, ok := plates[plate]; ok && plate != "" {
+			return nil, nil, internal.ErrRegistrationAlreadyExists
+		}
+		plates[plate] = struct{}{}
+
+		owners := r.owners("registration", plate)
+		switch len(owners) {
+		case 0:
+			if _, ok := ids[vehicle.Id]; ok || r.exists(vehicle.Id) {
+				return nil, nil, internal.ErrCarAlreadyExists
+			}
+			ids[vehicle.Id] = struct{}{}
+			created = append(created, vehicle)
+		case 1:
+			before := r.db[owners[0]]
+			vehicle.Id = before.Id
+			vehicle.Status = before.Status
+			vehicle.DeletedAt = nil
+			updated = append(updated, internal.VehicleChange{Before: before, After: vehicle})
+		default:
+			return nil, nil, internal.ErrRegistrationAmbiguous
+		}
+
+		// the other identifiers must be free, in the repository and in the batch
+		if err := r.unique(vehicle, claimed); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Last chance to abort before the "db" is modified
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	r.putAll(created)
+	for _, vehicle := range created {
+		r.commit(vehicle, false)
+	}
+	after := make([]internal.Vehicle, len(updated))
+	for i, change := range updated {
+		after[i] = change.After
+	}
+	r.putAll(after)
+	for _, vehicle := range after {
+		r.commit(vehicle, false)
+	}
+	return
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestVehicleMap_UpsertByRegistration(t *testing.T) {
+	// row returns a vehicle of the batch with the given id, registration and color
+	row := func(id int, plate, color string) internal.Vehicle {
+		vehicle := testVehicle(id, "Ford", 200)
+		vehicle.Registration, vehicle.Color = plate, color
+		return vehicle
+	}
+
+	cases := []struct {
+		name        string
+		batch       []internal.Vehicle
+		wantCreated int
+		wantUpdated int
+		wantErr     error
+	}{
+		{"update and create", []internal.Vehicle{row(0, "AB123CD", "blue"), row(2, "EF456GH", "green")}, 1, 1, nil},
+		{"no registrations", []internal.Vehicle{row(2, "", "blue"), row(3, "", "green")}, 2, 0, nil},
+		{"existing plate twice", []internal.Vehicle{row(0, "AB123CD", "blue"), row(0, "AB123CD", "green")}, 0, 0, internal.ErrRegistrationAlreadyExists},
+		{"existing plate twice in other formats", []internal.Vehicle{row(0, "AB123CD", "blue"), row(0, "ab-123 cd", "green")}, 0, 0, internal.ErrRegistrationAlreadyExists},
+		{"new plate twice", []internal.Vehicle{row(2, "EF456GH", "blue"), row(3, "EF456GH", "green")}, 0, 0, internal.ErrRegistrationAlreadyExists},
+		{"id in use", []internal.Vehicle{row(1, "EF456GH", "blue")}, 0, 0, internal.ErrCarAlreadyExists},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx := context.Background()
+			rp := NewVehicleMap(map[int]internal.Vehicle{1: row(1, "AB123CD", "red")})
+
+			created, updated, err := rp.UpsertByRegistration(ctx, c.batch)
+			if !errors.Is(err, c.wantErr) {
+				t.Fatalf("UpsertByRegistration() error = %v, want %v", err, c.wantErr)
+			}
+			if len(created) != c.wantCreated || len(updated) != c.wantUpdated {
+				t.Fatalf("UpsertByRegistration() = %d created and %d updated, want %d and %d", len(created), len(updated), c.wantCreated, c.wantUpdated)
+			}
+
+			// a rejected batch leaves the vehicle, its history and the indexes as they were
+			wantRevisions := 1 + c.wantUpdated
+			if h, _ := rp.FindHistory(ctx, 1); len(h) != wantRevisions {
+				t.Fatalf("history of 1 = %d revisions, want %d", len(h), wantRevisions)
+			}
+			if err != nil {
+				red, _ := rp.FindWhere(ctx, internal.VehicleFilter{Color: "red"})
+				all, _ := rp.FindAll(ctx)
+				if len(red) != 1 || len(all) != 1 {
+					t.Fatalf("vehicles = %d, red ones = %d, want 1 and 1", len(all), len(red))
+				}
+			}
+			if err := rp.CheckAggregates(); err != nil {
+				t.Fatal(err)
+			}
+			for _, attribute := range sortedAttributes {
+				if n, want := len(*rp.indexes.sorted[attribute]), len(rp.db); n != want {
+					t.Fatalf("%s index = %d entries, want %d", attribute, n, want)
+				}
+			}
+		})
+	}
+}
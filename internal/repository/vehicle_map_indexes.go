@@ -0,0 +1,321 @@
+package repository
+
+import (
+	"app/internal"
+	"math"
+	"sort"
+)
+
+// hashedAttributes are the attributes with a hash index, for equality lookups
+var hashedAttributes = []string{"brand", "color", "fuel_type", "transmission", "registration", "vin"}
+
+// sortedAttributes are the attributes with a sorted index, for range lookups
+var sortedAttributes = []string{"year", "weight", "max_speed", "height", "length", "width"}
+
+// hashIndex is a set of vehicle ids by attribute value
+type hashIndex map[string]map[int]struct{}
+
+// add is a method that adds an id to the set of a value
+func (ix hashIndex) add(value string, id int) {
+	ids, ok := ix[value]
+	if !ok {
+		ids = make(map[int]struct{})
+		ix[value] = ids
+	}
+	ids[id] = struct{}{}
+}
+
+// remove is a method that removes an id from the set of a value
+func (ix hashIndex) remove(value string, id int) {
+	delete(ix[value], id)
+	if len(ix[value]) == 0 {
+		delete(ix, value)
+	}
+}
+
+// ids is a method that returns the ids with the given value
+func (ix hashIndex) ids(value string) []int {
+	ids := make([]int, 0, len(ix[value]))
+	for id := range ix[value] {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// sortedEntry is an entry of a sorted index
+type sortedEntry struct {
+	value float64
+	id    int
+}
+
+// sortedIndex is a list of entries sorted by value and then by id
+type sortedIndex []sortedEntry
+
+// less returns true when the entry a goes before the entry b
+func (a sortedEntry) less(b sortedEntry) bool {
+	return a.value < b.value || (a.value == b.value && a.id < b.id)
+}
+
+// sortEntries sorts entries by value and then by id
+func sortEntries(entries []sortedEntry) {
+	sort.Slice(entries, func(i, j int) bool { return entries[i].less(entries[j]) })
+}
+
+// search is a method that returns the position of the first entry not less than e
+func (ix sortedIndex) search(e sortedEntry) int {
+	return sort.Search(len(ix), func(i int) bool { return !ix[i].less(e) })
+}
+
+// addAll is a method that inserts the entries keeping the order. The entries are sorted and merged
+// from the back in a single pass, so a batch costs O(n + k log k) instead of one O(n) insert each
+func (ix *sortedIndex) addAll(entries []sortedEntry) {
+	if len(entries) == 0 {
+		return
+	}
+	sortEntries(entries)
+
+	s := *ix
+	n := len(s)
+	s = append(s, entries...)
+	i, j, k := n-1, len(entries)-1, len(s)-1
+	for ; j >= 0; k-- {
+		if i >= 0 && entries[j].less(s[i]) {
+			s[k] = s[i]
+			i--
+		} else {
+			s[k] = entries[j]
+			j--
+		}
+	}
+	*ix = s
+}
+
+// removeAll is a method that deletes the entries in a single pass from the position of the first one
+func (ix *sortedIndex) removeAll(entries []sortedEntry) {
+	if len(entries) == 0 {
+		return
+	}
+	sortEntries(entries)
+
+	s := *ix
+	k := s.search(entries[0])
+	j := 0
+	for i := k; i < len(s); i++ {
+		for j < len(entries) && entries[j].less(s[i]) {
+			j++
+		}
+		if j < len(entries) && entries[j] == s[i] {
+			j++
+			continue
+		}
+		s[k] = s[i]
+		k++
+	}
+	*ix = s[:k]
+}
+
+// between is a method that returns the ids with a value in [min, max]
+func (ix sortedIndex) between(min, max float64) []int {
+	from := sort.Search(len(ix), func(i int) bool { return ix[i].value >= min })
+	to := sort.Search(len(ix), func(i int) bool { return ix[i].value > max })
+	if to < from {
+		return nil
+	}
+	ids := make([]int, 0, to-from)
+	for _, e := range ix[from:to] {
+		ids = append(ids, e.id)
+	}
+	return ids
+}
+
+// vehicleIndexes are the secondary indexes of a VehicleMap.
+// They cover every vehicle in db, the ones in the trash included
+type vehicleIndexes struct {
+	hash   map[string]hashIndex
+	sorted map[string]*sortedIndex
+	// text is the inverted index of the search tokens
+	text *textIndex
+}
+
+// newVehicleIndexes is a function that builds the indexes of the given vehicles
+func newVehicleIndexes(db map[int]internal.Vehicle) *vehicleIndexes {
+	ix := &vehicleIndexes{
+		hash:   make(map[string]hashIndex),
+		sorted: make(map[string]*sortedIndex),
+		text:   newTextIndex(),
+	}
+	for _, attribute := range hashedAttributes {
+		ix.hash[attribute] = make(hashIndex)
+	}
+	for _, attribute := range sortedAttributes {
+		sorted := make(sortedIndex, 0, len(db))
+		ix.sorted[attribute] = &sorted
+	}
+
+	// bulk build: append everything and sort once
+	for _, vehicle := range db {
+		for _, token := range internal.VehicleSearchTokens(vehicle) {
+			ix.text.ids.add(token, vehicle.Id)
+		}
+		for _, attribute := range hashedAttributes {
+			ix.hash[attribute].add(hashedValue(vehicle, attribute), vehicle.Id)
+		}
+		for _, attribute := range sortedAttributes {
+			*ix.sorted[attribute] = append(*ix.sorted[attribute], sortedEntry{value: sortedValue(vehicle, attribute), id: vehicle.Id})
+		}
+	}
+	for _, sorted := range ix.sorted {
+		sortEntries(*sorted)
+	}
+	ix.text.build()
+	return ix
+}
+
+// addAll is a method that indexes the vehicles
+func (ix *vehicleIndexes) addAll(vehicles []internal.Vehicle) {
+	ix.text.addAll(vehicles)
+	for _, vehicle := range vehicles {
+		for _, attribute := range hashedAttributes {
+			ix.hash[attribute].add(hashedValue(vehicle, attribute), vehicle.Id)
+		}
+	}
+	for _, attribute := range sortedAttributes {
+		ix.sorted[attribute].addAll(sortedEntries(vehicles, attribute))
+	}
+}
+
+// removeAll is a method that removes the vehicles from the indexes
+func (ix *vehicleIndexes) removeAll(vehicles []internal.Vehicle) {
+	ix.text.removeAll(vehicles)
+	for _, vehicle := range vehicles {
+		for _, attribute := range hashedAttributes {
+			ix.hash[attribute].remove(hashedValue(vehicle, attribute), vehicle.Id)
+		}
+	}
+	for _, attribute := range sortedAttributes {
+		ix.sorted[attribute].removeAll(sortedEntries(vehicles, attribute))
+	}
+}
+
+// sortedEntries returns the entries of the vehicles for a sorted index
+func sortedEntries(vehicles []internal.Vehicle, attribute string) []sortedEntry {
+	entries := make([]sortedEntry, len(vehicles))
+	for i, vehicle := range vehicles {
+		entries[i] = sortedEntry{value: sortedValue(vehicle, attribute), id: vehicle.Id}
+	}
+	return entries
+}
+
+// hashedValue returns the normalized key of a vehicle for a hash index
+func hashedValue(vehicle internal.Vehicle, attribute string) string {
+	switch attribute {
+	case "registration":
+		return internal.NormalizePlate(vehicle.Registration)
+	case "vin":
+		return internal.NormalizeVIN(vehicle.VIN)
+	}
+	value, _ := internal.CategoricalField(vehicle, attribute)
+	return internal.NormalizeKey(value)
+}
+
+// sortedValue returns the value of a vehicle for a sorted index
+func sortedValue(vehicle internal.Vehicle, attribute string) float64 {
+	if attribute == "year" {
+		return float64(vehicle.FabricationYear)
+	}
+	value, _ := internal.NumericField(vehicle, attribute)
+	return value
+}
+
+// lookup is a method that returns the vehicles with the given ids, the caller must hold the lock
+func (r *VehicleMap) lookup(ids []int) []internal.Vehicle {
+	vehicles := make([]internal.Vehicle, 0, len(ids))
+	for _, id := range ids {
+		if vehicle, ok := r.db[id]; ok {
+			vehicles = append(vehicles, vehicle)
+		}
+	}
+	return vehicles
+}
+
+// candidates is a method that returns the ids of a superset of the vehicles matching the filter,
+// using the most selective index available, or nil with ok false when a full scan is needed
+func (r *VehicleMap) candidates(filter internal.VehicleFilter) (ids []int, ok bool) {
+	var options [][]int
+	for attribute, value := range map[string]string{
+		"brand":        filter.Brand,
+		"color":        filter.Color,
+		"fuel_type":    filter.FuelType,
+		"transmission": filter.Transmission,
+	} {
+		if value != "" {
+			options = append(options, r.indexes.hash[attribute].ids(internal.NormalizeKey(value)))
+		}
+	}
+	ranges := []struct {
+		attribute string
+		min, max  *float64
+	}{
+		{"year", intToFloat(filter.YearFrom), intToFloat(filter.YearTo)},
+		{"weight", filter.MinWeight, filter.MaxWeight},
+		{"length", filter.MinLength, filter.MaxLength},
+		{"width", filter.MinWidth, filter.MaxWidth},
+	}
+	for _, rg := range ranges {
+		if rg.min == nil && rg.max == nil {
+			continue
+		}
+		min, max := math.Inf(-1), math.Inf(1)
+		if rg.min != nil {
+			min = *rg.min
+		}
+		if rg.max != nil {
+			max = *rg.max
+		}
+		options = append(options, r.indexes.sorted[rg.attribute].between(min, max))
+	}
+
+	for _, option := range options {
+		if !ok || len(option) < len(ids) {
+			ids, ok = option, true
+		}
+	}
+	return
+}
+
+// intToFloat converts an optional int
+func intToFloat(v *int) *float64 {
+	if v == nil {
+		return nil
+	}
+	f := float64(*v)
+	return &f
+}
+
+// unionIDs returns the ids present in any of the lists, without duplicates
+func unionIDs(lists ...[]int) []int {
+	seen := make(map[int]struct{})
+	ids := make([]int, 0)
+	for _, list := range lists {
+		for _, id := range list {
+			if _, ok := seen[id]; !ok {
+				seen[id] = struct{}{}
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+// scan is a method that returns the vehicles to check against the filter, the caller must hold the lock
+func (r *VehicleMap) scan(filter internal.VehicleFilter) []internal.Vehicle {
+	ids, ok := r.candidates(filter)
+	if !ok {
+		vehicles := make([]internal.Vehicle, 0, len(r.db))
+		for _, vehicle := range r.db {
+			vehicles = append(vehicles, vehicle)
+		}
+		return vehicles
+	}
+	return r.lookup(ids)
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+)
+
+// randomVehicles returns n vehicles with attributes spread over a few brands, colors and ranges
+func randomVehicles(n int, seed int64) map[int]internal.Vehicle {
+	rnd := rand.New(rand.NewSource(seed))
+	brands := []string{"Ford", "Fiat", "Renault", "Toyota", "Honda", "Kia", "Audi", "Seat"}
+	colors := []string{"red", "blue", "black", "white", "gray"}
+	db := make(map[int]internal.Vehicle, n)
+	for id := 1; id <= n; id++ {
+		db[id] = internal.Vehicle{Id: id, VehicleAttributes: internal.VehicleAttributes{
+			Brand:           brands[rnd.Intn(len(brands))],
+			Model:           fmt.Sprintf("Model %d", rnd.Intn(50)),
+			Color:           colors[rnd.Intn(len(colors))],
+			FabricationYear: 1990 + rnd.Intn(35),
+			Capacity:        2 + rnd.Intn(6),
+			MaxSpeed:        float64(100 + rnd.Intn(150)),
+			FuelType:        "gas",
+			Transmission:    "manual",
+			Weight:          float64(800 + rnd.Intn(2000)),
+			Dimensions:      internal.Dimensions{Height: 1.5, Length: float64(3 + rnd.Intn(3)), Width: 2},
+		}}
+	}
+	return db
+}
+
+func TestSortedIndex_AddAllRemoveAll(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	var ix sortedIndex
+	present := make(map[sortedEntry]struct{})
+	for round := 0; round < 50; round++ {
+		var added []sortedEntry
+		for i := rnd.Intn(20); i >= 0; i-- {
+			e := sortedEntry{value: float64(rnd.Intn(10)), id: round*100 + i}
+			added = append(added, e)
+			present[e] = struct{}{}
+		}
+		ix.addAll(added)
+
+		var removed []sortedEntry
+		for e := range present {
+			if rnd.Intn(3) == 0 {
+				removed = append(removed, e)
+				delete(present, e)
+			}
+		}
+		// an entry not in the index is ignored
+		removed = append(removed, sortedEntry{value: 5, id: -1})
+		ix.removeAll(removed)
+
+		want := make([]sortedEntry, 0, len(present))
+		for e := range present {
+			want = append(want, e)
+		}
+		sortEntries(want)
+		if !reflect.DeepEqual([]sortedEntry(ix), want) {
+			t.Fatalf("round %d: index %v, want %v", round, ix, want)
+		}
+	}
+}
+
+func TestVehicleMap_FindWhere(t *testing.T) {
+	ctx := context.Background()
+	rp := NewVehicleMap(randomVehicles(2000, 1))
+	// the indexes must follow the writes, in batches or one by one
+	if _, err := rp.UpdateWhere(ctx, internal.VehicleFilter{Brand: "Ford"}, internal.VehiclePatch{Color: ptr("green")}, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rp.DeleteWhere(ctx, internal.VehicleFilter{Brand: "Kia"}, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rp.UpdateMaxSpeed(ctx, 10, 300); err != nil {
+		t.Fatal(err)
+	}
+
+	year, weight := 2000, 1500.0
+	cases := []struct {
+		name   string
+		filter internal.VehicleFilter
+	}{
+		{"brand", internal.VehicleFilter{Brand: "fiat"}},
+		{"updated color", internal.VehicleFilter{Color: "green"}},
+		{"deleted brand", internal.VehicleFilter{Brand: "Kia"}},
+		{"year range", internal.VehicleFilter{YearFrom: &year, YearTo: &year}},
+		{"brand and weight", internal.VehicleFilter{Brand: "Audi", MaxWeight: &weight}},
+		{"full scan", internal.VehicleFilter{MinCapacity: ptr(6)}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := rp.FindWhere(ctx, c.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := make([]internal.Vehicle, 0)
+			for _, vehicle := range rp.db {
+				if vehicle.DeletedAt == nil && c.filter.Match(vehicle) {
+					want = append(want, vehicle)
+				}
+			}
+			sort.Slice(want, func(i, j int) bool { return want[i].Id < want[j].Id })
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("FindWhere() returned %d vehicles, a scan %d", len(got), len(want))
+			}
+		})
+	}
+}
+
+var (
+	benchmarkOnce sync.Once
+	benchmarkMap  *VehicleMap
+)
+
+func BenchmarkVehicleMap_FindWhere(b *testing.B) {
+	benchmarkOnce.Do(func() { benchmarkMap = NewVehicleMap(randomVehicles(1_000_000, 1)) })
+	ctx := context.Background()
+	minWeight, maxWeight := 1000.0, 1010.0
+
+	benchmarks := []struct {
+		name   string
+		filter internal.VehicleFilter
+	}{
+		// the weight range is the most selective index
+		{"indexed", internal.VehicleFilter{Brand: "Ford", MinWeight: &minWeight, MaxWeight: &maxWeight}},
+		// the capacity has no index, so every vehicle is checked
+		{"scanned", internal.VehicleFilter{MinCapacity: ptr(7)}},
+	}
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := benchmarkMap.FindWhere(ctx, bm.filter); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
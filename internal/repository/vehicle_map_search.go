@@ -0,0 +1,236 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"math"
+	"sort"
+	"strings"
+)
+
+// textIndex is the inverted index of the search tokens. Besides the ids by token it keeps the tokens
+// sorted, for prefix lookups, and by length, so that only tokens of a close length are compared for typos
+type textIndex struct {
+	// ids is the set of vehicle ids by token
+	ids hashIndex
+	// tokens are the distinct tokens in ascending order
+	tokens []string
+	// lengths is the set of tokens by length in bytes
+	lengths map[int]map[string]struct{}
+}
+
+// newTextIndex is a function that returns an empty textIndex
+func newTextIndex() *textIndex {
+	return &textIndex{ids: make(hashIndex), lengths: make(map[int]map[string]struct{})}
+}
+
+// build is a method that derives the sorted tokens and the lengths from ids at once, after a bulk load
+func (ix *textIndex) build() {
+	ix.tokens = make([]string, 0, len(ix.ids))
+	ix.lengths = make(map[int]map[string]struct{})
+	for token := range ix.ids {
+		ix.tokens = append(ix.tokens, token)
+		ix.addLength(token)
+	}
+	sort.Strings(ix.tokens)
+}
+
+// addLength is a method that adds a token to the set of its length
+func (ix *textIndex) addLength(token string) {
+	tokens, ok := ix.lengths[len(token)]
+	if !ok {
+		tokens = make(map[string]struct{})
+		ix.lengths[len(token)] = tokens
+	}
+	tokens[token] = struct{}{}
+}
+
+// addAll is a method that indexes the tokens of the vehicles, the new tokens are merged into the sorted ones at once
+func (ix *textIndex) addAll(vehicles []internal.Vehicle) {
+	var added []string
+	for _, vehicle := range vehicles {
+		for _, token := range internal.VehicleSearchTokens(vehicle) {
+			if _, ok := ix.ids[token]; !ok {
+				added = append(added, token)
+				ix.addLength(token)
+			}
+			ix.ids.add(token, vehicle.Id)
+		}
+	}
+	if len(added) == 0 {
+		return
+	}
+
+	sort.Strings(added)
+	merged := make([]string, 0, len(ix.tokens)+len(added))
+	i, j := 0, 0
+	for i < len(ix.tokens) || j < len(added) {
+		if j == len(added) || (i < len(ix.tokens) && ix.tokens[i] < added[j]) {
+			merged = append(merged, ix.tokens[i])
+			i++
+		} else {
+			merged = append(merged, added[j])
+			j++
+		}
+	}
+	ix.tokens = merged
+}
+
+// removeAll is a method that removes the tokens of the vehicles, the tokens no longer used leave the sorted ones at once
+func (ix *textIndex) removeAll(vehicles []internal.Vehicle) {
+	removed := make(map[string]struct{})
+	for _, vehicle := range vehicles {
+		for _, token := range internal.VehicleSearchTokens(vehicle) {
+			ix.ids.remove(token, vehicle.Id)
+			if _, ok := ix.ids[token]; !ok {
+				removed[token] = struct{}{}
+			}
+		}
+	}
+	if len(removed) == 0 {
+		return
+	}
+
+	for token := range removed {
+		delete(ix.lengths[len(token)], token)
+		if len(ix.lengths[len(token)]) == 0 {
+			delete(ix.lengths, len(token))
+		}
+	}
+	tokens := ix.tokens[:0]
+	for _, token := range ix.tokens {
+		if _, ok := removed[token]; !ok {
+			tokens = append(tokens, token)
+		}
+	}
+	ix.tokens = tokens
+}
+
+// matches is a method that returns the tokens matching a query term with their similarity, from 0 to 1:
+// the exact token, the tokens it prefixes and the tokens within a few typos of it
+func (ix *textIndex) matches(term string) map[string]float64 {
+	matches := make(map[string]float64)
+	if _, ok := ix.ids[term]; ok {
+		matches[term] = 1
+	}
+	if len(term) >= 2 {
+		for i := sort.SearchStrings(ix.tokens, term); i < len(ix.tokens) && strings.HasPrefix(ix.tokens[i], term); i++ {
+			if ix.tokens[i] != term {
+				matches[ix.tokens[i]] = 0.8
+			}
+		}
+	}
+
+	maxDistance := maxTypos(term)
+	if maxDistance == 0 {
+		return matches
+	}
+	for length := len(term) - maxDistance; length <= len(term)+maxDistance; length++ {
+		for token := range ix.lengths[length] {
+			if _, ok := matches[token]; ok {
+				continue
+			}
+			if distance := editDistance(term, token); distance <= maxDistance {
+				matches[token] = 1 - 0.3*float64(distance)
+			}
+		}
+	}
+	return matches
+}
+
+// Search is a method that finds the vehicles matching the terms of a query, best matches first.
+// Every term is looked up in the inverted index (exact, prefix or with a few typos)
+// and weighted by how rare the token is, vehicles matching more terms rank higher
+func (r *VehicleMap) Search(ctx context.Context, query string, limit int) (results []internal.VehicleSearchResult, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	terms := internal.SearchTokens(query)
+	if len(terms) == 0 {
+		return nil, internal.ErrEmptySearchQuery
+	}
+
+	total := float64(len(r.db))
+	scores := make(map[int]float64)
+	matched := make(map[int]int)
+	for _, term := range terms {
+		// best match of the term for every vehicle
+		best := make(map[int]float64)
+		for token, similarity := range r.indexes.text.matches(term) {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			ids := r.indexes.text.ids[token]
+			score := similarity * math.Log(1+total/float64(len(ids)))
+			for id := range ids {
+				if score > best[id] {
+					best[id] = score
+				}
+			}
+		}
+		for id, score := range best {
+			scores[id] += score
+			matched[id]++
+		}
+	}
+
+	results = make([]internal.VehicleSearchResult, 0, len(scores))
+	for id, score := range scores {
+		vehicle := r.db[id]
+		if !r.visible(ctx, vehicle) {
+			continue
+		}
+		results = append(results, internal.VehicleSearchResult{
+			Vehicle: vehicle,
+			Score:   score * float64(matched[id]) / float64(len(terms)),
+		})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Vehicle.Id < results[j].Vehicle.Id
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return
+}
+
+// maxTypos returns the edit distance tolerated for a query term, it grows with the length of the term
+func maxTypos(term string) int {
+	switch {
+	case len(term) >= 8:
+		return 2
+	case len(term) >= 4:
+		return 1
+	}
+	return 0
+}
+
+// editDistance returns the optimal string alignment distance between two strings:
+// insertions, deletions, substitutions and transpositions of adjacent characters
+func editDistance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	d := make([][]int, len(s)+1)
+	for i := range d {
+		d[i] = make([]int, len(t)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(s)][len(t)]
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestTextIndex_Matches(t *testing.T) {
+	ix := newTextIndex()
+	ix.addAll([]internal.Vehicle{
+		{Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", Model: "Focus"}},
+		{Id: 2, VehicleAttributes: internal.VehicleAttributes{Brand: "Toyota", Model: "Corolla"}},
+		{Id: 3, VehicleAttributes: internal.VehicleAttributes{Brand: "Fiat", Model: "Fiorino"}},
+	})
+
+	cases := []struct {
+		term string
+		want map[string]float64
+	}{
+		{"ford", map[string]float64{"ford": 1}},
+		{"fo", map[string]float64{"ford": 0.8, "focus": 0.8}},
+		{"toyot", map[string]float64{"toyota": 0.8}},
+		{"frod", map[string]float64{"ford": 0.7}},
+		{"corola", map[string]float64{"corolla": 0.7}},
+		{"fiorion", map[string]float64{"fiorino": 0.7}},
+		// short terms tolerate no typos
+		{"fxd", map[string]float64{}},
+		{"volvo", map[string]float64{}},
+	}
+	for _, c := range cases {
+		t.Run(c.term, func(t *testing.T) {
+			if got := ix.matches(c.term); !reflect.DeepEqual(got, c.want) {
+				t.Fatalf("matches(%q) = %v, want %v", c.term, got, c.want)
+			}
+		})
+	}
+}
+
+func TestVehicleMap_Search(t *testing.T) {
+	ctx := context.Background()
+	rp := NewVehicleMap(map[int]internal.Vehicle{
+		1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", Model: "Focus", Color: "red"}},
+		2: {Id: 2, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", Model: "Fiesta", Color: "blue"}},
+		3: {Id: 3, VehicleAttributes: internal.VehicleAttributes{Brand: "Toyota", Model: "Corolla", Color: "red"}},
+	})
+	if err := rp.CreateVehicle(ctx, internal.Vehicle{Id: 4, VehicleAttributes: internal.VehicleAttributes{Brand: "Kia", Model: "Rio", Color: "red"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rp.Delete(ctx, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		query   string
+		wantIDs []int
+		wantErr error
+	}{
+		{"ford", []int{1}, nil},
+		{"frod focus", []int{1}, nil},
+		{"red toyota", []int{3, 1, 4}, nil},
+		{"ki", []int{4}, nil},
+		{"fiesta", []int{}, nil},
+		{" ,", nil, internal.ErrEmptySearchQuery},
+	}
+	for _, c := range cases {
+		t.Run(c.query, func(t *testing.T) {
+			results, err := rp.Search(ctx, c.query, 0)
+			if !errors.Is(err, c.wantErr) {
+				t.Fatalf("Search() error = %v, want %v", err, c.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			ids := make([]int, len(results))
+			for i, result := range results {
+				ids[i] = result.Vehicle.Id
+			}
+			if !reflect.DeepEqual(ids, c.wantIDs) {
+				t.Fatalf("Search(%q) = %v, want %v", c.query, ids, c.wantIDs)
+			}
+		})
+	}
+
+	// the sorted tokens and the lengths follow the writes
+	text := rp.indexes.text
+	tokens := make([]string, 0, len(text.ids))
+	length := 0
+	for token := range text.ids {
+		tokens = append(tokens, token)
+	}
+	for _, set := range text.lengths {
+		length += len(set)
+	}
+	sort.Strings(tokens)
+	if !reflect.DeepEqual(text.tokens, tokens) || length != len(tokens) {
+		t.Fatalf("tokens %v in %d lengths, want %v", text.tokens, length, tokens)
+	}
+}
+
+func BenchmarkVehicleMap_Search(b *testing.B) {
+	benchmarkOnce.Do(func() { benchmarkMap = NewVehicleMap(randomVehicles(1_000_000, 1)) })
+	ctx := context.Background()
+
+	for _, query := range []string{"toyota", "toy", "toyoat red"} {
+		b.Run(query, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := benchmarkMap.Search(ctx, query, 10); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"fmt"
+)
+
+// Transition is a method that changes the status of an active vehicle, if its current status allows the transition
+func (r *VehicleMap) Transition(ctx context.Context, vehicleID int, to internal.VehicleStatus) (change internal.VehicleChange, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.active(vehicleID) {
+		return internal.VehicleChange{}, internal.ErrVehicleNotFounded
+	}
+
+	vehicle := r.db[vehicleID]
+	if !vehicle.Status.CanTransition(to) {
+		return internal.VehicleChange{}, fmt.Errorf("%w: from %s to %s, allowed: %v", internal.ErrInvalidTransition, vehicle.Status, to, vehicle.Status.Transitions())
+	}
+	change.Before = vehicle
+	vehicle.Status = to
+
+	r.put(vehicle)
+	r.commit(vehicle, false)
+
+	change.After = vehicle
+	return
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestVehicleMap_Transition(t *testing.T) {
+	cases := []struct {
+		name    string
+		path    []internal.VehicleStatus
+		deleted bool
+		wantErr error
+	}{
+		{"allowed", []internal.VehicleStatus{internal.VehicleStatusInMaintenance, internal.VehicleStatusInService}, false, nil},
+		{"retired back into service", []internal.VehicleStatus{internal.VehicleStatusRetired, internal.VehicleStatusInService}, false, nil},
+		{"same status", []internal.VehicleStatus{internal.VehicleStatusInService}, false, internal.ErrInvalidTransition},
+		{"out of sold", []internal.VehicleStatus{internal.VehicleStatusSold, internal.VehicleStatusInService}, false, internal.ErrInvalidTransition},
+		{"in the trash", []internal.VehicleStatus{internal.VehicleStatusInMaintenance}, true, internal.ErrVehicleNotFounded},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx := context.Background()
+			vehicle := testVehicle(1, "Ford", 200)
+			vehicle.Status = internal.VehicleStatusInService
+			rp := NewVehicleMap(map[int]internal.Vehicle{1: vehicle})
+			if c.deleted {
+				if _, err := rp.Delete(ctx, 1); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var err error
+			applied := 0
+			for _, to := range c.path {
+				var change internal.VehicleChange
+				if change, err = rp.Transition(ctx, 1, to); err != nil {
+					break
+				}
+				if change.After.Status != to {
+					t.Fatalf("Transition() = %+v, want status %s", change.After, to)
+				}
+				applied++
+			}
+			if !errors.Is(err, c.wantErr) {
+				t.Fatalf("Transition() error = %v, want %v", err, c.wantErr)
+			}
+
+			// every applied transition is a revision whose only change is the status
+			history, _ := rp.FindHistory(ctx, 1)
+			revisions := history[len(history)-applied:]
+			for i, revision := range revisions {
+				changes := internal.DiffVehicles(history[len(history)-applied+i-1].Vehicle, revision.Vehicle)
+				if len(changes) != 1 || changes[0].Field != "status" || changes[0].To != c.path[i] {
+					t.Fatalf("revision %d changes = %+v, want the status only", revision.Version, changes)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"time"
+)
+
+// visible is a method that checks if a vehicle must be returned by the Find* queries
+func (r *VehicleMap) visible(ctx context.Context, vehicle internal.Vehicle) bool {
+	return vehicle.DeletedAt == nil || internal.IncludeDeletedFromContext(ctx)
+}
+
+// active is a method that checks if a vehicle exists and is not in the trash, the caller must hold the lock
+func (r *VehicleMap) active(vehicleID int) bool {
+	vehicle, ok := r.db[vehicleID]
+	return ok && vehicle.DeletedAt == nil
+}
+
+// FindTrash is a method that returns the soft deleted vehicles
+func (r *VehicleMap) FindTrash(ctx context.Context) (v map[int]internal.Vehicle, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	v = make(map[int]internal.Vehicle)
+	for key, value := range r.db {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if value.DeletedAt != nil {
+			v[key] = value
+		}
+	}
+	return
+}
+
+// Restore is a method that takes a vehicle out of the trash
+func (r *VehicleMap) Restore(ctx context.Context, vehicleID int) (internal.Vehicle, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	vehicle, ok := r.db[vehicleID]
+	if !ok || vehicle.DeletedAt == nil {
+		return internal.Vehicle{}, internal.ErrVehicleNotInTrash
+	}
+
+	// the identifiers may have been given to another vehicle meanwhile
+	if err := r.unique(vehicle, nil); err != nil {
+		return internal.Vehicle{}, err
+	}
+	vehicle.DeletedAt = nil
+
+	r.put(vehicle)
+	r.commit(vehicle, false)
+
+	return vehicle, nil
+}
+
+// Purge is a method that permanently removes the vehicles deleted before the given moment, along with their history
+func (r *VehicleMap) Purge(ctx context.Context, deletedBefore time.Time) (purged []internal.Vehicle, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	purged = make([]internal.Vehicle, 0)
+	// the purged vehicles leave the indexes at once, even when the context ends halfway
+	defer func() { r.indexes.removeAll(purged) }()
+	for id, vehicle := range r.db {
+		if err := ctx.Err(); err != nil {
+			return purged, err
+		}
+		if vehicle.DeletedAt == nil || !vehicle.DeletedAt.Before(deletedBefore) {
+			continue
+		}
+		delete(r.db, id)
+		delete(r.history, id)
+		purged = append(purged, vehicle)
+	}
+	return
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestVehicleMap_Trash(t *testing.T) {
+	ctx := context.Background()
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := t0
+	// plated returns a vehicle with the given id and registration
+	plated := func(id int, plate string) internal.Vehicle {
+		vehicle := testVehicle(id, "Ford", 200)
+		vehicle.Registration = plate
+		return vehicle
+	}
+	rp := NewVehicleMap(map[int]internal.Vehicle{1: plated(1, "AB123CD"), 2: plated(2, "EF456GH"), 3: plated(3, "IJ789KL")})
+	rp.now = func() time.Time { return now }
+
+	steps := []struct {
+		name    string
+		run     func() error
+		wantErr error
+		// active and trash are the ids of the vehicles out of and in the trash after the step
+		active, trash []int
+	}{
+		{"delete", func() error { _, err := rp.Delete(ctx, 1); return err }, nil, []int{2, 3}, []int{1}},
+		{"delete again", func() error { _, err := rp.Delete(ctx, 1); return err }, internal.ErrVehicleNotFounded, []int{2, 3}, []int{1}},
+		{"delete later", func() error { now = t0.Add(time.Hour); _, err := rp.Delete(ctx, 2); return err }, nil, []int{3}, []int{1, 2}},
+		{"restore", func() error { _, err := rp.Restore(ctx, 2); return err }, nil, []int{2, 3}, []int{1}},
+		{"restore an active vehicle", func() error { _, err := rp.Restore(ctx, 2); return err }, internal.ErrVehicleNotInTrash, []int{2, 3}, []int{1}},
+		{"restore an unknown vehicle", func() error { _, err := rp.Restore(ctx, 9); return err }, internal.ErrVehicleNotInTrash, []int{2, 3}, []int{1}},
+		{"restore a taken registration", func() error {
+			// the registration of the vehicle in the trash is given to another one
+			if err := rp.CreateVehicle(ctx, plated(4, "ab-123-cd")); err != nil {
+				return err
+			}
+			_, err := rp.Restore(ctx, 1)
+			return err
+		}, internal.ErrRegistrationAlreadyExists, []int{2, 3, 4}, []int{1}},
+		{"purge the newer ones", func() error { _, err := rp.Purge(ctx, t0); return err }, nil, []int{2, 3, 4}, []int{1}},
+		{"purge", func() error {
+			purged, err := rp.Purge(ctx, t0.Add(time.Second))
+			if err == nil && (len(purged) != 1 || purged[0].Id != 1) {
+				t.Fatalf("Purge() = %v, want vehicle 1", purged)
+			}
+			return err
+		}, nil, []int{2, 3, 4}, []int{}},
+		{"restore a purged vehicle", func() error { _, err := rp.Restore(ctx, 1); return err }, internal.ErrVehicleNotInTrash, []int{2, 3, 4}, []int{}},
+	}
+	for _, step := range steps {
+		if err := step.run(); !errors.Is(err, step.wantErr) {
+			t.Fatalf("%s: error = %v, want %v", step.name, err, step.wantErr)
+		}
+
+		active, _ := rp.FindAll(ctx)
+		trash, _ := rp.FindTrash(ctx)
+		all, _ := rp.FindAll(internal.ContextWithIncludeDeleted(ctx))
+		if len(active) != len(step.active) || len(trash) != len(step.trash) || len(all) != len(active)+len(trash) {
+			t.Fatalf("%s: %d active, %d in the trash and %d in all, want %v and %v", step.name, len(active), len(trash), len(all), step.active, step.trash)
+		}
+		for _, id := range step.active {
+			if _, ok := active[id]; !ok {
+				t.Fatalf("%s: vehicle %d not active", step.name, id)
+			}
+		}
+		for _, id := range step.trash {
+			if v, ok := trash[id]; !ok || v.DeletedAt == nil {
+				t.Fatalf("%s: vehicle %d not in the trash", step.name, id)
+			}
+		}
+		if err := rp.CheckAggregates(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+	}
+
+	// the purged vehicles leave no history nor index entries behind
+	if _, err := rp.FindHistory(ctx, 1); !errors.Is(err, internal.ErrVehicleNotFounded) {
+		t.Fatalf("FindHistory() of a purged vehicle error = %v", err)
+	}
+	if v, err := rp.FindByRegistration(ctx, "AB123CD"); err != nil || v.Id != 4 {
+		t.Fatalf("FindByRegistration() = %d %v, want 4", v.Id, err)
+	}
+}
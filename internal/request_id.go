@@ -0,0 +1,17 @@
+package internal
+
+import "context"
+
+// requestIDKey is the key used to store the request id in a context
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a copy of ctx carrying the request id
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request id stored in ctx, or an empty string
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
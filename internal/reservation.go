@@ -0,0 +1,63 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrReservationNotFound = errors.New("reservation not found")
+	ErrReservationConflict = errors.New("vehicle already reserved in that time range")
+	ErrInvalidReservation  = errors.New("invalid reservation")
+)
+
+// Reservation is a struct that represents the booking of a vehicle over a time range
+type Reservation struct {
+	// Id is the unique identifier of the reservation, given by the repository
+	Id int
+	// VehicleID is the id of the vehicle reserved
+	VehicleID int
+	// From and To are the start and the end of the reservation, To excluded
+	From, To time.Time
+	// ReservedBy is who the vehicle is reserved for
+	ReservedBy string
+	// Purpose is the reason of the reservation
+	Purpose string
+	// Passengers is the number of passengers expected
+	Passengers int
+	// CreatedAt is the moment the reservation was made
+	CreatedAt time.Time
+	// CancelledAt is the moment the reservation was cancelled (nil while in effect)
+	CancelledAt *time.Time
+}
+
+// Overlaps is a method that checks if the reservation is in effect at some moment of a time range, to excluded
+func (r Reservation) Overlaps(from, to time.Time) bool {
+	return r.CancelledAt == nil && r.From.Before(to) && from.Before(r.To)
+}
+
+// ReservationRepository is an interface that represents a repository of reservations
+type ReservationRepository interface {
+	// FindByVehicle returns the reservations of a vehicle, earliest first
+	FindByVehicle(ctx context.Context, vehicleID int) (r []Reservation, err error)
+	// FindOverlapping returns the reservations in effect at some moment of a time range
+	FindOverlapping(ctx context.Context, from, to time.Time) (r []Reservation, err error)
+	// Create adds a reservation with the next id. It returns ErrReservationConflict when it overlaps another one of the vehicle
+	Create(ctx context.Context, reservation Reservation) (r Reservation, err error)
+	// Cancel sets the cancellation of a reservation
+	Cancel(ctx context.Context, id int, at time.Time) (r Reservation, err error)
+	VehicleDependentRepository
+}
+
+// ReservationService is an interface that represents a service of reservations
+type ReservationService interface {
+	// FindByVehicle returns the reservations of a vehicle overlapping a time range (zero means unbounded), earliest first
+	FindByVehicle(ctx context.Context, vehicleID int, from, to time.Time) (r []Reservation, err error)
+	// Create reserves a vehicle
+	Create(ctx context.Context, reservation Reservation) (r Reservation, err error)
+	// Cancel cancels a reservation of a vehicle
+	Cancel(ctx context.Context, vehicleID int, id int) (r Reservation, err error)
+	// Available returns the vehicles in service matching the filter without reservations in a time range, sorted by id
+	Available(ctx context.Context, filter VehicleFilter, from, to time.Time) (v []Vehicle, err error)
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"app/internal"
+	"context"
+	"fmt"
+	"time"
+)
+
+// NewAssignmentDefault is a function that returns a new instance of AssignmentDefault
+func NewAssignmentDefault(rp internal.AssignmentRepository, rpVehicle internal.VehicleRepository, rpOwner internal.OwnerRepository, rpDriver internal.DriverRepository, rf *References) *AssignmentDefault {
+	return &AssignmentDefault{rp: rp, rpVehicle: rpVehicle, rpOwner: rpOwner, rpDriver: rpDriver, rf: rf}
+}
+
+// AssignmentDefault is a struct that represents the default service for the owners and drivers of the vehicles
+type AssignmentDefault struct {
+	// rp is the repository of assignments
+	rp internal.AssignmentRepository
+	// rpVehicle is the repository of vehicles, used to check the vehicle of an assignment exists
+	rpVehicle internal.VehicleRepository
+	// rpOwner is the repository of owners, used to check the owner of an assignment exists
+	rpOwner internal.OwnerRepository
+	// rpDriver is the repository of drivers, used to check the driver of an assignment exists
+	rpDriver internal.DriverRepository
+	// rf serializes the assignments with the deletions of the owners and drivers they refer to
+	rf *References
+}
+
+// person is a method that checks that the person of a role exists
+func (s *AssignmentDefault) person(ctx context.Context, role internal.AssignmentRole, id int) (err error) {
+	switch role {
+	case internal.AssignmentOwner:
+		_, err = s.rpOwner.FindOne(ctx, id)
+	case internal.AssignmentDriver:
+		_, err = s.rpDriver.FindOne(ctx, id)
+	default:
+		err = fmt.Errorf("%w: role must be %s or %s", internal.ErrInvalidAssignment, internal.AssignmentOwner, internal.AssignmentDriver)
+	}
+	return
+}
+
+// Assign is a method that puts a person in charge of a vehicle from the given moment (now when zero),
+// taking over the current one of the same role
+func (s *AssignmentDefault) Assign(ctx context.Context, assignment internal.Assignment) (internal.Assignment, *internal.Assignment, error) {
+	// the person can not be removed between its check and the assignment
+	release := s.rf.Refer()
+	defer release()
+
+	if err := s.person(ctx, assignment.Role, assignment.PersonID); err != nil {
+		return internal.Assignment{}, nil, err
+	}
+	if _, err := s.rpVehicle.FindOne(ctx, assignment.VehicleID); err != nil {
+		return internal.Assignment{}, nil, err
+	}
+
+	if assignment.From.IsZero() {
+		assignment.From = time.Now().UTC()
+	}
+	if assignment.To != nil && !assignment.To.After(assignment.From) {
+		return internal.Assignment{}, nil, fmt.Errorf("%w: it can not end before it starts", internal.ErrInvalidAssignment)
+	}
+	return s.rp.Create(ctx, assignment)
+}
+
+// End is a method that ends an assignment of a vehicle at the given moment (now when zero)
+func (s *AssignmentDefault) End(ctx context.Context, vehicleID int, id int, at time.Time) (internal.Assignment, error) {
+	assignments, err := s.rp.FindByVehicle(ctx, vehicleID, "")
+	if err != nil {
+		return internal.Assignment{}, err
+	}
+	for _, a := range assignments {
+		if a.Id != id {
+			continue
+		}
+		if at.IsZero() {
+			at = time.Now().UTC()
+		}
+		return s.rp.End(ctx, id, at)
+	}
+	return internal.Assignment{}, internal.ErrAssignmentNotFound
+}
+
+// FindByVehicle is a method that returns the history of assignments of a vehicle with the given role
+func (s *AssignmentDefault) FindByVehicle(ctx context.Context, vehicleID int, role internal.AssignmentRole) ([]internal.Assignment, error) {
+	if role != "" && role != internal.AssignmentOwner && role != internal.AssignmentDriver {
+		return nil, fmt.Errorf("%w: role must be %s or %s", internal.ErrInvalidAssignment, internal.AssignmentOwner, internal.AssignmentDriver)
+	}
+	return s.rp.FindByVehicle(ctx, vehicleID, role)
+}
+
+// FindByPerson is a method that returns the history of assignments of an owner or a driver
+func (s *AssignmentDefault) FindByPerson(ctx context.Context, role internal.AssignmentRole, personID int) ([]internal.Assignment, error) {
+	if err := s.person(ctx, role, personID); err != nil {
+		return nil, err
+	}
+	return s.rp.FindByPerson(ctx, role, personID)
+}
+
+// FilterVehicles is a method that returns the vehicles that meet the criteria of the filter at its moment
+func (s *AssignmentDefault) FilterVehicles(ctx context.Context, v map[int]internal.Vehicle, filter internal.AssignmentFilter) (map[int]internal.Vehicle, error) {
+	if filter.Empty() {
+		return v, nil
+	}
+	at := filter.At
+	if at.IsZero() {
+		at = time.Now().UTC()
+	}
+
+	owners, err := s.rp.FindActive(ctx, internal.AssignmentOwner, at)
+	if err != nil {
+		return nil, err
+	}
+	drivers, err := s.rp.FindActive(ctx, internal.AssignmentDriver, at)
+	if err != nil {
+		return nil, err
+	}
+
+	r := make(map[int]internal.Vehicle)
+	for id, vehicle := range v {
+		owner, hasOwner := owners[id]
+		driver, hasDriver := drivers[id]
+		switch {
+		case filter.OwnerID != nil && (!hasOwner || owner.PersonID != *filter.OwnerID),
+			filter.DriverID != nil && (!hasDriver || driver.PersonID != *filter.DriverID),
+			filter.Unassigned && hasDriver:
+			continue
+		}
+		r[id] = vehicle
+	}
+	return r, nil
+}
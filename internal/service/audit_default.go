@@ -0,0 +1,36 @@
+package service
+
+import (
+	"app/internal"
+	"context"
+	"time"
+)
+
+// NewAuditDefault is a function that returns a new instance of AuditDefault
+func NewAuditDefault(rp internal.AuditRepository) *AuditDefault {
+	return &AuditDefault{rp: rp}
+}
+
+// AuditDefault is a struct that represents the default service for the audit log
+type AuditDefault struct {
+	// rp is the repository that will be used by the service
+	rp internal.AuditRepository
+}
+
+// FindAll is a method that returns the entries between from and to
+func (s *AuditDefault) FindAll(ctx context.Context, from, to time.Time) ([]internal.AuditEntry, error) {
+	entries, err := s.rp.FindAll(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// FindByVehicle is a method that returns the entries of a vehicle between from and to
+func (s *AuditDefault) FindByVehicle(ctx context.Context, vehicleID int, from, to time.Time) ([]internal.AuditEntry, error) {
+	entries, err := s.rp.FindByVehicle(ctx, vehicleID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAuditDefault_Find(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(hours int) time.Time { return t0.Add(time.Duration(hours) * time.Hour) }
+	rp := repository.NewAuditSlice(nil)
+	for i, vehicleID := range []int{1, 2, 1, 2} {
+		if err := rp.Append(context.Background(), internal.AuditEntry{VehicleID: vehicleID, Timestamp: at(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sv := NewAuditDefault(rp)
+
+	cases := []struct {
+		name      string
+		vehicleID int
+		from, to  time.Time
+		want      []int
+	}{
+		{"all", 0, time.Time{}, time.Time{}, []int{1, 2, 3, 4}},
+		{"from", 0, at(2), time.Time{}, []int{3, 4}},
+		{"to", 0, time.Time{}, at(1), []int{1, 2}},
+		{"between", 0, at(1), at(2), []int{2, 3}},
+		{"empty range", 0, at(5), time.Time{}, []int{}},
+		{"vehicle", 1, time.Time{}, time.Time{}, []int{1, 3}},
+		{"vehicle from", 2, at(2), time.Time{}, []int{4}},
+		{"vehicle without entries", 3, time.Time{}, time.Time{}, []int{}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var entries []internal.AuditEntry
+			var err error
+			if c.vehicleID == 0 {
+				entries, err = sv.FindAll(context.Background(), c.from, c.to)
+			} else {
+				entries, err = sv.FindByVehicle(context.Background(), c.vehicleID, c.from, c.to)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != len(c.want) {
+				t.Fatalf("entries = %d, want %v", len(entries), c.want)
+			}
+			for i, e := range entries {
+				if e.Id != c.want[i] {
+					t.Fatalf("entries[%d] = %d, want %v", i, e.Id, c.want)
+				}
+			}
+		})
+	}
+}
+
+func TestVehicleDefault_Audit(t *testing.T) {
+	ctx := internal.ContextWithPrincipal(context.Background(), internal.Principal{Subject: "alice"})
+	rp := repository.NewVehicleMap(map[int]internal.Vehicle{1: testVehicle(1, "red")})
+	au := repository.NewAuditSlice(nil)
+	sv := NewVehicleDefault(rp, au, nil, testCatalogs(), nil, nil, nil, nil)
+
+	// the concurrent updates must each see the value left by another one
+	var wg sync.WaitGroup
+	for i := 1; i <= 50; i++ {
+		wg.Add(1)
+		go func(speed float64) {
+			defer wg.Done()
+			if _, err := sv.UpdateMaxSpeed(ctx, 1, speed); err != nil {
+				t.Error(err)
+			}
+		}(float64(i))
+	}
+	wg.Wait()
+	if _, err := sv.UpdateFuelType(ctx, 1, "manual"); err == nil {
+		t.Fatal("UpdateFuelType() out of the catalog succeeded")
+	}
+	if err := sv.Delete(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, _ := au.FindByVehicle(ctx, 1, time.Time{}, time.Time{})
+	if len(entries) != 51 {
+		t.Fatalf("entries = %d, want 51", len(entries))
+	}
+	final, _ := rp.FindHistory(ctx, 1)
+	last := final[len(final)-2].Vehicle.MaxSpeed
+	befores := make(map[float64]bool)
+	afters := map[float64]bool{0: true}
+	for _, e := range entries[:50] {
+		if e.Action != internal.AuditActionUpdateMaxSpeed || e.Actor != "alice" || e.Before == nil || e.After == nil {
+			t.Fatalf("entry = %+v", e)
+		}
+		if befores[e.Before.MaxSpeed] {
+			t.Fatalf("two updates from %v", e.Before.MaxSpeed)
+		}
+		befores[e.Before.MaxSpeed] = true
+		afters[e.After.MaxSpeed] = true
+	}
+	delete(afters, last)
+	for speed := range afters {
+		if !befores[speed] {
+			t.Fatalf("no update from %v", speed)
+		}
+	}
+
+	deleted := entries[50]
+	if deleted.Action != internal.AuditActionDelete || deleted.Before == nil || deleted.Before.MaxSpeed != last || deleted.Before.DeletedAt != nil || deleted.After != nil {
+		t.Fatalf("delete entry = %+v", deleted)
+	}
+}
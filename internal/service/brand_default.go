@@ -0,0 +1,168 @@
+package service
+
+import (
+	"app/internal"
+	"context"
+	"strings"
+)
+
+// NewBrandDefault is a function that returns a new instance of BrandDefault
+func NewBrandDefault(rp internal.BrandRepository, rpVehicle internal.VehicleRepository, svVehicle internal.VehicleService, nm internal.Normalizer, rf *References) *BrandDefault {
+	return &BrandDefault{rp: rp, rpVehicle: rpVehicle, svVehicle: svVehicle, nm: nm, rf: rf}
+}
+
+// BrandDefault is a struct that represents the default service for brands and models
+type BrandDefault struct {
+	// rp is the registry of brands and models
+	rp internal.BrandRepository
+	// rpVehicle is the repository of vehicles, used to find the vehicles linked to a brand or model
+	rpVehicle internal.VehicleRepository
+	// svVehicle is the service of vehicles, used to create vehicles from a model
+	svVehicle internal.VehicleService
+	// nm normalizes the names of the new brands and models (nil disables the normalization)
+	nm internal.Normalizer
+	// rf is held while checking the references to a brand or model and deleting it, shared with the vehicle writes
+	rf *References
+}
+
+// value is a method that returns the canonical value of an attribute
+func (s *BrandDefault) value(attribute, value string) string {
+	value = strings.TrimSpace(value)
+	if s.nm == nil || value == "" {
+		return value
+	}
+	return s.nm.Value(attribute, value)
+}
+
+// FindAll is a method that returns the brands sorted by name
+func (s *BrandDefault) FindAll(ctx context.Context) ([]internal.Brand, error) {
+	return s.rp.FindAll(ctx)
+}
+
+// FindOne is a method that returns a brand by its name
+func (s *BrandDefault) FindOne(ctx context.Context, name string) (internal.Brand, error) {
+	return s.rp.FindOne(ctx, s.value("brand", name))
+}
+
+// Create is a method that adds a brand
+func (s *BrandDefault) Create(ctx context.Context, brand internal.Brand) (internal.Brand, error) {
+	brand.Name = s.value("brand", brand.Name)
+	if brand.Name == "" {
+		return internal.Brand{}, internal.ErrInvalidName
+	}
+	if err := s.rp.Create(ctx, brand); err != nil {
+		return internal.Brand{}, err
+	}
+	return brand, nil
+}
+
+// Update is a method that replaces the manufacturer info of a brand
+func (s *BrandDefault) Update(ctx context.Context, brand internal.Brand) (internal.Brand, error) {
+	brand.Name = s.value("brand", brand.Name)
+	return s.rp.Update(ctx, brand)
+}
+
+// Delete is a method that removes a brand and its models unless a vehicle, even in the trash, still references it
+func (s *BrandDefault) Delete(ctx context.Context, name string) error {
+	release := s.rf.Remove()
+	defer release()
+
+	brand, err := s.rp.FindOne(ctx, s.value("brand", name))
+	if err != nil {
+		return err
+	}
+
+	vehicles, err := s.rpVehicle.FindWhere(internal.ContextWithIncludeDeleted(ctx), internal.VehicleFilter{Brand: brand.Name})
+	if err != nil {
+		return err
+	}
+	if len(vehicles) > 0 {
+		return internal.ErrBrandInUse
+	}
+
+	return s.rp.Delete(ctx, brand.Name)
+}
+
+// FindModels is a method that returns the models of a brand sorted by name
+func (s *BrandDefault) FindModels(ctx context.Context, brand string) ([]internal.VehicleModel, error) {
+	return s.rp.FindModels(ctx, s.value("brand", brand))
+}
+
+// FindModel is a method that returns a model of a brand by its name
+func (s *BrandDefault) FindModel(ctx context.Context, brand string, name string) (internal.VehicleModel, error) {
+	return s.rp.FindModel(ctx, s.value("brand", brand), s.value("model", name))
+}
+
+// CreateModel is a method that adds a model to its brand
+func (s *BrandDefault) CreateModel(ctx context.Context, model internal.VehicleModel) (internal.VehicleModel, error) {
+	model = s.model(model)
+	if model.Name == "" {
+		return internal.VehicleModel{}, internal.ErrInvalidName
+	}
+	if err := s.rp.CreateModel(ctx, model); err != nil {
+		return internal.VehicleModel{}, err
+	}
+	return s.rp.FindModel(ctx, model.Brand, model.Name)
+}
+
+// UpdateModel is a method that replaces the specs of a model
+func (s *BrandDefault) UpdateModel(ctx context.Context, model internal.VehicleModel) (internal.VehicleModel, error) {
+	return s.rp.UpdateModel(ctx, s.model(model))
+}
+
+// model is a method that returns a model with its names and default specs in canonical form
+func (s *BrandDefault) model(m internal.VehicleModel) internal.VehicleModel {
+	m.Brand = s.value("brand", m.Brand)
+	m.Name = s.value("model", m.Name)
+	m.FuelType = s.value("fuel_type", m.FuelType)
+	m.Transmission = s.value("transmission", m.Transmission)
+	return m
+}
+
+// DeleteModel is a method that removes a model unless a vehicle, even in the trash, still references it
+func (s *BrandDefault) DeleteModel(ctx context.Context, brand string, name string) error {
+	release := s.rf.Remove()
+	defer release()
+
+	model, err := s.FindModel(ctx, brand, name)
+	if err != nil {
+		return err
+	}
+
+	vehicles, err := s.rpVehicle.FindWhere(internal.ContextWithIncludeDeleted(ctx), internal.VehicleFilter{Brand: model.Brand, Model: model.Name})
+	if err != nil {
+		return err
+	}
+	if len(vehicles) > 0 {
+		return internal.ErrModelInUse
+	}
+
+	return s.rp.DeleteModel(ctx, model.Brand, model.Name)
+}
+
+// FindModelVehicles is a method that returns the vehicles linked to a model
+func (s *BrandDefault) FindModelVehicles(ctx context.Context, brand string, name string) ([]internal.Vehicle, error) {
+	model, err := s.FindModel(ctx, brand, name)
+	if err != nil {
+		return nil, err
+	}
+	return s.rpVehicle.FindWhere(ctx, internal.VehicleFilter{Brand: model.Brand, Model: model.Name})
+}
+
+// CreateVehicleFromModel is a method that creates a vehicle from the template of a model
+func (s *BrandDefault) CreateVehicleFromModel(ctx context.Context, brand string, name string, id int, patch internal.VehiclePatch) (internal.Vehicle, error) {
+	model, err := s.FindModel(ctx, brand, name)
+	if err != nil {
+		return internal.Vehicle{}, err
+	}
+
+	// the vehicle stays linked to the model
+	patch.Brand, patch.Model = nil, nil
+	vehicle := patch.Apply(model.Template())
+	vehicle.Id = id
+
+	if err := s.svVehicle.CreateVehicle(ctx, vehicle); err != nil {
+		return internal.Vehicle{}, err
+	}
+	return s.rpVehicle.FindOne(ctx, id)
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestBrandDefault_Delete(t *testing.T) {
+	cases := []struct {
+		name    string
+		brand   string
+		model   string
+		wantErr error
+	}{
+		{"unused brand", "Fiat", "", nil},
+		{"brand in use", "Ford", "", internal.ErrBrandInUse},
+		{"unused model", "Ford", "Fiesta", nil},
+		{"model in use", "ford", "focus", internal.ErrModelInUse},
+		{"unknown model", "Ford", "Mustang", internal.ErrModelNotFound},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx := context.Background()
+			rp := repository.NewVehicleMap(map[int]internal.Vehicle{1: testVehicle(1, "red")})
+			br := repository.NewBrandMap(
+				[]internal.Brand{{Name: "Ford"}, {Name: "Fiat"}},
+				[]internal.VehicleModel{{Brand: "Ford", Name: "Focus"}, {Brand: "Ford", Name: "Fiesta"}},
+			)
+			sv := NewBrandDefault(br, rp, nil, nil, nil)
+
+			var err error
+			if c.model == "" {
+				err = sv.Delete(ctx, c.brand)
+			} else {
+				err = sv.DeleteModel(ctx, c.brand, c.model)
+			}
+			if !errors.Is(err, c.wantErr) {
+				t.Fatalf("error = %v, want %v", err, c.wantErr)
+			}
+		})
+	}
+}
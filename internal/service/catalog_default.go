@@ -0,0 +1,88 @@
+package service
+
+import (
+	"app/internal"
+	"context"
+	"strings"
+)
+
+// NewCatalogDefault is a function that returns a new instance of CatalogDefault
+func NewCatalogDefault(rp internal.CatalogRepository, rpVehicle internal.VehicleRepository, nm internal.Normalizer, rf *References) *CatalogDefault {
+	return &CatalogDefault{rp: rp, rpVehicle: rpVehicle, nm: nm, rf: rf}
+}
+
+// CatalogDefault is a struct that represents the default service for catalogs
+type CatalogDefault struct {
+	// rp is the repository of catalogs
+	rp internal.CatalogRepository
+	// rpVehicle is the repository of vehicles, used to check the references to an entry
+	rpVehicle internal.VehicleRepository
+	// nm normalizes the values of the new entries (nil disables the normalization)
+	nm internal.Normalizer
+	// rf is held while checking the references to an entry and deleting it, shared with the vehicle writes
+	rf *References
+}
+
+// FindAll is a method that returns the entries of a catalog sorted by value
+func (s *CatalogDefault) FindAll(ctx context.Context, kind internal.CatalogKind) ([]internal.CatalogEntry, error) {
+	return s.rp.FindAll(ctx, kind)
+}
+
+// FindOne is a method that returns an entry of a catalog by its value
+func (s *CatalogDefault) FindOne(ctx context.Context, kind internal.CatalogKind, value string) (internal.CatalogEntry, error) {
+	return s.rp.FindOne(ctx, kind, value)
+}
+
+// Create is a method that adds an entry to a catalog, its value in canonical form
+func (s *CatalogDefault) Create(ctx context.Context, kind internal.CatalogKind, entry internal.CatalogEntry) (internal.CatalogEntry, error) {
+	if !kind.Valid() {
+		return internal.CatalogEntry{}, internal.ErrCatalogNotFound
+	}
+	entry.Value = strings.TrimSpace(entry.Value)
+	if entry.Value == "" {
+		return internal.CatalogEntry{}, internal.ErrCatalogEntryInvalid
+	}
+	if s.nm != nil {
+		entry.Value = s.nm.Value(kind.Attribute(), entry.Value)
+	}
+
+	if err := s.rp.Create(ctx, kind, entry); err != nil {
+		return internal.CatalogEntry{}, err
+	}
+	return entry, nil
+}
+
+// Update is a method that replaces the description of an entry
+func (s *CatalogDefault) Update(ctx context.Context, kind internal.CatalogKind, value string, description string) (internal.CatalogEntry, error) {
+	return s.rp.Update(ctx, kind, value, description)
+}
+
+// Delete is a method that removes an entry from a catalog unless a vehicle, even in the trash, still references it
+func (s *CatalogDefault) Delete(ctx context.Context, kind internal.CatalogKind, value string) error {
+	release := s.rf.Remove()
+	defer release()
+
+	entry, err := s.rp.FindOne(ctx, kind, value)
+	if err != nil {
+		return err
+	}
+
+	var filter internal.VehicleFilter
+	switch kind {
+	case internal.CatalogFuelTypes:
+		filter.FuelType = entry.Value
+	case internal.CatalogTransmissions:
+		filter.Transmission = entry.Value
+	case internal.CatalogColors:
+		filter.Color = entry.Value
+	}
+	vehicles, err := s.rpVehicle.FindWhere(internal.ContextWithIncludeDeleted(ctx), filter)
+	if err != nil {
+		return err
+	}
+	if len(vehicles) > 0 {
+		return internal.ErrCatalogEntryInUse
+	}
+
+	return s.rp.Delete(ctx, kind, value)
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testCatalogs returns the catalogs of the test vehicles
+func testCatalogs() *repository.CatalogMap {
+	return repository.NewCatalogMap(map[internal.CatalogKind][]internal.CatalogEntry{
+		internal.CatalogFuelTypes:     {{Value: "gas"}},
+		internal.CatalogTransmissions: {{Value: "manual"}},
+		internal.CatalogColors:        {{Value: "red"}, {Value: "blue"}},
+	})
+}
+
+// testVehicle returns a vehicle whose attributes belong to testCatalogs
+func testVehicle(id int, color string) internal.Vehicle {
+	return internal.Vehicle{Id: id, VehicleAttributes: internal.VehicleAttributes{
+		Brand: "Ford", Model: "Focus", Color: color, FuelType: "gas", Transmission: "manual", FabricationYear: 2020,
+	}}
+}
+
+func TestCatalogDefault_Delete(t *testing.T) {
+	cases := []struct {
+		name    string
+		color   string
+		trashed bool
+		wantErr error
+	}{
+		{"unused", "blue", false, nil},
+		{"in use", "red", false, internal.ErrCatalogEntryInUse},
+		{"in use in the trash", "red", true, internal.ErrCatalogEntryInUse},
+		{"unknown", "green", false, internal.ErrCatalogEntryNotFound},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx := context.Background()
+			rp := repository.NewVehicleMap(map[int]internal.Vehicle{1: testVehicle(1, "red")})
+			if c.trashed {
+				rp.Delete(ctx, 1)
+			}
+			sv := NewCatalogDefault(testCatalogs(), rp, nil, nil)
+
+			if err := sv.Delete(ctx, internal.CatalogColors, c.color); !errors.Is(err, c.wantErr) {
+				t.Fatalf("Delete() error = %v, want %v", err, c.wantErr)
+			}
+		})
+	}
+}
+
+// slowCatalog is a CatalogRepository that signals when a color was checked and waits a little,
+// so that a deletion can run between the check of a vehicle and its write
+type slowCatalog struct {
+	*repository.CatalogMap
+	checked chan struct{}
+}
+
+// FindOne is a method that finds an entry, signals the check of a color and waits
+func (r *slowCatalog) FindOne(ctx context.Context, kind internal.CatalogKind, value string) (internal.CatalogEntry, error) {
+	entry, err := r.CatalogMap.FindOne(ctx, kind, value)
+	if kind == internal.CatalogColors {
+		close(r.checked)
+		time.Sleep(5 * time.Millisecond)
+	}
+	return entry, err
+}
+
+func TestCatalogDefault_DeleteWhileCreating(t *testing.T) {
+	ctx := context.Background()
+	ct := &slowCatalog{CatalogMap: testCatalogs()}
+	rp := repository.NewVehicleMap(nil)
+	rf := NewReferences()
+	svVehicle := NewVehicleDefault(rp, nil, nil, ct, nil, nil, nil, rf)
+	svCatalog := NewCatalogDefault(ct.CatalogMap, rp, nil, rf)
+
+	ct.checked = make(chan struct{})
+	var createErr, deleteErr error
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() { defer wg.Done(); createErr = svVehicle.CreateVehicle(ctx, testVehicle(1, "blue")) }()
+	go func() {
+		defer wg.Done()
+		<-ct.checked
+		deleteErr = svCatalog.Delete(ctx, internal.CatalogColors, "blue")
+	}()
+	wg.Wait()
+
+	// the entry checked by the vehicle can not be deleted before the vehicle is written
+	if createErr != nil || !errors.Is(deleteErr, internal.ErrCatalogEntryInUse) {
+		t.Fatalf("create error %v, delete error %v", createErr, deleteErr)
+	}
+}
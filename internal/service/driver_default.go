@@ -0,0 +1,20 @@
+package service
+
+import "app/internal"
+
+// NewDriverDefault is a function that returns a new instance of DriverDefault
+func NewDriverDefault(rp internal.DriverRepository, rpAssignment internal.AssignmentRepository, rf *References) *DriverDefault {
+	return &DriverDefault{&personDefault[internal.Driver]{
+		rp:           rp,
+		rpAssignment: rpAssignment,
+		rf:           rf,
+		role:         internal.AssignmentDriver,
+		name:         func(d *internal.Driver) *string { return &d.Name },
+		errInUse:     internal.ErrDriverInUse,
+	}}
+}
+
+// DriverDefault is a struct that represents the default service for drivers
+type DriverDefault struct {
+	*personDefault[internal.Driver]
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"app/internal"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// NewMaintenanceDefault is a function that returns a new instance of MaintenanceDefault
+func NewMaintenanceDefault(rp internal.MaintenanceRepository, rpVehicle internal.VehicleRepository, rpOdometer internal.OdometerRepository) *MaintenanceDefault {
+	return &MaintenanceDefault{rp: rp, rpVehicle: rpVehicle, rpOdometer: rpOdometer}
+}
+
+// MaintenanceDefault is a struct that represents the default service for maintenance
+type MaintenanceDefault struct {
+	// rp is the repository of maintenance records and schedules
+	rp internal.MaintenanceRepository
+	// rpVehicle is the repository of vehicles, used to check the vehicles exist
+	rpVehicle internal.VehicleRepository
+	// rpOdometer is the repository of odometer readings, the latest one is the current odometer
+	rpOdometer internal.OdometerRepository
+}
+
+// odometer is a method that returns the last known odometer of a vehicle: the most recent of its readings,
+// its records and the start of its schedules, so that a replaced odometer is taken into account
+func (s *MaintenanceDefault) odometer(ctx context.Context, vehicleID int, records []internal.MaintenanceRecord, schedules []internal.MaintenanceSchedule) (float64, error) {
+	readings, err := s.rpOdometer.FindReadings(ctx, vehicleID)
+	if err != nil {
+		return 0, err
+	}
+
+	var odometer float64
+	var date time.Time
+	known := func(d time.Time, value float64) {
+		if d.After(date) || (d.Equal(date) && value > odometer) {
+			odometer, date = value, d
+		}
+	}
+	for _, r := range readings {
+		known(r.Date, r.Value)
+	}
+	for _, r := range records {
+		known(r.Date, r.Odometer)
+	}
+	for _, sc := range schedules {
+		known(sc.Since, sc.SinceOdometer)
+	}
+	return odometer, nil
+}
+
+// due is a method that returns when the services of the schedules of a vehicle are due
+func (s *MaintenanceDefault) due(ctx context.Context, vehicleID int, records []internal.MaintenanceRecord, schedules []internal.MaintenanceSchedule, now time.Time) ([]internal.MaintenanceDue, error) {
+	odometer, err := s.odometer(ctx, vehicleID, records, schedules)
+	if err != nil {
+		return nil, err
+	}
+
+	due := make([]internal.MaintenanceDue, len(schedules))
+	for i, sc := range schedules {
+		// the records are sorted oldest first
+		var last *internal.MaintenanceRecord
+		for j := range records {
+			if internal.NormalizeKey(records[j].Type) == internal.NormalizeKey(sc.Type) {
+				last = &records[j]
+			}
+		}
+		due[i] = sc.NextDue(last, odometer, now)
+	}
+	return due, nil
+}
+
+// FindByVehicle is a method that returns the records of a vehicle and when the services of its schedules are due
+func (s *MaintenanceDefault) FindByVehicle(ctx context.Context, vehicleID int) ([]internal.MaintenanceRecord, []internal.MaintenanceDue, error) {
+	if _, err := s.rpVehicle.FindOne(ctx, vehicleID); err != nil {
+		return nil, nil, err
+	}
+	records, err := s.rp.FindRecords(ctx, vehicleID)
+	if err != nil {
+		return nil, nil, err
+	}
+	schedules, err := s.rp.FindSchedules(ctx, vehicleID)
+	if err != nil {
+		return nil, nil, err
+	}
+	due, err := s.due(ctx, vehicleID, records, schedules, time.Now().UTC())
+	if err != nil {
+		return nil, nil, err
+	}
+	return records, due, nil
+}
+
+// CreateRecord is a method that records a service of a vehicle, at the moment of the request when no date is given
+func (s *MaintenanceDefault) CreateRecord(ctx context.Context, record internal.MaintenanceRecord) (internal.MaintenanceRecord, error) {
+	record.Type = strings.TrimSpace(record.Type)
+	switch {
+	case record.Type == "":
+		return internal.MaintenanceRecord{}, fmt.Errorf("%w: type must not be empty", internal.ErrInvalidMaintenance)
+	case record.Odometer < 0 || record.Cost < 0:
+		return internal.MaintenanceRecord{}, fmt.Errorf("%w: odometer and cost must not be negative", internal.ErrInvalidMaintenance)
+	}
+	if _, err := s.rpVehicle.FindOne(ctx, record.VehicleID); err != nil {
+		return internal.MaintenanceRecord{}, err
+	}
+
+	if record.Date.IsZero() {
+		record.Date = time.Now().UTC()
+	}
+	return s.rp.CreateRecord(ctx, record)
+}
+
+// DeleteRecord is a method that removes a record of a vehicle
+func (s *MaintenanceDefault) DeleteRecord(ctx context.Context, vehicleID int, id int) error {
+	records, err := s.rp.FindRecords(ctx, vehicleID)
+	if err != nil {
+		return err
+	}
+	for _, r := range records {
+		if r.Id == id {
+			return s.rp.DeleteRecord(ctx, id)
+		}
+	}
+	return internal.ErrMaintenanceRecordNotFound
+}
+
+// CreateSchedule is a method that adds a recurring service to a vehicle. The first service is counted
+// from the moment of the request and the last known odometer unless others are given
+func (s *MaintenanceDefault) CreateSchedule(ctx context.Context, schedule internal.MaintenanceSchedule) (internal.MaintenanceSchedule, error) {
+	schedule.Type = strings.TrimSpace(schedule.Type)
+	switch {
+	case schedule.Type == "":
+		return internal.MaintenanceSchedule{}, fmt.Errorf("%w: type must not be empty", internal.ErrInvalidMaintenance)
+	case schedule.IntervalDays < 0 || schedule.IntervalDistance < 0 || schedule.SinceOdometer < 0:
+		return internal.MaintenanceSchedule{}, fmt.Errorf("%w: intervals and odometer must not be negative", internal.ErrInvalidMaintenance)
+	case schedule.IntervalDays == 0 && schedule.IntervalDistance == 0:
+		return internal.MaintenanceSchedule{}, fmt.Errorf("%w: an interval of days or distance is required", internal.ErrInvalidMaintenance)
+	}
+	if _, err := s.rpVehicle.FindOne(ctx, schedule.VehicleID); err != nil {
+		return internal.MaintenanceSchedule{}, err
+	}
+
+	if schedule.Since.IsZero() {
+		schedule.Since = time.Now().UTC()
+	}
+	if schedule.SinceOdometer == 0 {
+		records, err := s.rp.FindRecords(ctx, schedule.VehicleID)
+		if err != nil {
+			return internal.MaintenanceSchedule{}, err
+		}
+		if schedule.SinceOdometer, err = s.odometer(ctx, schedule.VehicleID, records, nil); err != nil {
+			return internal.MaintenanceSchedule{}, err
+		}
+	}
+	return s.rp.CreateSchedule(ctx, schedule)
+}
+
+// DeleteSchedule is a method that removes a recurring service of a vehicle
+func (s *MaintenanceDefault) DeleteSchedule(ctx context.Context, vehicleID int, id int) error {
+	schedules, err := s.rp.FindSchedules(ctx, vehicleID)
+	if err != nil {
+		return err
+	}
+	for _, sc := range schedules {
+		if sc.Id == id {
+			return s.rp.DeleteSchedule(ctx, id)
+		}
+	}
+	return internal.ErrMaintenanceScheduleNotFound
+}
+
+// FindDue is a method that returns the services overdue or due within the given time or distance
+// of the vehicles out of the trash, overdue ones and earliest dates first
+func (s *MaintenanceDefault) FindDue(ctx context.Context, window time.Duration, distance float64) ([]internal.MaintenanceDue, error) {
+	schedules, err := s.rp.FindSchedules(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	byVehicle := make(map[int][]internal.MaintenanceSchedule)
+	for _, sc := range schedules {
+		byVehicle[sc.VehicleID] = append(byVehicle[sc.VehicleID], sc)
+	}
+
+	now := time.Now().UTC()
+	due := make([]internal.MaintenanceDue, 0)
+	for vehicleID, schedules := range byVehicle {
+		if _, err := s.rpVehicle.FindOne(ctx, vehicleID); err != nil {
+			if errors.Is(err, internal.ErrVehicleNotFounded) {
+				continue
+			}
+			return nil, err
+		}
+		records, err := s.rp.FindRecords(ctx, vehicleID)
+		if err != nil {
+			return nil, err
+		}
+		vehicleDue, err := s.due(ctx, vehicleID, records, schedules, now)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range vehicleDue {
+			if d.Within(now, window, distance) {
+				due = append(due, d)
+			}
+		}
+	}
+
+	sort.Slice(due, func(i, j int) bool {
+		a, b := due[i], due[j]
+		switch {
+		case a.Overdue != b.Overdue:
+			return a.Overdue
+		case a.Date != nil && b.Date != nil && !a.Date.Equal(*b.Date):
+			return a.Date.Before(*b.Date)
+		case (a.Date == nil) != (b.Date == nil):
+			return a.Date != nil
+		}
+		return a.Schedule.Id < b.Schedule.Id
+	})
+	return due, nil
+}
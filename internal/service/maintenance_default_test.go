@@ -0,0 +1,64 @@
+package service
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMaintenanceDefault_FindDue(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	days := func(d int) time.Time { return now.AddDate(0, 0, d) }
+
+	rpVehicle := repository.NewVehicleMap(map[int]internal.Vehicle{1: testVehicle(1, "red"), 2: testVehicle(2, "red"), 3: testVehicle(3, "red")})
+	rpVehicle.Delete(ctx, 3)
+	rp := repository.NewMaintenanceMap()
+	rpOdometer := repository.NewOdometerMap()
+	for _, sc := range []internal.MaintenanceSchedule{
+		// overdue by date, the service of another type does not count
+		{VehicleID: 1, Type: "oil", IntervalDays: 20, Since: days(-30)},
+		// serviced 5 days ago, due in 15 days
+		{VehicleID: 1, Type: "filter", IntervalDays: 20, Since: days(-30)},
+		// due within the distance
+		{VehicleID: 2, Type: "tires", IntervalDistance: 1000, Since: days(-30)},
+		// due in 3 days
+		{VehicleID: 2, Type: "inspection", IntervalDays: 33, Since: days(-30)},
+		// the vehicle is in the trash
+		{VehicleID: 3, Type: "oil", IntervalDays: 20, Since: days(-30)},
+	} {
+		rp.CreateSchedule(ctx, sc)
+	}
+	rp.CreateRecord(ctx, internal.MaintenanceRecord{VehicleID: 1, Type: "brakes", Date: days(-5)})
+	rp.CreateRecord(ctx, internal.MaintenanceRecord{VehicleID: 1, Type: " FILTER", Date: days(-5)})
+	rpOdometer.Create(ctx, internal.OdometerReading{VehicleID: 2, Date: days(-1), Value: 950})
+	sv := NewMaintenanceDefault(rp, rpVehicle, rpOdometer)
+
+	due, err := sv.FindDue(ctx, 7*24*time.Hour, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// overdue first, then the earliest dates, then the ones without date
+	want := []struct {
+		vehicleID int
+		typ       string
+		overdue   bool
+	}{
+		{1, "oil", true},
+		{2, "inspection", false},
+		{2, "tires", false},
+	}
+	if len(due) != len(want) {
+		t.Fatalf("FindDue() = %+v, want %d services", due, len(want))
+	}
+	for i, w := range want {
+		if d := due[i]; d.Schedule.VehicleID != w.vehicleID || d.Schedule.Type != w.typ || d.Overdue != w.overdue {
+			t.Fatalf("FindDue()[%d] = %+v, want %+v", i, d, w)
+		}
+	}
+	if tires := due[2]; tires.CurrentOdometer != 950 || *tires.Odometer != 1000 {
+		t.Fatalf("tires due at %v with odometer %v, want 1000 with 950", *tires.Odometer, tires.CurrentOdometer)
+	}
+}
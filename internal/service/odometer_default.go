@@ -0,0 +1,90 @@
+package service
+
+import (
+	"app/internal"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// NewOdometerDefault is a function that returns a new instance of OdometerDefault
+func NewOdometerDefault(rp internal.OdometerRepository, rpVehicle internal.VehicleRepository, svMaintenance internal.MaintenanceService) *OdometerDefault {
+	return &OdometerDefault{rp: rp, rpVehicle: rpVehicle, svMaintenance: svMaintenance}
+}
+
+// OdometerDefault is a struct that represents the default service for odometer readings
+type OdometerDefault struct {
+	// rp is the repository of odometer readings
+	rp internal.OdometerRepository
+	// rpVehicle is the repository of vehicles, used to check the vehicles exist
+	rpVehicle internal.VehicleRepository
+	// svMaintenance is the service of maintenance, used to project the next service
+	svMaintenance internal.MaintenanceService
+}
+
+// FindReadings is a method that returns the readings of a vehicle between two moments (zero means unbounded), oldest first
+func (s *OdometerDefault) FindReadings(ctx context.Context, vehicleID int, from, to time.Time) ([]internal.OdometerReading, error) {
+	if _, err := s.rpVehicle.FindOne(ctx, vehicleID); err != nil {
+		return nil, err
+	}
+	readings, err := s.rp.FindReadings(ctx, vehicleID)
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]internal.OdometerReading, 0, len(readings))
+	for _, reading := range readings {
+		if (!from.IsZero() && reading.Date.Before(from)) || (!to.IsZero() && reading.Date.After(to)) {
+			continue
+		}
+		r = append(r, reading)
+	}
+	return r, nil
+}
+
+// Create is a method that adds a reading of a vehicle, at the moment of the request when no date is given
+func (s *OdometerDefault) Create(ctx context.Context, reading internal.OdometerReading) (internal.OdometerReading, error) {
+	now := time.Now().UTC()
+	if reading.Date.IsZero() {
+		reading.Date = now
+	}
+	switch {
+	case reading.Value < 0:
+		return internal.OdometerReading{}, fmt.Errorf("%w: value must not be negative", internal.ErrInvalidOdometerReading)
+	case reading.Date.After(now):
+		return internal.OdometerReading{}, fmt.Errorf("%w: date must not be in the future", internal.ErrInvalidOdometerReading)
+	}
+	if _, err := s.rpVehicle.FindOne(ctx, reading.VehicleID); err != nil {
+		return internal.OdometerReading{}, err
+	}
+
+	reading.Notes = strings.TrimSpace(reading.Notes)
+	return s.rp.Create(ctx, reading)
+}
+
+// Usage is a method that returns the usage of a vehicle between two moments (zero means unbounded) and its next service,
+// projected from the last reading at the average daily distance
+func (s *OdometerDefault) Usage(ctx context.Context, vehicleID int, from, to time.Time) (internal.OdometerUsage, error) {
+	readings, err := s.FindReadings(ctx, vehicleID, from, to)
+	if err != nil {
+		return internal.OdometerUsage{}, err
+	}
+	usage := internal.Usage(readings)
+
+	_, due, err := s.svMaintenance.FindByVehicle(ctx, vehicleID)
+	if err != nil {
+		return internal.OdometerUsage{}, err
+	}
+	since := time.Now().UTC()
+	if usage.Last != nil {
+		since = usage.Last.Date
+	}
+	for i, d := range due {
+		date := d.Projected(since, usage.AverageDailyDistance)
+		if date != nil && (usage.NextServiceDate == nil || date.Before(*usage.NextServiceDate)) {
+			usage.NextService, usage.NextServiceDate = &due[i], date
+		}
+	}
+	return usage, nil
+}
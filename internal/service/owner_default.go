@@ -0,0 +1,20 @@
+package service
+
+import "app/internal"
+
+// NewOwnerDefault is a function that returns a new instance of OwnerDefault
+func NewOwnerDefault(rp internal.OwnerRepository, rpAssignment internal.AssignmentRepository, rf *References) *OwnerDefault {
+	return &OwnerDefault{&personDefault[internal.Owner]{
+		rp:           rp,
+		rpAssignment: rpAssignment,
+		rf:           rf,
+		role:         internal.AssignmentOwner,
+		name:         func(o *internal.Owner) *string { return &o.Name },
+		errInUse:     internal.ErrOwnerInUse,
+	}}
+}
+
+// OwnerDefault is a struct that represents the default service for owners
+type OwnerDefault struct {
+	*personDefault[internal.Owner]
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"app/internal"
+	"context"
+	"strings"
+	"time"
+)
+
+// personRepository is an interface that represents a repository of people, met by the owner and driver ones
+type personRepository[T any] interface {
+	FindAll(ctx context.Context) (p []T, err error)
+	FindOne(ctx context.Context, id int) (p T, err error)
+	Create(ctx context.Context, person T) (p T, err error)
+	Update(ctx context.Context, person T) (p T, err error)
+	Delete(ctx context.Context, id int) (err error)
+}
+
+// personDefault is a struct that represents the default service for people, shared by owners and drivers
+type personDefault[T any] struct {
+	// rp is the repository of people
+	rp personRepository[T]
+	// rpAssignment is the repository of assignments, used to find the vehicles of a person
+	rpAssignment internal.AssignmentRepository
+	// rf serializes the deletions with the assignments referring to the people
+	rf *References
+	// role is the role of the people in the assignments
+	role internal.AssignmentRole
+	// name returns the name field of a person
+	name func(p *T) *string
+	// errInUse is the error returned when a person with current or upcoming vehicles is deleted
+	errInUse error
+}
+
+// FindAll is a method that returns the people sorted by id
+func (s *personDefault[T]) FindAll(ctx context.Context) ([]T, error) {
+	return s.rp.FindAll(ctx)
+}
+
+// FindOne is a method that returns a person by its id
+func (s *personDefault[T]) FindOne(ctx context.Context, id int) (T, error) {
+	return s.rp.FindOne(ctx, id)
+}
+
+// Create is a method that adds a person
+func (s *personDefault[T]) Create(ctx context.Context, person T) (p T, err error) {
+	if err = s.validate(&person); err != nil {
+		return
+	}
+	return s.rp.Create(ctx, person)
+}
+
+// Update is a method that replaces the data of a person
+func (s *personDefault[T]) Update(ctx context.Context, person T) (p T, err error) {
+	if err = s.validate(&person); err != nil {
+		return
+	}
+	return s.rp.Update(ctx, person)
+}
+
+// Delete is a method that removes a person unless it has current or upcoming vehicles,
+// the past assignments are kept as history
+func (s *personDefault[T]) Delete(ctx context.Context, id int) error {
+	// no assignment can be made between the check and the deletion
+	release := s.rf.Remove()
+	defer release()
+
+	assignments, err := s.rpAssignment.FindByPerson(ctx, s.role, id)
+	if err != nil {
+		return err
+	}
+	if pending(assignments, time.Now().UTC()) {
+		return s.errInUse
+	}
+	return s.rp.Delete(ctx, id)
+}
+
+// validate is a method that trims the name of a person and requires it
+func (s *personDefault[T]) validate(person *T) error {
+	name := s.name(person)
+	*name = strings.TrimSpace(*name)
+	if *name == "" {
+		return internal.ErrInvalidName
+	}
+	return nil
+}
+
+// pending returns true when some of the assignments is in effect or starts after the given moment
+func pending(assignments []internal.Assignment, now time.Time) bool {
+	for _, a := range assignments {
+		if a.To == nil || a.To.After(now) {
+			return true
+		}
+	}
+	return false
+}
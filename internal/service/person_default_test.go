@@ -0,0 +1,115 @@
+package service
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestOwnerDefault_Delete(t *testing.T) {
+	now := time.Now().UTC()
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+
+	cases := []struct {
+		name string
+		// assignment is assigned to the owner 1 before the deletion, when set
+		assignment *internal.Assignment
+		id         int
+		wantErr    error
+	}{
+		{"unassigned", nil, 1, nil},
+		{"past vehicle", &internal.Assignment{From: now.Add(-2 * time.Hour), To: &past}, 1, nil},
+		{"current vehicle", &internal.Assignment{From: now.Add(-2 * time.Hour)}, 1, internal.ErrOwnerInUse},
+		{"upcoming vehicle", &internal.Assignment{From: future}, 1, internal.ErrOwnerInUse},
+		{"unknown", nil, 2, internal.ErrOwnerNotFound},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx := context.Background()
+			rpAssignment := repository.NewAssignmentMap()
+			sv := NewOwnerDefault(repository.NewOwnerMap(map[int]internal.Owner{1: {Id: 1, Name: "Ann"}}), rpAssignment, nil)
+			if c.assignment != nil {
+				c.assignment.Role, c.assignment.VehicleID, c.assignment.PersonID = internal.AssignmentOwner, 1, 1
+				if _, _, err := rpAssignment.Create(ctx, *c.assignment); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := sv.Delete(ctx, c.id); !errors.Is(err, c.wantErr) {
+				t.Fatalf("Delete() error = %v, want %v", err, c.wantErr)
+			}
+		})
+	}
+}
+
+func TestDriverDefault_Create(t *testing.T) {
+	cases := []struct {
+		name     string
+		driver   internal.Driver
+		wantName string
+		wantErr  error
+	}{
+		{"trimmed name", internal.Driver{Name: "  Bob ", LicenseNumber: "B-1"}, "Bob", nil},
+		{"blank name", internal.Driver{Name: "  "}, "", internal.ErrInvalidName},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			sv := NewDriverDefault(repository.NewDriverMap(nil), repository.NewAssignmentMap(), nil)
+
+			d, err := sv.Create(context.Background(), c.driver)
+			if !errors.Is(err, c.wantErr) {
+				t.Fatalf("Create() error = %v, want %v", err, c.wantErr)
+			}
+			if d.Name != c.wantName || (err == nil && (d.Id != 1 || d.LicenseNumber != c.driver.LicenseNumber)) {
+				t.Fatalf("Create() = %+v", d)
+			}
+		})
+	}
+}
+
+// slowDrivers is a DriverRepository that signals when a driver was checked and waits a little,
+// so that a deletion can run between the check of an assignment and its write
+type slowDrivers struct {
+	*repository.DriverMap
+	checked chan struct{}
+}
+
+// FindOne is a method that finds a driver, signals the check and waits
+func (r *slowDrivers) FindOne(ctx context.Context, id int) (internal.Driver, error) {
+	d, err := r.DriverMap.FindOne(ctx, id)
+	close(r.checked)
+	time.Sleep(5 * time.Millisecond)
+	return d, err
+}
+
+func TestDriverDefault_DeleteWhileAssigning(t *testing.T) {
+	ctx := context.Background()
+	rpDriver := &slowDrivers{DriverMap: repository.NewDriverMap(map[int]internal.Driver{1: {Id: 1, Name: "Bob"}}), checked: make(chan struct{})}
+	rpAssignment := repository.NewAssignmentMap()
+	rf := NewReferences()
+	svAssignment := NewAssignmentDefault(rpAssignment, repository.NewVehicleMap(map[int]internal.Vehicle{1: testVehicle(1, "red")}), nil, rpDriver, rf)
+	svDriver := NewDriverDefault(rpDriver.DriverMap, rpAssignment, rf)
+
+	var assignErr, deleteErr error
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		_, _, assignErr = svAssignment.Assign(ctx, internal.Assignment{VehicleID: 1, Role: internal.AssignmentDriver, PersonID: 1})
+	}()
+	go func() {
+		defer wg.Done()
+		<-rpDriver.checked
+		deleteErr = svDriver.Delete(ctx, 1)
+	}()
+	wg.Wait()
+
+	// the driver checked by the assignment can not be deleted before the assignment is written
+	if assignErr != nil || !errors.Is(deleteErr, internal.ErrDriverInUse) {
+		t.Fatalf("assign error %v, delete error %v", assignErr, deleteErr)
+	}
+}
@@ -0,0 +1,37 @@
+package service
+
+import "sync"
+
+// NewReferences is a function that returns a new instance of References
+func NewReferences() *References {
+	return &References{}
+}
+
+// References is a struct that serializes the writes checking the entries they refer to with the deletion of those,
+// such as the vehicle writes with the catalog entries, brands and models, or the assignments with the owners and drivers,
+// so that an entry can not be removed between the check of a write and the write, nor a write made between the check
+// that nothing refers to an entry and its deletion. A nil References does not lock
+type References struct {
+	// mu is held shared by the writes and exclusively by the deletions
+	mu sync.RWMutex
+}
+
+// Refer is a method that holds the references for a write, several writes may hold them at once.
+// The returned function releases them
+func (r *References) Refer() (release func()) {
+	if r == nil {
+		return func() {}
+	}
+	r.mu.RLock()
+	return r.mu.RUnlock
+}
+
+// Remove is a method that holds the references for a deletion, alone.
+// The returned function releases them
+func (r *References) Remove() (release func()) {
+	if r == nil {
+		return func() {}
+	}
+	r.mu.Lock()
+	return r.mu.Unlock
+}
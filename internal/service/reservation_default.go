@@ -0,0 +1,123 @@
+package service
+
+import (
+	"app/internal"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// NewReservationDefault is a function that returns a new instance of ReservationDefault
+func NewReservationDefault(rp internal.ReservationRepository, rpVehicle internal.VehicleRepository, svVehicle internal.VehicleService) *ReservationDefault {
+	return &ReservationDefault{rp: rp, rpVehicle: rpVehicle, svVehicle: svVehicle}
+}
+
+// ReservationDefault is a struct that represents the default service for reservations
+type ReservationDefault struct {
+	// rp is the repository of reservations
+	rp internal.ReservationRepository
+	// rpVehicle is the repository of vehicles, used to check the vehicles exist
+	rpVehicle internal.VehicleRepository
+	// svVehicle is the service of vehicles, used to search the available ones
+	svVehicle internal.VehicleService
+}
+
+// FindByVehicle is a method that returns the reservations of a vehicle overlapping a time range (zero means unbounded),
+// cancelled ones included, earliest first
+func (s *ReservationDefault) FindByVehicle(ctx context.Context, vehicleID int, from, to time.Time) ([]internal.Reservation, error) {
+	if _, err := s.rpVehicle.FindOne(ctx, vehicleID); err != nil {
+		return nil, err
+	}
+	reservations, err := s.rp.FindByVehicle(ctx, vehicleID)
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]internal.Reservation, 0, len(reservations))
+	for _, reservation := range reservations {
+		if (!from.IsZero() && !reservation.To.After(from)) || (!to.IsZero() && !reservation.From.Before(to)) {
+			continue
+		}
+		r = append(r, reservation)
+	}
+	return r, nil
+}
+
+// Create is a method that reserves a vehicle for the caller unless someone else is given
+func (s *ReservationDefault) Create(ctx context.Context, reservation internal.Reservation) (internal.Reservation, error) {
+	now := time.Now().UTC()
+	reservation.ReservedBy = strings.TrimSpace(reservation.ReservedBy)
+	if p, ok := internal.PrincipalFromContext(ctx); ok && reservation.ReservedBy == "" {
+		reservation.ReservedBy = p.Subject
+	}
+	switch {
+	case reservation.From.IsZero() || reservation.To.IsZero():
+		return internal.Reservation{}, fmt.Errorf("%w: from and to are required", internal.ErrInvalidReservation)
+	case !reservation.To.After(reservation.From):
+		return internal.Reservation{}, fmt.Errorf("%w: it must end after it starts", internal.ErrInvalidReservation)
+	case !reservation.To.After(now):
+		return internal.Reservation{}, fmt.Errorf("%w: it must not end in the past", internal.ErrInvalidReservation)
+	case reservation.ReservedBy == "":
+		return internal.Reservation{}, fmt.Errorf("%w: who it is reserved for is required", internal.ErrInvalidReservation)
+	case reservation.Passengers < 0:
+		return internal.Reservation{}, fmt.Errorf("%w: passengers must not be negative", internal.ErrInvalidReservation)
+	}
+
+	vehicle, err := s.rpVehicle.FindOne(ctx, reservation.VehicleID)
+	if err != nil {
+		return internal.Reservation{}, err
+	}
+	if !vehicle.Status.Operational() {
+		return internal.Reservation{}, fmt.Errorf("%w: the vehicle is %s", internal.ErrInvalidReservation, vehicle.Status)
+	}
+	if reservation.Passengers > vehicle.Capacity {
+		return internal.Reservation{}, fmt.Errorf("%w: the vehicle carries %d passengers", internal.ErrInvalidReservation, vehicle.Capacity)
+	}
+
+	reservation.CreatedAt = now
+	reservation.CancelledAt = nil
+	return s.rp.Create(ctx, reservation)
+}
+
+// Cancel is a method that cancels a reservation of a vehicle, the reservation is kept in its calendar
+func (s *ReservationDefault) Cancel(ctx context.Context, vehicleID int, id int) (internal.Reservation, error) {
+	reservations, err := s.rp.FindByVehicle(ctx, vehicleID)
+	if err != nil {
+		return internal.Reservation{}, err
+	}
+	for _, r := range reservations {
+		if r.Id == id {
+			return s.rp.Cancel(ctx, id, time.Now().UTC())
+		}
+	}
+	return internal.Reservation{}, internal.ErrReservationNotFound
+}
+
+// Available is a method that returns the vehicles in service matching the filter without reservations in a time range, sorted by id
+func (s *ReservationDefault) Available(ctx context.Context, filter internal.VehicleFilter, from, to time.Time) ([]internal.Vehicle, error) {
+	if !to.After(from) {
+		return nil, fmt.Errorf("%w: the time range must end after it starts", internal.ErrInvalidReservation)
+	}
+	vehicles, err := s.svVehicle.FindWhere(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	reservations, err := s.rp.FindOverlapping(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	reserved := make(map[int]bool, len(reservations))
+	for _, r := range reservations {
+		reserved[r.VehicleID] = true
+	}
+	v := make([]internal.Vehicle, 0, len(vehicles))
+	for _, vehicle := range vehicles {
+		// the vehicles in the trash or out of service can not be reserved
+		if !reserved[vehicle.Id] && vehicle.DeletedAt == nil && vehicle.Status == internal.VehicleStatusInService {
+			v = append(v, vehicle)
+		}
+	}
+	return v, nil
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// testFleet returns the vehicles of the reservation tests: 1 in service for 4 passengers, 2 retired and 3 in maintenance
+func testFleet() *repository.VehicleMap {
+	vehicles := map[int]internal.Vehicle{}
+	for id, status := range map[int]internal.VehicleStatus{1: internal.VehicleStatusInService, 2: internal.VehicleStatusRetired, 3: internal.VehicleStatusInMaintenance} {
+		vehicle := testVehicle(id, "red")
+		vehicle.Capacity, vehicle.Status = 4, status
+		vehicles[id] = vehicle
+	}
+	return repository.NewVehicleMap(vehicles)
+}
+
+func TestReservationDefault_Create(t *testing.T) {
+	from := time.Now().UTC().Add(24 * time.Hour).Truncate(time.Hour)
+	to := from.Add(2 * time.Hour)
+
+	cases := []struct {
+		name        string
+		reservation internal.Reservation
+		caller      string
+		// reserved is true when the vehicle 1 is already reserved from from to to
+		reserved bool
+		wantErr  error
+	}{
+		{"valid", internal.Reservation{VehicleID: 1, From: from, To: to, ReservedBy: "bob", Passengers: 4}, "", false, nil},
+		{"for the caller", internal.Reservation{VehicleID: 1, From: from, To: to}, "alice", false, nil},
+		{"in maintenance", internal.Reservation{VehicleID: 3, From: from, To: to, ReservedBy: "bob"}, "", false, nil},
+		{"overlapping", internal.Reservation{VehicleID: 1, From: from.Add(-time.Hour), To: from.Add(time.Minute), ReservedBy: "bob"}, "", true, internal.ErrReservationConflict},
+		{"back to back", internal.Reservation{VehicleID: 1, From: from.Add(-time.Hour), To: from, ReservedBy: "bob"}, "", true, nil},
+		{"no one", internal.Reservation{VehicleID: 1, From: from, To: to}, "", false, internal.ErrInvalidReservation},
+		{"no range", internal.Reservation{VehicleID: 1, ReservedBy: "bob"}, "", false, internal.ErrInvalidReservation},
+		{"reversed range", internal.Reservation{VehicleID: 1, From: to, To: from, ReservedBy: "bob"}, "", false, internal.ErrInvalidReservation},
+		{"in the past", internal.Reservation{VehicleID: 1, From: from.Add(-72 * time.Hour), To: to.Add(-72 * time.Hour), ReservedBy: "bob"}, "", false, internal.ErrInvalidReservation},
+		{"too many passengers", internal.Reservation{VehicleID: 1, From: from, To: to, ReservedBy: "bob", Passengers: 5}, "", false, internal.ErrInvalidReservation},
+		{"negative passengers", internal.Reservation{VehicleID: 1, From: from, To: to, ReservedBy: "bob", Passengers: -1}, "", false, internal.ErrInvalidReservation},
+		{"retired", internal.Reservation{VehicleID: 2, From: from, To: to, ReservedBy: "bob"}, "", false, internal.ErrInvalidReservation},
+		{"unknown vehicle", internal.Reservation{VehicleID: 9, From: from, To: to, ReservedBy: "bob"}, "", false, internal.ErrVehicleNotFounded},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx := context.Background()
+			rp := repository.NewReservationMap()
+			sv := NewReservationDefault(rp, testFleet(), nil)
+			if c.reserved {
+				if _, err := sv.Create(ctx, internal.Reservation{VehicleID: 1, From: from, To: to, ReservedBy: "carol"}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if c.caller != "" {
+				ctx = internal.ContextWithPrincipal(ctx, internal.Principal{Subject: c.caller})
+			}
+
+			r, err := sv.Create(ctx, c.reservation)
+			if !errors.Is(err, c.wantErr) {
+				t.Fatalf("Create() error = %v, want %v", err, c.wantErr)
+			}
+			if err == nil && c.caller != "" && r.ReservedBy != c.caller {
+				t.Fatalf("Create() reserved by = %q, want %q", r.ReservedBy, c.caller)
+			}
+		})
+	}
+}
+
+func TestReservationDefault_Available(t *testing.T) {
+	ctx := context.Background()
+	from := time.Now().UTC().Add(24 * time.Hour).Truncate(time.Hour)
+	rpVehicle := testFleet()
+	vehicle := testVehicle(4, "red")
+	vehicle.Status = internal.VehicleStatusInService
+	if err := rpVehicle.CreateVehicle(ctx, vehicle); err != nil {
+		t.Fatal(err)
+	}
+	rp := repository.NewReservationMap()
+	sv := NewReservationDefault(rp, rpVehicle, NewVehicleDefault(rpVehicle, nil, nil, nil, nil, nil, nil, nil))
+	r, err := sv.Create(ctx, internal.Reservation{VehicleID: 1, From: from, To: from.Add(2 * time.Hour), ReservedBy: "bob"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name     string
+		from, to time.Time
+		cancel   bool
+		want     []int
+		wantErr  error
+	}{
+		{"during the reservation", from.Add(time.Hour), from.Add(3 * time.Hour), false, []int{4}, nil},
+		{"after the reservation", from.Add(2 * time.Hour), from.Add(3 * time.Hour), false, []int{1, 4}, nil},
+		{"reversed range", from.Add(time.Hour), from, false, nil, internal.ErrInvalidReservation},
+		{"during a cancelled reservation", from, from.Add(time.Hour), true, []int{1, 4}, nil},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if c.cancel {
+				if _, err := sv.Cancel(ctx, 1, r.Id); err != nil {
+					t.Fatal(err)
+				}
+			}
+			v, err := sv.Available(ctx, internal.VehicleFilter{Brand: "Ford"}, c.from, c.to)
+			if !errors.Is(err, c.wantErr) {
+				t.Fatalf("Available() error = %v, want %v", err, c.wantErr)
+			}
+			if len(v) != len(c.want) {
+				t.Fatalf("Available() = %d vehicles, want %v", len(v), c.want)
+			}
+			for i := range v {
+				if v[i].Id != c.want[i] {
+					t.Fatalf("Available()[%d] = %d, want %v", i, v[i].Id, c.want)
+				}
+			}
+		})
+	}
+}
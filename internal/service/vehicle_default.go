@@ -1,122 +1,658 @@
 package service
 
-import "app/internal"
+import (
+	"app/internal"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
 
 // NewVehicleDefault is a function that returns a new instance of VehicleDefault
-func NewVehicleDefault(rp internal.VehicleRepository) *VehicleDefault {
-	return &VehicleDefault{rp: rp}
+func NewVehicleDefault(rp internal.VehicleRepository, au internal.AuditRepository, nm internal.Normalizer, ct internal.CatalogRepository, br internal.BrandRepository, rv internal.RegistrationValidator, vd internal.VINDecoder, rf *References, dp ...internal.VehicleDependentRepository) *VehicleDefault {
+	return &VehicleDefault{rp: rp, au: au, nm: nm, ct: ct, br: br, rv: rv, vd: vd, rf: rf, dp: dp}
 }
 
 // VehicleDefault is a struct that represents the default service for vehicles
 type VehicleDefault struct {
 	// rp is the repository that will be used by the service
 	rp internal.VehicleRepository
+	// au is the audit log where every mutation is recorded (nil disables the audit)
+	au internal.AuditRepository
+	// nm normalizes the attribute values on write and in the filters (nil disables the normalization)
+	nm internal.Normalizer
+	// ct are the catalogs the attributes must belong to (nil disables the check)
+	ct internal.CatalogRepository
+	// br is the registry the brand and the model must belong to (nil disables the check)
+	br internal.BrandRepository
+	// rv checks the registrations against the plate formats (nil disables the check)
+	rv internal.RegistrationValidator
+	// vd decodes the VINs to check them against the vehicles (nil disables the check and the decoding)
+	vd internal.VINDecoder
+	// rf is held by the writes checking the catalogs and the registry, shared with the services deleting their entries
+	rf *References
+	// dp are the repositories of the records of the vehicles, purged along with them
+	dp []internal.VehicleDependentRepository
+}
+
+// record is a method that appends the entries to the audit log with the caller of the request
+func (s *VehicleDefault) record(ctx context.Context, entries ...internal.AuditEntry) error {
+	if s.au == nil {
+		return nil
+	}
+
+	actor := internal.RoleAnonymous
+	if p, ok := internal.PrincipalFromContext(ctx); ok {
+		actor = p.Subject
+	}
+	requestID := internal.RequestIDFromContext(ctx)
+	now := time.Now().UTC()
+	for i := range entries {
+		entries[i].Actor = actor
+		entries[i].RequestID = requestID
+		entries[i].Timestamp = now
+	}
+
+	// the change is already applied, it must not go unnoticed
+	if err := s.au.Append(context.WithoutCancel(ctx), entries...); err != nil {
+		return fmt.Errorf("%w: %v", internal.ErrAuditFailed, err)
+	}
+	return nil
+}
+
+// value is a method that returns the canonical value of an attribute used as criteria
+func (s *VehicleDefault) value(attribute, value string) string {
+	if s.nm == nil {
+		return value
+	}
+	return s.nm.Value(attribute, value)
+}
+
+// vehicle is a method that returns a vehicle about to be written in canonical form
+func (s *VehicleDefault) vehicle(v internal.Vehicle) internal.Vehicle {
+	if s.nm == nil {
+		return v
+	}
+	return s.nm.Vehicle(v)
+}
+
+// inCatalog is a method that checks that a value belongs to a catalog
+func (s *VehicleDefault) inCatalog(ctx context.Context, kind internal.CatalogKind, value string) error {
+	if s.ct == nil {
+		return nil
+	}
+	_, err := s.ct.FindOne(ctx, kind, value)
+	if errors.Is(err, internal.ErrCatalogEntryNotFound) {
+		return fmt.Errorf("%w: %s %q", internal.ErrNotInCatalog, kind, value)
+	}
+	return err
+}
+
+// registered is a method that checks that the brand of a vehicle and its model, when known, belong to the registry
+func (s *VehicleDefault) registered(ctx context.Context, v internal.Vehicle) error {
+	if s.br == nil {
+		return nil
+	}
+	var err error
+	if v.Model == "" {
+		_, err = s.br.FindOne(ctx, v.Brand)
+	} else {
+		_, err = s.br.FindModel(ctx, v.Brand, v.Model)
+	}
+	if errors.Is(err, internal.ErrBrandNotFound) || errors.Is(err, internal.ErrModelNotFound) {
+		return fmt.Errorf("%w: %s %q", internal.ErrNotInRegistry, v.Brand, v.Model)
+	}
+	return err
+}
+
+// registration is a method that checks that a registration matches a plate format
+func (s *VehicleDefault) registration(plate string) error {
+	if s.rv == nil {
+		return nil
+	}
+	if _, err := s.rv.Validate(plate); err != nil {
+		return fmt.Errorf("%w: %q", err, plate)
+	}
+	return nil
+}
+
+// vin is a method that checks the check digit of the VIN of a vehicle and that its manufacturer
+// and model year agree with the brand and the fabrication year. Vehicles without VIN are valid
+func (s *VehicleDefault) vin(v internal.Vehicle) error {
+	if s.vd == nil || v.VIN == "" {
+		return nil
+	}
+	info, err := s.vd.Decode(v.VIN)
+	if err != nil {
+		return fmt.Errorf("%w, %q", err, v.VIN)
+	}
+	if !info.CheckDigitValid {
+		return fmt.Errorf("%w: wrong check digit in %q", internal.ErrInvalidVIN, v.VIN)
+	}
+	if info.Manufacturer != "" && internal.NormalizeKey(info.Manufacturer) != internal.NormalizeKey(v.Brand) {
+		return fmt.Errorf("%w: it was assigned to %s, not %s", internal.ErrVINMismatch, info.Manufacturer, v.Brand)
+	}
+	// the model year is the fabrication year or the next one
+	for _, year := range info.ModelYears {
+		if v.FabricationYear == year || v.FabricationYear == year-1 {
+			return nil
+		}
+	}
+	if len(info.ModelYears) > 0 {
+		return fmt.Errorf("%w: model year %v can not be built in %d", internal.ErrVINMismatch, info.ModelYears, v.FabricationYear)
+	}
+	return nil
+}
+
+// validate is a method that checks that the attributes of a vehicle belong to their catalogs,
+// its brand and model to the registry, and that its registration and VIN are valid
+func (s *VehicleDefault) validate(ctx context.Context, v internal.Vehicle) error {
+	if err := s.vin(v); err != nil {
+		return err
+	}
+	if err := s.registered(ctx, v); err != nil {
+		return err
+	}
+	return s.validatePatch(ctx, internal.VehiclePatch{Registration: &v.Registration, FuelType: &v.FuelType, Transmission: &v.Transmission, Color: &v.Color})
+}
+
+// validatePatch is a method that checks that the attributes changed by a patch belong to their catalogs
+// and that the registration it sets is valid
+func (s *VehicleDefault) validatePatch(ctx context.Context, p internal.VehiclePatch) error {
+	if p.Registration != nil {
+		if err := s.registration(*p.Registration); err != nil {
+			return err
+		}
+	}
+	for _, c := range []struct {
+		kind  internal.CatalogKind
+		value *string
+	}{
+		{internal.CatalogFuelTypes, p.FuelType},
+		{internal.CatalogTransmissions, p.Transmission},
+		{internal.CatalogColors, p.Color},
+	} {
+		if c.value == nil {
+			continue
+		}
+		if err := s.inCatalog(ctx, c.kind, *c.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// filter is a method that returns a filter with its criteria in canonical form
+func (s *VehicleDefault) filter(f internal.VehicleFilter) internal.VehicleFilter {
+	for _, c := range []struct {
+		attribute string
+		value     *string
+	}{
+		{"brand", &f.Brand},
+		{"model", &f.Model},
+		{"color", &f.Color},
+		{"fuel_type", &f.FuelType},
+		{"transmission", &f.Transmission},
+	} {
+		if *c.value != "" {
+			*c.value = s.value(c.attribute, *c.value)
+		}
+	}
+	return f
+}
+
+// patch is a method that returns a patch with its values in canonical form
+func (s *VehicleDefault) patch(p internal.VehiclePatch) internal.VehiclePatch {
+	v := s.vehicle(p.Apply(internal.Vehicle{}))
+	for _, c := range []struct {
+		dst   **string
+		value string
+	}{
+		{&p.Brand, v.Brand},
+		{&p.Model, v.Model},
+		{&p.Registration, v.Registration},
+		{&p.VIN, v.VIN},
+		{&p.Color, v.Color},
+		{&p.FuelType, v.FuelType},
+		{&p.Transmission, v.Transmission},
+	} {
+		if *c.dst != nil {
+			value := c.value
+			*c.dst = &value
+		}
+	}
+	return p
 }
 
 // FindAll is a method that returns a map of all vehicles
-func (s *VehicleDefault) FindAll() (v map[int]internal.Vehicle, err error) {
-	v, err = s.rp.FindAll()
+func (s *VehicleDefault) FindAll(ctx context.Context) (v map[int]internal.Vehicle, err error) {
+	v, err = s.rp.FindAll(ctx)
 	return
 }
 
-func (s *VehicleDefault) CreateVehicle(newVehicle internal.Vehicle) error {
-	if err := s.rp.CreateVehicle(newVehicle); err != nil {
+// FindWhere is a method that returns the vehicles matching the filter, sorted by id
+func (s *VehicleDefault) FindWhere(ctx context.Context, filter internal.VehicleFilter) (v []internal.Vehicle, err error) {
+	return s.rp.FindWhere(ctx, s.filter(filter))
+}
+
+func (s *VehicleDefault) CreateVehicle(ctx context.Context, newVehicle internal.Vehicle) error {
+	newVehicle = s.vehicle(newVehicle)
+	newVehicle.Status = internal.VehicleStatusInService
+
+	release := s.rf.Refer()
+	defer release()
+	if err := s.validate(ctx, newVehicle); err != nil {
+		return err
+	}
+	if err := s.rp.CreateVehicle(ctx, newVehicle); err != nil {
 		return err
 	}
 
-	return nil
+	return s.record(ctx, internal.AuditEntry{
+		VehicleID: newVehicle.Id,
+		Action:    internal.AuditActionCreate,
+		After:     &newVehicle,
+	})
 }
 
-func (s *VehicleDefault) FindByColorAndYear(color string, year int) (map[int]internal.Vehicle, error) {
-	vehicles, err := s.rp.FindByColorAndYear(color, year)
+func (s *VehicleDefault) FindByColorAndYear(ctx context.Context, color string, year int) (map[int]internal.Vehicle, error) {
+	vehicles, err := s.rp.FindByColorAndYear(ctx, s.value("color", color), year)
 	if err != nil {
 		return nil, err
 	}
 	return vehicles, nil
 }
 
-func (s *VehicleDefault) FindBetweenBrandAndYearRate(brand string, initialYear int, finalYear int) (map[int]internal.Vehicle, error) {
-	vehicles, err := s.rp.FindBetweenBrandAndYearRate(brand, initialYear, finalYear)
+func (s *VehicleDefault) FindBetweenBrandAndYearRate(ctx context.Context, brand string, initialYear int, finalYear int) (map[int]internal.Vehicle, error) {
+	vehicles, err := s.rp.FindBetweenBrandAndYearRate(ctx, s.value("brand", brand), initialYear, finalYear)
 	if err != nil {
 		return nil, err
 	}
 	return vehicles, nil
 }
 
-func (s *VehicleDefault) FindVelocityAverageByBrand(brand string) (float64, error) {
-	brandVelocityAverage, err := s.rp.FindVelocityAverageByBrand(brand)
+func (s *VehicleDefault) FindVelocityAverageByBrand(ctx context.Context, brand string) (float64, error) {
+	brandVelocityAverage, err := s.rp.FindVelocityAverageByBrand(ctx, s.value("brand", brand))
 	if err != nil {
 		return 0, err
 	}
 	return brandVelocityAverage, nil
 }
 
-func (s *VehicleDefault) CreateVehicules(newVehicles []internal.Vehicle) error {
-	if err := s.rp.CreateVehicules(newVehicles); err != nil {
+func (s *VehicleDefault) CreateVehicules(ctx context.Context, newVehicles []internal.Vehicle) error {
+	release := s.rf.Refer()
+	defer release()
+	for i := range newVehicles {
+		newVehicles[i] = s.vehicle(newVehicles[i])
+		newVehicles[i].Status = internal.VehicleStatusInService
+		if err := s.validate(ctx, newVehicles[i]); err != nil {
+			return err
+		}
+	}
+	if err := s.rp.CreateVehicules(ctx, newVehicles); err != nil {
 		return err
 	}
-	return nil
+
+	entries := make([]internal.AuditEntry, len(newVehicles))
+	for i := range newVehicles {
+		entries[i] = internal.AuditEntry{
+			VehicleID: newVehicles[i].Id,
+			Action:    internal.AuditActionCreate,
+			After:     &newVehicles[i],
+		}
+	}
+	return s.record(ctx, entries...)
 }
 
-func (s *VehicleDefault) UpdateMaxSpeed(vehicleID int, newMaxSpeed float64) (internal.Vehicle, error) {
-	vehiculeUpdated, err := s.rp.UpdateMaxSpeed(vehicleID, newMaxSpeed)
+func (s *VehicleDefault) UpdateMaxSpeed(ctx context.Context, vehicleID int, newMaxSpeed float64) (internal.Vehicle, error) {
+	change, err := s.rp.UpdateMaxSpeed(ctx, vehicleID, newMaxSpeed)
 	if err != nil {
 		return internal.Vehicle{}, err
 	}
-	return vehiculeUpdated, nil
+
+	err = s.record(ctx, internal.AuditEntry{
+		VehicleID: vehicleID,
+		Action:    internal.AuditActionUpdateMaxSpeed,
+		Before:    &change.Before,
+		After:     &change.After,
+	})
+	return change.After, err
 }
 
-func (s *VehicleDefault) FindVehiclesByFuelType(fuelType string) (v map[int]internal.Vehicle, err error) {
-	vehiculesFounded, err := s.rp.FindVehiclesByFuelType(fuelType)
+func (s *VehicleDefault) FindVehiclesByFuelType(ctx context.Context, fuelType string) (v map[int]internal.Vehicle, err error) {
+	vehiculesFounded, err := s.rp.FindVehiclesByFuelType(ctx, s.value("fuel_type", fuelType))
 	if err != nil {
 		return nil, err
 	}
 	return vehiculesFounded, nil
 }
 
-func (s *VehicleDefault) Delete(vehicleID int) error {
-	if err := s.rp.Delete(vehicleID); err != nil {
+func (s *VehicleDefault) Delete(ctx context.Context, vehicleID int) error {
+	change, err := s.rp.Delete(ctx, vehicleID)
+	if err != nil {
 		return err
 	}
-	return nil
+
+	return s.record(ctx, internal.AuditEntry{
+		VehicleID: vehicleID,
+		Action:    internal.AuditActionDelete,
+		Before:    &change.Before,
+	})
 }
 
-func (s *VehicleDefault) FindVehiculesByTransmissionType(transmissionType string) (v map[int]internal.Vehicle, err error) {
-	vehiclesFound, err := s.rp.FindVehiculesByTransmissionType(transmissionType)
+func (s *VehicleDefault) FindVehiculesByTransmissionType(ctx context.Context, transmissionType string) (v map[int]internal.Vehicle, err error) {
+	vehiclesFound, err := s.rp.FindVehiculesByTransmissionType(ctx, s.value("transmission", transmissionType))
 	if err != nil {
 		return nil, err
 	}
 	return vehiclesFound, nil
 }
 
-func (s *VehicleDefault) UpdateFuelType(vehicleID int, newFuelType string) (internal.Vehicle, error) {
-	vehicleUpdated, err := s.rp.UpdateFuelType(vehicleID, newFuelType)
+func (s *VehicleDefault) UpdateFuelType(ctx context.Context, vehicleID int, newFuelType string) (internal.Vehicle, error) {
+	newFuelType = s.vehicle(internal.Vehicle{VehicleAttributes: internal.VehicleAttributes{FuelType: newFuelType}}).FuelType
+
+	release := s.rf.Refer()
+	defer release()
+	if err := s.inCatalog(ctx, internal.CatalogFuelTypes, newFuelType); err != nil {
+		return internal.Vehicle{}, err
+	}
+	change, err := s.rp.UpdateFuelType(ctx, vehicleID, newFuelType)
 	if err != nil {
 		return internal.Vehicle{}, err
 	}
-	return vehicleUpdated, nil
+
+	err = s.record(ctx, internal.AuditEntry{
+		VehicleID: vehicleID,
+		Action:    internal.AuditActionUpdateFuelType,
+		Before:    &change.Before,
+		After:     &change.After,
+	})
+	return change.After, err
 }
 
-func (s *VehicleDefault) AverageBrandCapacity(brand string) (float64, error) {
-	averageBrandCapacity, err := s.rp.AverageBrandCapacity(brand)
+func (s *VehicleDefault) AverageBrandCapacity(ctx context.Context, brand string) (float64, error) {
+	averageBrandCapacity, err := s.rp.AverageBrandCapacity(ctx, s.value("brand", brand))
 	if err != nil {
 		return 0, err
 	}
 	return averageBrandCapacity, nil
 }
 
-func (s *VehicleDefault) FindVehiclesByDimensions(minLength, maxLength, minWidth, maxWidth float64) (v map[int]internal.Vehicle, err error) {
-	vehiclesFound, err := s.rp.FindVehiclesByDimensions(minLength, maxLength, minWidth, maxWidth)
+func (s *VehicleDefault) FindVehiclesByDimensions(ctx context.Context, minLength, maxLength, minWidth, maxWidth float64) (v map[int]internal.Vehicle, err error) {
+	vehiclesFound, err := s.rp.FindVehiclesByDimensions(ctx, minLength, maxLength, minWidth, maxWidth)
 	if err != nil {
 		return nil, err
 	}
 	return vehiclesFound, nil
 }
 
-func (s *VehicleDefault) FindVehiclesByWeightRate(minWeight, maxWeight float64) (v map[int]internal.Vehicle, err error) {
-	vehiclesFounded, err := s.rp.FindVehiclesByWeightRate(minWeight, maxWeight)
+func (s *VehicleDefault) FindVehiclesByWeightRate(ctx context.Context, minWeight, maxWeight float64) (v map[int]internal.Vehicle, err error) {
+	vehiclesFounded, err := s.rp.FindVehiclesByWeightRate(ctx, minWeight, maxWeight)
 	if err != nil {
 		return nil, err
 	}
 	return vehiclesFounded, nil
 }
+
+func (s *VehicleDefault) FindOne(ctx context.Context, vehicleID int) (internal.Vehicle, error) {
+	vehicle, err := s.rp.FindOne(ctx, vehicleID)
+	if err != nil {
+		return internal.Vehicle{}, err
+	}
+	return vehicle, nil
+}
+
+func (s *VehicleDefault) FindHistory(ctx context.Context, vehicleID int) ([]internal.VehicleRevision, error) {
+	revisions, err := s.rp.FindHistory(ctx, vehicleID)
+	if err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+func (s *VehicleDefault) FindAllAsOf(ctx context.Context, asOf time.Time) (map[int]internal.Vehicle, error) {
+	vehicles, err := s.rp.FindAllAsOf(ctx, asOf)
+	if err != nil {
+		return nil, err
+	}
+	return vehicles, nil
+}
+
+func (s *VehicleDefault) FindOneAsOf(ctx context.Context, vehicleID int, asOf time.Time) (internal.Vehicle, error) {
+	vehicle, err := s.rp.FindOneAsOf(ctx, vehicleID, asOf)
+	if err != nil {
+		return internal.Vehicle{}, err
+	}
+	return vehicle, nil
+}
+
+func (s *VehicleDefault) FindTrash(ctx context.Context) (map[int]internal.Vehicle, error) {
+	vehicles, err := s.rp.FindTrash(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return vehicles, nil
+}
+
+func (s *VehicleDefault) Restore(ctx context.Context, vehicleID int) (internal.Vehicle, error) {
+	vehicleRestored, err := s.rp.Restore(ctx, vehicleID)
+	if err != nil {
+		return internal.Vehicle{}, err
+	}
+
+	err = s.record(ctx, internal.AuditEntry{
+		VehicleID: vehicleID,
+		Action:    internal.AuditActionRestore,
+		After:     &vehicleRestored,
+	})
+	return vehicleRestored, err
+}
+
+// PurgeDeleted is a method that hard deletes the vehicles that have been in the trash longer than retention,
+// along with their assignments, reservations, maintenance and odometer readings
+func (s *VehicleDefault) PurgeDeleted(ctx context.Context, retention time.Duration) (int, error) {
+	purged, err := s.rp.Purge(ctx, time.Now().Add(-retention))
+	if len(purged) == 0 {
+		return 0, err
+	}
+
+	// the vehicles are gone, their records must follow even if the context ends
+	ids := make([]int, len(purged))
+	for i := range purged {
+		ids[i] = purged[i].Id
+	}
+	for _, dp := range s.dp {
+		if errDelete := dp.DeleteByVehicles(context.WithoutCancel(ctx), ids); errDelete != nil {
+			err = errors.Join(err, errDelete)
+		}
+	}
+
+	entries := make([]internal.AuditEntry, len(purged))
+	for i := range purged {
+		entries[i] = internal.AuditEntry{
+			VehicleID: purged[i].Id,
+			Action:    internal.AuditActionPurge,
+			Before:    &purged[i],
+		}
+	}
+	if errAudit := s.record(ctx, entries...); errAudit != nil {
+		return len(purged), errors.Join(err, errAudit)
+	}
+	return len(purged), err
+}
+
+func (s *VehicleDefault) BulkUpdate(ctx context.Context, filter internal.VehicleFilter, patch internal.VehiclePatch, dryRun bool) ([]int, error) {
+	// an empty filter would change the whole fleet
+	if filter.Empty() {
+		return nil, internal.ErrEmptyFilter
+	}
+	if patch.Empty() {
+		return nil, internal.ErrEmptyPatch
+	}
+
+	patch = s.patch(patch)
+
+	release := s.rf.Refer()
+	defer release()
+	if err := s.validatePatch(ctx, patch); err != nil {
+		return nil, err
+	}
+	// the VINs and the models are checked against the whole vehicles, as they would be after the update
+	if (s.vd != nil && (patch.VIN != nil || patch.Brand != nil || patch.FabricationYear != nil)) ||
+		(s.br != nil && (patch.Brand != nil || patch.Model != nil)) {
+		preview, err := s.rp.UpdateWhere(ctx, s.filter(filter), patch, true)
+		if err != nil {
+			return nil, err
+		}
+		for _, change := range preview {
+			if err := s.vin(change.After); err != nil {
+				return nil, err
+			}
+			if err := s.registered(ctx, change.After); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	changes, err := s.rp.UpdateWhere(ctx, s.filter(filter), patch, dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, len(changes))
+	entries := make([]internal.AuditEntry, len(changes))
+	for i := range changes {
+		ids[i] = changes[i].After.Id
+		entries[i] = internal.AuditEntry{
+			VehicleID: changes[i].After.Id,
+			Action:    internal.AuditActionBulkUpdate,
+			Before:    &changes[i].Before,
+			After:     &changes[i].After,
+		}
+	}
+	if dryRun || len(entries) == 0 {
+		return ids, nil
+	}
+	return ids, s.record(ctx, entries...)
+}
+
+func (s *VehicleDefault) BulkDelete(ctx context.Context, filter internal.VehicleFilter, dryRun bool) ([]int, error) {
+	// an empty filter would delete the whole fleet
+	if filter.Empty() {
+		return nil, internal.ErrEmptyFilter
+	}
+
+	deleted, err := s.rp.DeleteWhere(ctx, s.filter(filter), dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, len(deleted))
+	entries := make([]internal.AuditEntry, len(deleted))
+	for i := range deleted {
+		ids[i] = deleted[i].Id
+		entries[i] = internal.AuditEntry{
+			VehicleID: deleted[i].Id,
+			Action:    internal.AuditActionDelete,
+			Before:    &deleted[i],
+		}
+	}
+	if dryRun || len(entries) == 0 {
+		return ids, nil
+	}
+	return ids, s.record(ctx, entries...)
+}
+
+// Search is a method that finds the vehicles matching the terms of a query, best matches first
+func (s *VehicleDefault) Search(ctx context.Context, query string, limit int) ([]internal.VehicleSearchResult, error) {
+	if len(internal.SearchTokens(query)) == 0 {
+		return nil, internal.ErrEmptySearchQuery
+	}
+	return s.rp.Search(ctx, s.query(query), limit)
+}
+
+// query is a method that resolves the aliases among the terms of a search query,
+// the vehicles are indexed in canonical form so a term is looked up by the tokens of its canonical value
+func (s *VehicleDefault) query(query string) string {
+	if s.nm == nil {
+		return query
+	}
+	var terms []string
+	for _, term := range internal.SearchTokens(query) {
+		tokens := []string{term}
+		for _, attribute := range internal.SearchFields {
+			if value := s.nm.Value(attribute, term); internal.NormalizeKey(value) != term {
+				tokens = internal.SearchTokens(value)
+				break
+			}
+		}
+		terms = append(terms, tokens...)
+	}
+	return strings.Join(terms, " ")
+}
+
+// FindByRegistration is a method that returns the vehicle with the given registration
+func (s *VehicleDefault) FindByRegistration(ctx context.Context, plate string) (internal.Vehicle, error) {
+	return s.rp.FindByRegistration(ctx, plate)
+}
+
+// FindByVIN is a method that returns the vehicle with the given VIN
+func (s *VehicleDefault) FindByVIN(ctx context.Context, vin string) (internal.Vehicle, error) {
+	return s.rp.FindByVIN(ctx, vin)
+}
+
+// DecodeVIN is a method that decodes the manufacturer and the model year of a VIN
+func (s *VehicleDefault) DecodeVIN(ctx context.Context, vin string) (internal.VINInfo, error) {
+	if s.vd == nil {
+		return internal.VINInfo{}, fmt.Errorf("%w: vin decoding is disabled", internal.ErrInvalidVIN)
+	}
+	info, err := s.vd.Decode(vin)
+	if err != nil {
+		return internal.VINInfo{}, fmt.Errorf("%w, %q", err, vin)
+	}
+	return info, nil
+}
+
+// UpsertByRegistration is a method that replaces the vehicles whose registration is already in use,
+// keeping their id, and creates the others
+func (s *VehicleDefault) UpsertByRegistration(ctx context.Context, vehicles []internal.Vehicle) (created []internal.Vehicle, updated []internal.Vehicle, err error) {
+	release := s.rf.Refer()
+	defer release()
+	for i := range vehicles {
+		vehicles[i] = s.vehicle(vehicles[i])
+		// new vehicles start in service, the replaced ones keep their status
+		vehicles[i].Status = internal.VehicleStatusInService
+		if err := s.validate(ctx, vehicles[i]); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	created, changes, err := s.rp.UpsertByRegistration(ctx, vehicles)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entries := make([]internal.AuditEntry, 0, len(created)+len(changes))
+	for i := range created {
+		entries = append(entries, internal.AuditEntry{
+			VehicleID: created[i].Id,
+			Action:    internal.AuditActionCreate,
+			After:     &created[i],
+		})
+	}
+	updated = make([]internal.Vehicle, len(changes))
+	for i := range changes {
+		updated[i] = changes[i].After
+		entries = append(entries, internal.AuditEntry{
+			VehicleID: changes[i].After.Id,
+			Action:    internal.AuditActionUpsert,
+			Before:    &changes[i].Before,
+			After:     &changes[i].After,
+		})
+	}
+	if len(entries) == 0 {
+		return created, updated, nil
+	}
+	return created, updated, s.record(ctx, entries...)
+}
@@ -0,0 +1,227 @@
+package service
+
+import (
+	"app/internal"
+	"app/internal/normalize"
+	"app/internal/repository"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// testRegistry returns the registry of the test vehicles
+func testRegistry() *repository.BrandMap {
+	return repository.NewBrandMap(
+		[]internal.Brand{{Name: "Ford"}, {Name: "Fiat"}},
+		[]internal.VehicleModel{{Brand: "Ford", Name: "Focus"}, {Brand: "Fiat", Name: "Panda"}},
+	)
+}
+
+func TestVehicleDefault_CreateVehicle_Registry(t *testing.T) {
+	cases := []struct {
+		name         string
+		brand, model string
+		wantErr      error
+	}{
+		{"registered", "Ford", "Focus", nil},
+		{"registered in another case", "FORD", "focus", nil},
+		{"model unknown", "Ford", "", nil},
+		{"unknown brand", "Volvo", "V40", internal.ErrNotInRegistry},
+		{"unknown model", "Ford", "Mustang", internal.ErrNotInRegistry},
+		{"model of another brand", "Fiat", "Focus", internal.ErrNotInRegistry},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx := context.Background()
+			sv := NewVehicleDefault(repository.NewVehicleMap(nil), nil, nil, testCatalogs(), testRegistry(), nil, nil, nil)
+
+			vehicle := testVehicle(1, "red")
+			vehicle.Brand, vehicle.Model = c.brand, c.model
+			if err := sv.CreateVehicle(ctx, vehicle); !errors.Is(err, c.wantErr) {
+				t.Fatalf("CreateVehicle() error = %v, want %v", err, c.wantErr)
+			}
+		})
+	}
+}
+
+func TestVehicleDefault_BulkUpdate_Registry(t *testing.T) {
+	fiat, panda, mustang := "Fiat", "Panda", "Mustang"
+	cases := []struct {
+		name    string
+		patch   internal.VehiclePatch
+		wantErr error
+	}{
+		{"brand and model", internal.VehiclePatch{Brand: &fiat, Model: &panda}, nil},
+		{"brand without its model", internal.VehiclePatch{Brand: &fiat}, internal.ErrNotInRegistry},
+		{"unknown model", internal.VehiclePatch{Model: &mustang}, internal.ErrNotInRegistry},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx := context.Background()
+			rp := repository.NewVehicleMap(map[int]internal.Vehicle{1: testVehicle(1, "red")})
+			sv := NewVehicleDefault(rp, nil, nil, testCatalogs(), testRegistry(), nil, nil, nil)
+
+			_, err := sv.BulkUpdate(ctx, internal.VehicleFilter{Brand: "Ford"}, c.patch, false)
+			if !errors.Is(err, c.wantErr) {
+				t.Fatalf("BulkUpdate() error = %v, want %v", err, c.wantErr)
+			}
+			// a rejected patch changes nothing
+			if v, _ := rp.FindOne(ctx, 1); err != nil && (v.Brand != "Ford" || v.Model != "Focus") {
+				t.Fatalf("vehicle changed to %s %s", v.Brand, v.Model)
+			}
+		})
+	}
+}
+
+func TestVehicleDefault_Bulk(t *testing.T) {
+	blue, green := "blue", "green"
+	cases := []struct {
+		name    string
+		delete  bool
+		filter  internal.VehicleFilter
+		patch   internal.VehiclePatch
+		dryRun  bool
+		wantIDs int
+		wantErr error
+	}{
+		{"update", false, internal.VehicleFilter{Color: "red"}, internal.VehiclePatch{Color: &blue}, false, 2, nil},
+		{"update in a dry run", false, internal.VehicleFilter{Color: "red"}, internal.VehiclePatch{Color: &blue}, true, 2, nil},
+		{"update without filter", false, internal.VehicleFilter{}, internal.VehiclePatch{Color: &blue}, false, 0, internal.ErrEmptyFilter},
+		{"update without patch", false, internal.VehicleFilter{Color: "red"}, internal.VehiclePatch{}, false, 0, internal.ErrEmptyPatch},
+		{"update out of the catalog", false, internal.VehicleFilter{Color: "red"}, internal.VehiclePatch{Color: &green}, true, 0, internal.ErrNotInCatalog},
+		{"delete", true, internal.VehicleFilter{Color: "red"}, internal.VehiclePatch{}, false, 2, nil},
+		{"delete in a dry run", true, internal.VehicleFilter{Color: "red"}, internal.VehiclePatch{}, true, 2, nil},
+		{"delete without filter", true, internal.VehicleFilter{}, internal.VehiclePatch{}, false, 0, internal.ErrEmptyFilter},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx := context.Background()
+			rp := repository.NewVehicleMap(map[int]internal.Vehicle{1: testVehicle(1, "red"), 2: testVehicle(2, "red"), 3: testVehicle(3, "blue")})
+			au := repository.NewAuditSlice(nil)
+			sv := NewVehicleDefault(rp, au, nil, testCatalogs(), nil, nil, nil, nil)
+
+			var ids []int
+			var err error
+			if c.delete {
+				ids, err = sv.BulkDelete(ctx, c.filter, c.dryRun)
+			} else {
+				ids, err = sv.BulkUpdate(ctx, c.filter, c.patch, c.dryRun)
+			}
+			if !errors.Is(err, c.wantErr) {
+				t.Fatalf("error = %v, want %v", err, c.wantErr)
+			}
+			if len(ids) != c.wantIDs {
+				t.Fatalf("ids = %v, want %d", ids, c.wantIDs)
+			}
+
+			// only the applied changes reach the vehicles and the audit log
+			applied := err == nil && !c.dryRun
+			entries, _ := au.FindAll(ctx, time.Time{}, time.Time{})
+			if (len(entries) > 0) != applied {
+				t.Fatalf("audit entries = %d, applied %t", len(entries), applied)
+			}
+			all, _ := rp.FindAll(ctx)
+			if changed := len(all) != 3 || all[1].Color != "red"; changed != applied {
+				t.Fatalf("vehicles changed = %t, want %t", changed, applied)
+			}
+		})
+	}
+}
+
+func TestVehicleDefault_PurgeDeleted(t *testing.T) {
+	ctx := context.Background()
+	rp := repository.NewVehicleMap(map[int]internal.Vehicle{1: testVehicle(1, "red"), 2: testVehicle(2, "red")})
+	au := repository.NewAuditSlice(nil)
+	rpAssignment := repository.NewAssignmentMap()
+	rpReservation := repository.NewReservationMap()
+	rpMaintenance := repository.NewMaintenanceMap()
+	rpOdometer := repository.NewOdometerMap()
+	sv := NewVehicleDefault(rp, au, nil, nil, nil, nil, nil, nil, rpAssignment, rpReservation, rpMaintenance, rpOdometer)
+
+	// both vehicles have records, only the first one goes to the trash
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, id := range []int{1, 2} {
+		if _, _, err := rpAssignment.Create(ctx, internal.Assignment{Role: internal.AssignmentOwner, VehicleID: id, PersonID: 1, From: t0}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := rpReservation.Create(ctx, internal.Reservation{VehicleID: id, From: t0, To: t0.Add(time.Hour)}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := rpMaintenance.CreateRecord(ctx, internal.MaintenanceRecord{VehicleID: id, Date: t0}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := rpMaintenance.CreateSchedule(ctx, internal.MaintenanceSchedule{VehicleID: id, Type: "oil", IntervalDays: 365}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := rpOdometer.Create(ctx, internal.OdometerReading{VehicleID: id, Date: t0, Value: 100}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := sv.Delete(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	// a retention below zero purges the vehicles deleted up to now
+	n, err := sv.PurgeDeleted(ctx, -time.Second)
+	if err != nil || n != 1 {
+		t.Fatalf("PurgeDeleted() = %d %v, want 1", n, err)
+	}
+
+	counts := func(id int) []int {
+		assignments, _ := rpAssignment.FindByVehicle(ctx, id, "")
+		reservations, _ := rpReservation.FindByVehicle(ctx, id)
+		records, _ := rpMaintenance.FindRecords(ctx, id)
+		schedules, _ := rpMaintenance.FindSchedules(ctx, id)
+		readings, _ := rpOdometer.FindReadings(ctx, id)
+		return []int{len(assignments), len(reservations), len(records), len(schedules), len(readings)}
+	}
+	for id, want := range map[int]int{1: 0, 2: 1} {
+		for _, n := range counts(id) {
+			if n != want {
+				t.Fatalf("vehicle %d: records %v, want %d of each", id, counts(id), want)
+			}
+		}
+	}
+
+	entries, _ := au.FindByVehicle(ctx, 1, time.Time{}, time.Time{})
+	if len(entries) != 2 || entries[1].Action != internal.AuditActionPurge {
+		t.Fatalf("audit entries = %+v, want a delete and a purge", entries)
+	}
+}
+
+func TestVehicleDefault_Search(t *testing.T) {
+	rp := repository.NewVehicleMap(map[int]internal.Vehicle{
+		1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "Chevrolet", Model: "Onix", Color: "Gray"}},
+		2: {Id: 2, VehicleAttributes: internal.VehicleAttributes{Brand: "Volkswagen", Model: "Gol", Color: "Red"}},
+		3: {Id: 3, VehicleAttributes: internal.VehicleAttributes{Brand: "Mercedes-Benz", Model: "Sprinter", Color: "White"}},
+	})
+	sv := NewVehicleDefault(rp, nil, normalize.NewAliasTable(nil), nil, nil, nil, nil, nil)
+
+	cases := []struct {
+		query string
+		want  []int
+	}{
+		{"chevy", []int{1}},
+		{"VW gol", []int{2}},
+		{"benz", []int{3}},
+		{"grey", []int{1}},
+		{"chevrolet", []int{1}},
+	}
+	for _, c := range cases {
+		t.Run(c.query, func(t *testing.T) {
+			results, err := sv.Search(context.Background(), c.query, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ids := make([]int, len(results))
+			for i, result := range results {
+				ids[i] = result.Vehicle.Id
+			}
+			if !reflect.DeepEqual(ids, c.want) {
+				t.Fatalf("Search(%q) = %v, want %v", c.query, ids, c.want)
+			}
+		})
+	}
+}
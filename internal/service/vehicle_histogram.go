@@ -0,0 +1,142 @@
+package service
+
+import (
+	"app/internal"
+	"context"
+	"math"
+	"slices"
+	"sort"
+)
+
+// maxHistogramBuckets is the maximum number of buckets of a histogram
+const maxHistogramBuckets = 1000
+
+// Histogram is a method that returns the distribution of a numeric attribute of the vehicles matching the filter.
+// The boundaries are computed over all the vehicles so that the groups can be compared
+func (s *VehicleDefault) Histogram(ctx context.Context, filter internal.VehicleFilter, query internal.HistogramQuery) ([]internal.HistogramGroup, error) {
+	// validate the query
+	if !slices.Contains(internal.NumericFields, query.Field) {
+		return nil, internal.ErrInvalidStatsField
+	}
+	if query.GroupBy != "" && !slices.Contains(internal.CategoricalFields, query.GroupBy) {
+		return nil, internal.ErrInvalidGroupBy
+	}
+
+	vehicles, err := s.rp.FindWhere(ctx, s.filter(filter))
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]float64, len(vehicles))
+	for i, v := range vehicles {
+		values[i], _ = internal.NumericField(v, query.Field)
+	}
+	sorted := slices.Clone(values)
+	sort.Float64s(sorted)
+
+	boundaries, err := histogramBoundaries(sorted, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// count every value in its group and bucket
+	groups := make(map[string]*internal.HistogramGroup)
+	for i, v := range vehicles {
+		key, _ := internal.CategoricalField(v, query.GroupBy)
+		g, ok := groups[key]
+		if !ok {
+			g = &internal.HistogramGroup{Key: key, Buckets: make([]internal.HistogramBucket, len(boundaries)-1)}
+			for b := range g.Buckets {
+				g.Buckets[b] = internal.HistogramBucket{Lower: boundaries[b], Upper: boundaries[b+1]}
+			}
+			groups[key] = g
+		}
+		g.Count++
+
+		switch value := values[i]; {
+		case value < boundaries[0]:
+			g.Below++
+		case value > boundaries[len(boundaries)-1]:
+			g.Above++
+		default:
+			// first boundary greater than the value, the last bucket includes its upper boundary
+			b := sort.Search(len(boundaries), func(j int) bool { return boundaries[j] > value }) - 1
+			if b == len(g.Buckets) {
+				b--
+			}
+			g.Buckets[b].Count++
+		}
+	}
+
+	histogram := make([]internal.HistogramGroup, 0, len(groups))
+	for _, g := range groups {
+		histogram = append(histogram, *g)
+	}
+	sort.Slice(histogram, func(i, j int) bool { return histogram[i].Key < histogram[j].Key })
+	return histogram, nil
+}
+
+// histogramBoundaries returns the ascending boundaries of the buckets for the sorted values
+func histogramBoundaries(sorted []float64, query internal.HistogramQuery) (boundaries []float64, err error) {
+	switch query.Mode {
+	case internal.HistogramExplicit:
+		if len(query.Boundaries) < 2 || !sort.Float64sAreSorted(query.Boundaries) || len(query.Boundaries) > maxHistogramBuckets+1 {
+			return nil, internal.ErrInvalidHistogram
+		}
+		for _, b := range query.Boundaries {
+			if math.IsNaN(b) || math.IsInf(b, 0) {
+				return nil, internal.ErrInvalidHistogram
+			}
+		}
+		return query.Boundaries, nil
+	case internal.HistogramFixed, "":
+		if len(sorted) == 0 {
+			return []float64{0, 0}, nil
+		}
+		lower, upper := sorted[0], sorted[len(sorted)-1]
+		if query.Width != 0 {
+			if math.IsNaN(query.Width) || math.IsInf(query.Width, 0) || query.Width < 0 {
+				return nil, internal.ErrInvalidHistogram
+			}
+			lower = math.Floor(lower/query.Width) * query.Width
+			// counted as a float so that a tiny width can not overflow the int conversion
+			buckets := math.Floor((upper-lower)/query.Width) + 1
+			if math.IsNaN(buckets) || math.IsInf(buckets, 0) || buckets <= 0 || buckets > maxHistogramBuckets {
+				return nil, internal.ErrInvalidHistogram
+			}
+			n := int(buckets)
+			for i := 0; i <= n; i++ {
+				boundaries = append(boundaries, lower+float64(i)*query.Width)
+			}
+			return
+		}
+		if query.Buckets <= 0 || query.Buckets > maxHistogramBuckets {
+			return nil, internal.ErrInvalidHistogram
+		}
+		width := (upper - lower) / float64(query.Buckets)
+		for i := 0; i < query.Buckets; i++ {
+			boundaries = append(boundaries, lower+float64(i)*width)
+		}
+		return append(boundaries, upper), nil
+	case internal.HistogramQuantile:
+		if query.Buckets <= 0 || query.Buckets > maxHistogramBuckets {
+			return nil, internal.ErrInvalidHistogram
+		}
+		if len(sorted) == 0 {
+			return []float64{0, 0}, nil
+		}
+		boundaries = append(boundaries, sorted[0])
+		for i := 1; i < query.Buckets; i++ {
+			b := percentile(sorted, float64(i)*100/float64(query.Buckets))
+			// repeated values can make quantiles collapse
+			if b > boundaries[len(boundaries)-1] {
+				boundaries = append(boundaries, b)
+			}
+		}
+		if upper := sorted[len(sorted)-1]; upper > boundaries[len(boundaries)-1] || len(boundaries) == 1 {
+			boundaries = append(boundaries, upper)
+		}
+		return
+	}
+	return nil, internal.ErrInvalidHistogram
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"app/internal"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestHistogramBoundaries(t *testing.T) {
+	sorted := []float64{10, 20, 35}
+	cases := []struct {
+		name  string
+		query internal.HistogramQuery
+		want  []float64
+		err   error
+	}{
+		{"fixed width", internal.HistogramQuery{Mode: internal.HistogramFixed, Width: 10}, []float64{10, 20, 30, 40}, nil},
+		{"fixed buckets", internal.HistogramQuery{Mode: internal.HistogramFixed, Buckets: 5}, []float64{10, 15, 20, 25, 30, 35}, nil},
+		{"tiny width", internal.HistogramQuery{Mode: internal.HistogramFixed, Width: 1e-300}, nil, internal.ErrInvalidHistogram},
+		{"infinite width", internal.HistogramQuery{Mode: internal.HistogramFixed, Width: math.Inf(1)}, nil, internal.ErrInvalidHistogram},
+		{"NaN width", internal.HistogramQuery{Mode: internal.HistogramFixed, Width: math.NaN()}, nil, internal.ErrInvalidHistogram},
+		{"negative width", internal.HistogramQuery{Mode: internal.HistogramFixed, Width: -1}, nil, internal.ErrInvalidHistogram},
+		{"too many buckets", internal.HistogramQuery{Mode: internal.HistogramFixed, Width: 0.001}, nil, internal.ErrInvalidHistogram},
+		{"explicit", internal.HistogramQuery{Mode: internal.HistogramExplicit, Boundaries: []float64{0, 15, 50}}, []float64{0, 15, 50}, nil},
+		{"explicit infinite", internal.HistogramQuery{Mode: internal.HistogramExplicit, Boundaries: []float64{0, math.Inf(1)}}, nil, internal.ErrInvalidHistogram},
+		{"explicit NaN", internal.HistogramQuery{Mode: internal.HistogramExplicit, Boundaries: []float64{math.NaN(), 1}}, nil, internal.ErrInvalidHistogram},
+		{"explicit unsorted", internal.HistogramQuery{Mode: internal.HistogramExplicit, Boundaries: []float64{5, 1}}, nil, internal.ErrInvalidHistogram},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := histogramBoundaries(sorted, c.query)
+			if !errors.Is(err, c.err) {
+				t.Fatalf("error = %v, want %v", err, c.err)
+			}
+			if len(got) != len(c.want) {
+				t.Fatalf("boundaries = %v, want %v", got, c.want)
+			}
+			for i := range got {
+				if math.Abs(got[i]-c.want[i]) > 1e-9 {
+					t.Fatalf("boundaries = %v, want %v", got, c.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"app/internal"
+	"context"
+	"math"
+	"slices"
+	"sort"
+)
+
+// Stats is a method that aggregates the vehicles matching the filter
+func (s *VehicleDefault) Stats(ctx context.Context, filter internal.VehicleFilter, query internal.StatsQuery) ([]internal.StatsGroup, error) {
+	// validate the query
+	if query.GroupBy != "" && !slices.Contains(internal.CategoricalFields, query.GroupBy) {
+		return nil, internal.ErrInvalidGroupBy
+	}
+	if len(query.Fields) == 0 {
+		query.Fields = internal.NumericFields
+	}
+	for _, field := range query.Fields {
+		if !slices.Contains(internal.NumericFields, field) {
+			return nil, internal.ErrInvalidStatsField
+		}
+	}
+	for _, p := range query.Percentiles {
+		if math.IsNaN(p) || math.IsInf(p, 0) || p < 0 || p > 100 {
+			return nil, internal.ErrInvalidPercentile
+		}
+	}
+
+	// without filter nor order statistics the running aggregates of the repository are enough
+	if filter.Empty() && !query.Median && len(query.Percentiles) == 0 && !internal.IncludeDeletedFromContext(ctx) {
+		return s.statsFromAggregates(ctx, query)
+	}
+
+	vehicles, err := s.rp.FindWhere(ctx, s.filter(filter))
+	if err != nil {
+		return nil, err
+	}
+
+	// group
+	groups := make(map[string][]internal.Vehicle)
+	for _, v := range vehicles {
+		key, _ := internal.CategoricalField(v, query.GroupBy)
+		groups[key] = append(groups[key], v)
+	}
+
+	// aggregate
+	stats := make([]internal.StatsGroup, 0, len(groups))
+	for key, group := range groups {
+		g := internal.StatsGroup{
+			Key:    key,
+			Count:  len(group),
+			Fields: make(map[string]internal.FieldStats),
+		}
+		for _, field := range query.Fields {
+			values := make([]float64, len(group))
+			for i, v := range group {
+				values[i], _ = internal.NumericField(v, field)
+			}
+			g.Fields[field] = fieldStats(values, query.Median, query.Percentiles)
+		}
+		stats = append(stats, g)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Key < stats[j].Key })
+
+	return stats, nil
+}
+
+// statsFromAggregates is a method that builds the stats from the running aggregates of the repository
+func (s *VehicleDefault) statsFromAggregates(ctx context.Context, query internal.StatsQuery) ([]internal.StatsGroup, error) {
+	groups := make(map[string]*internal.StatsGroup)
+	for _, field := range query.Fields {
+		aggregates, err := s.rp.Aggregates(ctx, query.GroupBy, field)
+		if err != nil {
+			return nil, err
+		}
+		for key, a := range aggregates {
+			g, ok := groups[key]
+			if !ok {
+				g = &internal.StatsGroup{Key: key, Count: a.Count, Fields: make(map[string]internal.FieldStats)}
+				groups[key] = g
+			}
+			g.Fields[field] = internal.FieldStats{Min: a.Min, Max: a.Max, Sum: a.Sum, Avg: a.Avg()}
+		}
+	}
+
+	stats := make([]internal.StatsGroup, 0, len(groups))
+	for _, g := range groups {
+		stats = append(stats, *g)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Key < stats[j].Key })
+	return stats, nil
+}
+
+// fieldStats computes the aggregates of a non empty list of values
+func fieldStats(values []float64, median bool, percentiles []float64) (f internal.FieldStats) {
+	sort.Float64s(values)
+
+	f.Min = values[0]
+	f.Max = values[len(values)-1]
+	for _, v := range values {
+		f.Sum += v
+	}
+	f.Avg = f.Sum / float64(len(values))
+	if median {
+		f.Median = percentile(values, 50)
+	}
+	f.Percentiles = make(map[float64]float64)
+	for _, p := range percentiles {
+		f.Percentiles[p] = percentile(values, p)
+	}
+	return
+}
+
+// percentile returns the p-th percentile of sorted values, interpolating between the closest ranks
+func percentile(sorted []float64, p float64) float64 {
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"context"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestVehicleDefault_Stats(t *testing.T) {
+	// vehicle returns a vehicle of the given brand and maximum speed
+	vehicle := func(id int, brand string, maxSpeed float64) internal.Vehicle {
+		v := testVehicle(id, "red")
+		v.Brand, v.MaxSpeed = brand, maxSpeed
+		return v
+	}
+	rp := repository.NewVehicleMap(map[int]internal.Vehicle{
+		1: vehicle(1, "Ford", 100),
+		2: vehicle(2, "Ford", 200),
+		3: vehicle(3, "Ford", 400),
+		4: vehicle(4, "Fiat", 150),
+	})
+	sv := NewVehicleDefault(rp, nil, nil, nil, nil, nil, nil, nil)
+	speed := []string{"max_speed"}
+
+	type group struct {
+		key                   string
+		count                 int
+		min, max, avg, median float64
+		percentiles           map[float64]float64
+	}
+	cases := []struct {
+		name    string
+		filter  internal.VehicleFilter
+		query   internal.StatsQuery
+		want    []group
+		wantErr error
+	}{
+		{"fleet", internal.VehicleFilter{}, internal.StatsQuery{Fields: speed},
+			[]group{{"", 4, 100, 400, 212.5, 0, nil}}, nil},
+		{"by brand", internal.VehicleFilter{}, internal.StatsQuery{GroupBy: "brand", Fields: speed},
+			[]group{{"Fiat", 1, 150, 150, 150, 0, nil}, {"Ford", 3, 100, 400, 700.0 / 3, 0, nil}}, nil},
+		{"median of an odd count", internal.VehicleFilter{Brand: "Ford"}, internal.StatsQuery{Fields: speed, Median: true},
+			[]group{{"", 3, 100, 400, 700.0 / 3, 200, nil}}, nil},
+		{"median of an even count", internal.VehicleFilter{}, internal.StatsQuery{Fields: speed, Median: true},
+			[]group{{"", 4, 100, 400, 212.5, 175, nil}}, nil},
+		{"percentiles", internal.VehicleFilter{Brand: "Ford"}, internal.StatsQuery{Fields: speed, Percentiles: []float64{0, 25, 100}},
+			[]group{{"", 3, 100, 400, 700.0 / 3, 0, map[float64]float64{0: 100, 25: 150, 100: 400}}}, nil},
+		{"NaN percentile", internal.VehicleFilter{}, internal.StatsQuery{Fields: speed, Percentiles: []float64{math.NaN()}}, nil, internal.ErrInvalidPercentile},
+		{"infinite percentile", internal.VehicleFilter{}, internal.StatsQuery{Fields: speed, Percentiles: []float64{math.Inf(-1)}}, nil, internal.ErrInvalidPercentile},
+		{"negative percentile", internal.VehicleFilter{}, internal.StatsQuery{Fields: speed, Percentiles: []float64{-1}}, nil, internal.ErrInvalidPercentile},
+		{"percentile above 100", internal.VehicleFilter{}, internal.StatsQuery{Fields: speed, Percentiles: []float64{100.5}}, nil, internal.ErrInvalidPercentile},
+		{"unknown group", internal.VehicleFilter{}, internal.StatsQuery{GroupBy: "max_speed"}, nil, internal.ErrInvalidGroupBy},
+		{"unknown field", internal.VehicleFilter{}, internal.StatsQuery{Fields: []string{"brand"}}, nil, internal.ErrInvalidStatsField},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			stats, err := sv.Stats(context.Background(), c.filter, c.query)
+			if !errors.Is(err, c.wantErr) {
+				t.Fatalf("Stats() error = %v, want %v", err, c.wantErr)
+			}
+			if len(stats) != len(c.want) {
+				t.Fatalf("Stats() = %d groups, want %d", len(stats), len(c.want))
+			}
+			for i, want := range c.want {
+				got, fs := stats[i], stats[i].Fields["max_speed"]
+				if got.Key != want.key || got.Count != want.count || fs.Min != want.min || fs.Max != want.max ||
+					math.Abs(fs.Avg-want.avg) > 1e-9 || fs.Median != want.median {
+					t.Fatalf("Stats()[%d] = %s %d %+v, want %+v", i, got.Key, got.Count, fs, want)
+				}
+				for p, v := range want.percentiles {
+					if fs.Percentiles[p] != v {
+						t.Fatalf("Stats()[%d] p%v = %v, want %v", i, p, fs.Percentiles[p], v)
+					}
+				}
+			}
+		})
+	}
+}
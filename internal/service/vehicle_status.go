@@ -0,0 +1,30 @@
+package service
+
+import (
+	"app/internal"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Transition is a method that moves a vehicle to another status, recording the reason in the audit log
+func (s *VehicleDefault) Transition(ctx context.Context, transition internal.VehicleTransition) (internal.VehicleChange, error) {
+	transition.Reason = strings.TrimSpace(transition.Reason)
+	if transition.Reason == "" {
+		return internal.VehicleChange{}, fmt.Errorf("%w: a reason is required", internal.ErrInvalidTransition)
+	}
+
+	change, err := s.rp.Transition(ctx, transition.VehicleID, transition.To)
+	if err != nil {
+		return internal.VehicleChange{}, err
+	}
+
+	err = s.record(ctx, internal.AuditEntry{
+		VehicleID: transition.VehicleID,
+		Action:    internal.AuditActionTransition,
+		Reason:    transition.Reason,
+		Before:    &change.Before,
+		After:     &change.After,
+	})
+	return change, err
+}
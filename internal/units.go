@@ -0,0 +1,112 @@
+package internal
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrInvalidUnits = errors.New("invalid units, they must be metric or imperial")
+)
+
+// UnitSystem is a system of measurement units
+type UnitSystem string
+
+const (
+	// UnitsMetric is the canonical system: the stored data is always in metric units
+	UnitsMetric UnitSystem = "metric"
+	// UnitsImperial is the imperial system
+	UnitsImperial UnitSystem = "imperial"
+)
+
+// MeasuredFields are the attributes of a vehicle that have a unit
+var MeasuredFields = []string{"max_speed", "weight", "height", "length", "width"}
+
+// unit is a struct that represents the unit of a measured attribute in both systems
+type unit struct {
+	metric, imperial string
+	// factor converts a metric value to imperial
+	factor float64
+}
+
+// units are the units of the measured attributes
+var units = map[string]unit{
+	"max_speed": {metric: "km/h", imperial: "mph", factor: 0.621371192},
+	"weight":    {metric: "kg", imperial: "lb", factor: 2.20462262},
+	"height":    {metric: "cm", imperial: "in", factor: 0.393700787},
+	"length":    {metric: "cm", imperial: "in", factor: 0.393700787},
+	"width":     {metric: "cm", imperial: "in", factor: 0.393700787},
+	// distance is the unit of the odometers, it is not an attribute of the vehicles
+	"distance": {metric: "km", imperial: "mi", factor: 0.621371192},
+}
+
+// ParseUnitSystem is a function that parses a unit system, empty means metric
+func ParseUnitSystem(s string) (UnitSystem, error) {
+	switch UnitSystem(NormalizeKey(s)) {
+	case "", UnitsMetric:
+		return UnitsMetric, nil
+	case UnitsImperial:
+		return UnitsImperial, nil
+	}
+	return "", ErrInvalidUnits
+}
+
+// Symbol is a method that returns the unit of a measured attribute, empty when the attribute has none
+func (u UnitSystem) Symbol(field string) string {
+	if u == UnitsImperial {
+		return units[field].imperial
+	}
+	return units[field].metric
+}
+
+// FromCanonical is a method that converts a metric value of an attribute to the system
+func (u UnitSystem) FromCanonical(field string, value float64) float64 {
+	if unit, ok := units[field]; ok && u == UnitsImperial {
+		return value * unit.factor
+	}
+	return value
+}
+
+// ToCanonical is a method that converts a value of an attribute in the system to metric
+func (u UnitSystem) ToCanonical(field string, value float64) float64 {
+	if unit, ok := units[field]; ok && u == UnitsImperial {
+		return value / unit.factor
+	}
+	return value
+}
+
+// FromCanonicalVehicle is a method that returns a copy of a stored vehicle with its measures in the system
+func (u UnitSystem) FromCanonicalVehicle(v Vehicle) Vehicle {
+	return u.convertVehicle(v, u.FromCanonical)
+}
+
+// ToCanonicalVehicle is a method that returns a copy of a vehicle in the system with its measures in metric
+func (u UnitSystem) ToCanonicalVehicle(v Vehicle) Vehicle {
+	return u.convertVehicle(v, u.ToCanonical)
+}
+
+// convertVehicle applies a conversion to the measures of a vehicle
+func (u UnitSystem) convertVehicle(v Vehicle, convert func(field string, value float64) float64) Vehicle {
+	v.MaxSpeed = convert("max_speed", v.MaxSpeed)
+	v.Weight = convert("weight", v.Weight)
+	v.Height = convert("height", v.Height)
+	v.Length = convert("length", v.Length)
+	v.Width = convert("width", v.Width)
+	return v
+}
+
+// unitsKey is the key used to store the unit system in a context
+type unitsKey struct{}
+
+// ContextWithUnits returns a copy of ctx with the unit system of the request
+func ContextWithUnits(ctx context.Context, u UnitSystem) context.Context {
+	return context.WithValue(ctx, unitsKey{}, u)
+}
+
+// UnitsFromContext returns the unit system of the request, metric by default
+func UnitsFromContext(ctx context.Context) UnitSystem {
+	if u, ok := ctx.Value(unitsKey{}).(UnitSystem); ok {
+		return u
+	}
+	return UnitsMetric
+}
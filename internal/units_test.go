@@ -0,0 +1,76 @@
+package internal
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestParseUnitSystem(t *testing.T) {
+	cases := []struct {
+		s       string
+		want    UnitSystem
+		wantErr error
+	}{
+		{"", UnitsMetric, nil},
+		{"metric", UnitsMetric, nil},
+		{" Imperial ", UnitsImperial, nil},
+		{"si", "", ErrInvalidUnits},
+	}
+	for _, c := range cases {
+		t.Run(c.s, func(t *testing.T) {
+			got, err := ParseUnitSystem(c.s)
+			if got != c.want || !errors.Is(err, c.wantErr) {
+				t.Fatalf("ParseUnitSystem(%q) = %q, %v, want %q, %v", c.s, got, err, c.want, c.wantErr)
+			}
+		})
+	}
+}
+
+func TestUnitSystem_Convert(t *testing.T) {
+	cases := []struct {
+		name   string
+		units  UnitSystem
+		field  string
+		metric float64
+		want   float64
+		symbol string
+	}{
+		{"metric speed", UnitsMetric, "max_speed", 100, 100, "km/h"},
+		{"imperial speed", UnitsImperial, "max_speed", 100, 62.1371192, "mph"},
+		{"imperial weight", UnitsImperial, "weight", 1000, 2204.62262, "lb"},
+		{"imperial length", UnitsImperial, "length", 254, 100, "in"},
+		{"imperial distance", UnitsImperial, "distance", 160.9344, 100, "mi"},
+		{"imperial unmeasured", UnitsImperial, "passengers", 5, 5, ""},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := c.units.FromCanonical(c.field, c.metric)
+			if math.Abs(got-c.want) > 1e-6 {
+				t.Fatalf("FromCanonical() = %v, want %v", got, c.want)
+			}
+			if back := c.units.ToCanonical(c.field, got); math.Abs(back-c.metric) > 1e-6 {
+				t.Fatalf("ToCanonical() = %v, want %v", back, c.metric)
+			}
+			if symbol := c.units.Symbol(c.field); symbol != c.symbol {
+				t.Fatalf("Symbol() = %q, want %q", symbol, c.symbol)
+			}
+		})
+	}
+}
+
+func TestUnitSystem_Vehicle(t *testing.T) {
+	v := Vehicle{Id: 1, VehicleAttributes: VehicleAttributes{MaxSpeed: 160.9344, Weight: 453.59237, Capacity: 5, Dimensions: Dimensions{Height: 2.54}}}
+
+	out := UnitsImperial.FromCanonicalVehicle(v)
+	if math.Abs(out.MaxSpeed-100) > 1e-6 || math.Abs(out.Weight-1000) > 1e-6 || math.Abs(out.Height-1) > 1e-6 || out.Capacity != 5 {
+		t.Fatalf("FromCanonicalVehicle() = %+v", out.VehicleAttributes)
+	}
+	back := UnitsImperial.ToCanonicalVehicle(out)
+	if math.Abs(back.MaxSpeed-v.MaxSpeed) > 1e-6 || math.Abs(back.Weight-v.Weight) > 1e-6 || math.Abs(back.Height-v.Height) > 1e-6 {
+		t.Fatalf("ToCanonicalVehicle() = %+v", back.VehicleAttributes)
+	}
+	if UnitsMetric.FromCanonicalVehicle(v) != v {
+		t.Fatal("metric vehicles must not change")
+	}
+}
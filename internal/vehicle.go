@@ -1,5 +1,7 @@
 package internal
 
+import "time"
+
 // Dimensions is a struct that represents a dimension in 3d
 type Dimensions struct {
 	// Height is the height of the dimension
@@ -18,6 +20,8 @@ type VehicleAttributes struct {
 	Model string
 	// Registration is the registration of the vehicle
 	Registration string
+	// VIN is the vehicle identification number of the vehicle (empty when unknown)
+	VIN string
 	// Color is the color of the vehicle
 	Color string
 	// FabricationYear is the fabrication year of the vehicle
@@ -43,4 +47,10 @@ type Vehicle struct {
 
 	// VehicleAttribue is the attributes of a vehicle
 	VehicleAttributes
+
+	// Status is the stage of the lifecycle of the vehicle, changed by transitions only
+	Status VehicleStatus
+
+	// DeletedAt is the moment the vehicle was moved to the trash (nil when not deleted)
+	DeletedAt *time.Time
 }
@@ -0,0 +1,27 @@
+package internal
+
+import "errors"
+
+var (
+	ErrAggregatesInconsistent = errors.New("aggregates differ from the data")
+)
+
+// Aggregate is a struct that represents the running aggregates of a numeric attribute over a group of vehicles
+type Aggregate struct {
+	// Count is the number of vehicles
+	Count int
+	// Sum is the sum of the values
+	Sum float64
+	// Min is the minimum value
+	Min float64
+	// Max is the maximum value
+	Max float64
+}
+
+// Avg is a method that returns the average of the values
+func (a Aggregate) Avg() float64 {
+	if a.Count == 0 {
+		return 0
+	}
+	return a.Sum / float64(a.Count)
+}
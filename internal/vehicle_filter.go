@@ -0,0 +1,119 @@
+package internal
+
+import "errors"
+
+var (
+	ErrEmptyFilter = errors.New("the filter must have at least one criteria")
+	ErrEmptyPatch  = errors.New("the patch must change at least one attribute")
+)
+
+// VehicleFilter is a struct that represents attribute criteria over vehicles.
+// Empty strings and nil pointers mean the criteria is not applied, strings are compared by their normalized key
+type VehicleFilter struct {
+	// Brand is the exact brand
+	Brand string
+	// Model is the exact model
+	Model string
+	// Color is the exact color
+	Color string
+	// FuelType is the exact fuel type
+	FuelType string
+	// Transmission is the exact transmission
+	Transmission string
+	// YearFrom and YearTo are the inclusive fabrication year range
+	YearFrom, YearTo *int
+	// MinCapacity is the minimum number of passengers
+	MinCapacity *int
+	// Status is the exact status
+	Status VehicleStatus
+	// MinLength and MaxLength are the inclusive length range
+	MinLength, MaxLength *float64
+	// MinWidth and MaxWidth are the inclusive width range
+	MinWidth, MaxWidth *float64
+	// MinWeight and MaxWeight are the inclusive weight range
+	MinWeight, MaxWeight *float64
+}
+
+// Empty is a method that returns true when the filter has no criteria
+func (f VehicleFilter) Empty() bool {
+	return f == VehicleFilter{}
+}
+
+// Match is a method that checks if a vehicle meets all the criteria
+func (f VehicleFilter) Match(v Vehicle) bool {
+	switch {
+	case f.Brand != "" && NormalizeKey(v.Brand) != NormalizeKey(f.Brand),
+		f.Model != "" && NormalizeKey(v.Model) != NormalizeKey(f.Model),
+		f.Color != "" && NormalizeKey(v.Color) != NormalizeKey(f.Color),
+		f.FuelType != "" && NormalizeKey(v.FuelType) != NormalizeKey(f.FuelType),
+		f.Transmission != "" && NormalizeKey(v.Transmission) != NormalizeKey(f.Transmission),
+		f.YearFrom != nil && v.FabricationYear < *f.YearFrom,
+		f.YearTo != nil && v.FabricationYear > *f.YearTo,
+		f.MinCapacity != nil && v.Capacity < *f.MinCapacity,
+		f.Status != "" && v.Status != f.Status,
+		f.MinLength != nil && v.Length < *f.MinLength,
+		f.MaxLength != nil && v.Length > *f.MaxLength,
+		f.MinWidth != nil && v.Width < *f.MinWidth,
+		f.MaxWidth != nil && v.Width > *f.MaxWidth,
+		f.MinWeight != nil && v.Weight < *f.MinWeight,
+		f.MaxWeight != nil && v.Weight > *f.MaxWeight:
+		return false
+	}
+	return true
+}
+
+// VehiclePatch is a struct that represents a partial update of the attributes of a vehicle.
+// Nil pointers mean the attribute is left unchanged
+type VehiclePatch struct {
+	Brand           *string
+	Model           *string
+	Registration    *string
+	VIN             *string
+	Color           *string
+	FabricationYear *int
+	Capacity        *int
+	MaxSpeed        *float64
+	FuelType        *string
+	Transmission    *string
+	Weight          *float64
+	Height          *float64
+	Length          *float64
+	Width           *float64
+}
+
+// Empty is a method that returns true when the patch changes nothing
+func (p VehiclePatch) Empty() bool {
+	return p == VehiclePatch{}
+}
+
+// Apply is a method that returns a copy of the vehicle with the patch applied
+func (p VehiclePatch) Apply(v Vehicle) Vehicle {
+	set(&v.Brand, p.Brand)
+	set(&v.Model, p.Model)
+	set(&v.Registration, p.Registration)
+	set(&v.VIN, p.VIN)
+	set(&v.Color, p.Color)
+	set(&v.FabricationYear, p.FabricationYear)
+	set(&v.Capacity, p.Capacity)
+	set(&v.MaxSpeed, p.MaxSpeed)
+	set(&v.FuelType, p.FuelType)
+	set(&v.Transmission, p.Transmission)
+	set(&v.Weight, p.Weight)
+	set(&v.Height, p.Height)
+	set(&v.Length, p.Length)
+	set(&v.Width, p.Width)
+	return v
+}
+
+// set assigns value to dst when it is not nil
+func set[T any](dst *T, value *T) {
+	if value != nil {
+		*dst = *value
+	}
+}
+
+// VehicleChange is a struct that represents a vehicle before and after a change
+type VehicleChange struct {
+	Before Vehicle
+	After  Vehicle
+}
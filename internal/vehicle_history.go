@@ -0,0 +1,67 @@
+package internal
+
+import "time"
+
+// VehicleRevision is a struct that represents a version of a vehicle
+type VehicleRevision struct {
+	// Version is the sequence number of the revision, starting at 1
+	Version int
+	// Timestamp is the moment the revision was stored
+	Timestamp time.Time
+	// Deleted is true when the revision records the removal of the vehicle
+	Deleted bool
+	// Vehicle is the vehicle as it was in this revision
+	Vehicle Vehicle
+}
+
+// VehicleFieldChange is a struct that represents the change of an attribute between two revisions
+type VehicleFieldChange struct {
+	// Field is the name of the attribute, as exposed by the API
+	Field string
+	// From is the previous value
+	From any
+	// To is the new value
+	To any
+}
+
+// DiffVehicles is a function that returns the attributes that differ from a to b
+func DiffVehicles(a, b Vehicle) (changes []VehicleFieldChange) {
+	fields := []struct {
+		name     string
+		from, to any
+	}{
+		{"id", a.Id, b.Id},
+		{"brand", a.Brand, b.Brand},
+		{"model", a.Model, b.Model},
+		{"registration", a.Registration, b.Registration},
+		{"vin", a.VIN, b.VIN},
+		{"color", a.Color, b.Color},
+		{"year", a.FabricationYear, b.FabricationYear},
+		{"passengers", a.Capacity, b.Capacity},
+		{"max_speed", a.MaxSpeed, b.MaxSpeed},
+		{"fuel_type", a.FuelType, b.FuelType},
+		{"transmission", a.Transmission, b.Transmission},
+		{"weight", a.Weight, b.Weight},
+		{"height", a.Height, b.Height},
+		{"length", a.Length, b.Length},
+		{"width", a.Width, b.Width},
+		{"status", a.Status, b.Status},
+		{"deleted_at", deletedAt(a.DeletedAt), deletedAt(b.DeletedAt)},
+	}
+
+	changes = make([]VehicleFieldChange, 0)
+	for _, f := range fields {
+		if f.from != f.to {
+			changes = append(changes, VehicleFieldChange{Field: f.name, From: f.from, To: f.to})
+		}
+	}
+	return
+}
+
+// deletedAt returns a comparable value of the moment a vehicle was moved to the trash, nil when it is not deleted
+func deletedAt(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
@@ -0,0 +1,58 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiffVehicles(t *testing.T) {
+	deletedAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	// the same moment from another pointer must not be a change
+	sameDeletedAt := deletedAt.In(time.FixedZone("UTC+2", 2*3600))
+	base := Vehicle{Id: 1, VehicleAttributes: VehicleAttributes{Brand: "Ford", MaxSpeed: 200}, Status: VehicleStatusInService}
+
+	with := func(change func(v *Vehicle)) Vehicle {
+		v := base
+		change(&v)
+		return v
+	}
+	cases := []struct {
+		name string
+		a, b Vehicle
+		want []VehicleFieldChange
+	}{
+		{"equal", base, base, []VehicleFieldChange{}},
+		{"attribute", base, with(func(v *Vehicle) { v.MaxSpeed = 180 }), []VehicleFieldChange{{Field: "max_speed", From: 200.0, To: 180.0}}},
+		{"transition", base, with(func(v *Vehicle) { v.Status = VehicleStatusRetired }), []VehicleFieldChange{{Field: "status", From: VehicleStatusInService, To: VehicleStatusRetired}}},
+		{"trash", base, with(func(v *Vehicle) { v.DeletedAt = &deletedAt }), []VehicleFieldChange{{Field: "deleted_at", From: nil, To: "2026-01-01T00:00:00Z"}}},
+		{"restore", with(func(v *Vehicle) { v.DeletedAt = &deletedAt }), base, []VehicleFieldChange{{Field: "deleted_at", From: "2026-01-01T00:00:00Z", To: nil}}},
+		{"same deletion", with(func(v *Vehicle) { v.DeletedAt = &deletedAt }), with(func(v *Vehicle) { v.DeletedAt = &sameDeletedAt }), []VehicleFieldChange{}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := DiffVehicles(c.a, c.b); !reflect.DeepEqual(got, c.want) {
+				t.Fatalf("DiffVehicles() = %+v, want %+v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestVehicleStatus_CanTransition(t *testing.T) {
+	cases := []struct {
+		from, to VehicleStatus
+		want     bool
+	}{
+		{VehicleStatusInService, VehicleStatusInMaintenance, true},
+		{VehicleStatusInService, VehicleStatusInService, false},
+		{VehicleStatusInMaintenance, VehicleStatusReserved, false},
+		{VehicleStatusReserved, VehicleStatusInService, true},
+		{VehicleStatusRetired, VehicleStatusInService, true},
+		{VehicleStatusSold, VehicleStatusInService, false},
+	}
+	for _, c := range cases {
+		if got := c.from.CanTransition(c.to); got != c.want {
+			t.Errorf("%s.CanTransition(%s) = %v, want %v", c.from, c.to, got, c.want)
+		}
+	}
+}
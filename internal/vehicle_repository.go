@@ -1,40 +1,85 @@
 package internal
 
-import "errors"
+import (
+	"context"
+	"errors"
+	"time"
+)
 
 var (
 	ErrCarAlreadyExists  = errors.New("vehicle identifier already exists")
 	ErrVehicleNotFounded = errors.New("none vehicles found according criteria")
+	ErrVehicleNotInTrash = errors.New("vehicle not found in the trash")
 )
 
 // VehicleRepository is an interface that represents a vehicle repository
 type VehicleRepository interface {
 	// FindAll is a method that returns a map of all vehicles
-	FindAll() (v map[int]Vehicle, err error)
-	FindByID(vehicleId int) (exist bool)
+	FindAll(ctx context.Context) (v map[int]Vehicle, err error)
+	FindByID(ctx context.Context, vehicleId int) (exist bool)
+	// FindOne finds a vehicle by its identifier
+	FindOne(ctx context.Context, vehicleID int) (v Vehicle, err error)
 	// CreateVehicle creates a new vehicle in memory - requirement 1
-	CreateVehicle(newVehicle Vehicle) error
+	CreateVehicle(ctx context.Context, newVehicle Vehicle) error
 	// FindByColorAndYear filters cars according year and color - requirement 2
-	FindByColorAndYear(color string, year int) (map[int]Vehicle, error)
+	FindByColorAndYear(ctx context.Context, color string, year int) (map[int]Vehicle, error)
 	// FindBetweenBrandAndYearRate filters cars according a specific brand and year rate - requirement 3
-	FindBetweenBrandAndYearRate(brand string, initialYear int, finalYear int) (map[int]Vehicle, error)
+	FindBetweenBrandAndYearRate(ctx context.Context, brand string, initialYear int, finalYear int) (map[int]Vehicle, error)
 	// FindVelocityAverageByBrand finds an average of a specific brand - requirement 4
-	FindVelocityAverageByBrand(brand string) (float64, error)
+	FindVelocityAverageByBrand(ctx context.Context, brand string) (float64, error)
 	// CreateVehicules creates many vehicules - requirement 5
-	CreateVehicules(newVehicles []Vehicle) error
-	// UpdateMaxSpeed update only vehicle max_speed - requirement 6
-	UpdateMaxSpeed(vehicleID int, newMaxSpeed float64) (Vehicle, error)
+	CreateVehicules(ctx context.Context, newVehicles []Vehicle) error
+	// UpdateMaxSpeed update only vehicle max_speed - requirement 6. It returns the vehicle before and after the update
+	UpdateMaxSpeed(ctx context.Context, vehicleID int, newMaxSpeed float64) (VehicleChange, error)
 	// FindVehiclesByFuelType finds vehicles by fuel type - requirement 7
-	FindVehiclesByFuelType(fuelType string) (v map[int]Vehicle, err error)
-	// Delete deletes a vehicle - requirement 8
-	Delete(vehicleID int) error
+	FindVehiclesByFuelType(ctx context.Context, fuelType string) (v map[int]Vehicle, err error)
+	// Delete deletes a vehicle - requirement 8. It returns the vehicle before and after the deletion
+	Delete(ctx context.Context, vehicleID int) (VehicleChange, error)
 	// FindVehiculesByTransmissionType finds vehicles with a specific transmission type - requirement 9
-	FindVehiculesByTransmissionType(transmissionType string) (v map[int]Vehicle, err error)
-	// UpdateFuelType updates a vehicle fuel type - requirement 10
-	UpdateFuelType(vehicleID int, newFuelType string) (Vehicle, error)
+	FindVehiculesByTransmissionType(ctx context.Context, transmissionType string) (v map[int]Vehicle, err error)
+	// UpdateFuelType updates a vehicle fuel type - requirement 10. It returns the vehicle before and after the update
+	UpdateFuelType(ctx context.Context, vehicleID int, newFuelType string) (VehicleChange, error)
 	// AverageBrandCapacity calculates the average brand capacity - requirement 11
-	AverageBrandCapacity(brand string) (float64, error)
+	AverageBrandCapacity(ctx context.Context, brand string) (float64, error)
 	// FindVehiclesByDimensions finds vehicules based on a minimal and maximum length and width - requirement 12
-	FindVehiclesByDimensions(minLength, maxLength, minWidth, maxWidth float64) (v map[int]Vehicle, err error)
-	FindVehiclesByWeightRate(minWeight, maxWeight float64) (v map[int]Vehicle, err error)
+	FindVehiclesByDimensions(ctx context.Context, minLength, maxLength, minWidth, maxWidth float64) (v map[int]Vehicle, err error)
+	FindVehiclesByWeightRate(ctx context.Context, minWeight, maxWeight float64) (v map[int]Vehicle, err error)
+	// FindHistory finds all the revisions of a vehicle, oldest first
+	FindHistory(ctx context.Context, vehicleID int) (h []VehicleRevision, err error)
+	// FindAllAsOf returns the vehicles as they were at the given moment
+	FindAllAsOf(ctx context.Context, asOf time.Time) (v map[int]Vehicle, err error)
+	// FindOneAsOf finds a vehicle as it was at the given moment
+	FindOneAsOf(ctx context.Context, vehicleID int, asOf time.Time) (v Vehicle, err error)
+	// FindTrash finds the soft deleted vehicles
+	FindTrash(ctx context.Context) (v map[int]Vehicle, err error)
+	// Restore takes a vehicle out of the trash
+	Restore(ctx context.Context, vehicleID int) (v Vehicle, err error)
+	// Purge permanently removes the vehicles deleted before the given moment
+	Purge(ctx context.Context, deletedBefore time.Time) (purged []Vehicle, err error)
+	// UpdateWhere applies a patch to every vehicle matching the filter at once (only computed with dryRun)
+	UpdateWhere(ctx context.Context, filter VehicleFilter, patch VehiclePatch, dryRun bool) (changes []VehicleChange, err error)
+	// DeleteWhere soft deletes every vehicle matching the filter at once (only computed with dryRun)
+	DeleteWhere(ctx context.Context, filter VehicleFilter, dryRun bool) (deleted []Vehicle, err error)
+	// FindWhere finds the vehicles matching the filter
+	FindWhere(ctx context.Context, filter VehicleFilter) (v []Vehicle, err error)
+	// Aggregates returns the running aggregates of a numeric field grouped by a categorical attribute
+	Aggregates(ctx context.Context, groupBy string, field string) (a map[string]Aggregate, err error)
+	// Search finds the vehicles matching the terms of a query, best matches first
+	Search(ctx context.Context, query string, limit int) (r []VehicleSearchResult, err error)
+	// FindByRegistration finds a vehicle by its registration
+	FindByRegistration(ctx context.Context, plate string) (v Vehicle, err error)
+	// FindByVIN finds a vehicle by its VIN
+	FindByVIN(ctx context.Context, vin string) (v Vehicle, err error)
+	// UpsertByRegistration replaces the vehicles whose registration is in use and creates the others, all at once.
+	// It returns ErrRegistrationAlreadyExists when a registration appears twice in the batch
+	UpsertByRegistration(ctx context.Context, vehicles []Vehicle) (created []Vehicle, updated []VehicleChange, err error)
+	// Transition moves a vehicle to another status. It returns ErrInvalidTransition when its current status does not allow it
+	Transition(ctx context.Context, vehicleID int, to VehicleStatus) (change VehicleChange, err error)
+}
+
+// VehicleDependentRepository is an interface that represents a repository of records that belong to vehicles,
+// removed along with them when they are purged
+type VehicleDependentRepository interface {
+	// DeleteByVehicles removes the records of the given vehicles
+	DeleteByVehicles(ctx context.Context, vehicleIDs []int) (err error)
 }
@@ -0,0 +1,37 @@
+package internal
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+var (
+	ErrEmptySearchQuery = errors.New("empty search query")
+)
+
+// SearchFields are the attributes of a vehicle covered by the full-text search
+var SearchFields = []string{"brand", "model", "color", "registration"}
+
+// VehicleSearchResult is a struct that represents a vehicle matching a search and its relevance
+type VehicleSearchResult struct {
+	// Vehicle is the vehicle found
+	Vehicle Vehicle
+	// Score is the relevance of the vehicle, higher is better
+	Score float64
+}
+
+// SearchTokens is a function that splits a text into lowercase alphanumeric tokens
+func SearchTokens(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// VehicleSearchTokens is a function that returns the tokens of the searchable attributes of a vehicle
+func VehicleSearchTokens(v Vehicle) (tokens []string) {
+	for _, text := range []string{v.Brand, v.Model, v.Color, v.Registration} {
+		tokens = append(tokens, SearchTokens(text)...)
+	}
+	return
+}
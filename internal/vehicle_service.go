@@ -1,6 +1,10 @@
 package internal
 
-import "errors"
+import (
+	"context"
+	"errors"
+	"time"
+)
 
 var (
 	ErrInvalidBody = errors.New("invalid request body. Please check it and try again")
@@ -9,30 +13,66 @@ var (
 // VehicleService is an interface that represents a vehicle service
 type VehicleService interface {
 	// FindAll is a method that returns a map of all vehicles
-	FindAll() (v map[int]Vehicle, err error)
+	FindAll(ctx context.Context) (v map[int]Vehicle, err error)
 	// CreateVehicle creates a new vehicle in memory - requirement 1
-	CreateVehicle(newVehicle Vehicle) error
+	CreateVehicle(ctx context.Context, newVehicle Vehicle) error
 	// FindByColorAndYear filters cars according year and color - requirement 2
-	FindByColorAndYear(color string, year int) (map[int]Vehicle, error)
+	FindByColorAndYear(ctx context.Context, color string, year int) (map[int]Vehicle, error)
 	// FindBetweenBrandAndYearRate filters cars according a specific brand and year rate - requirement 3
-	FindBetweenBrandAndYearRate(brand string, initialYear int, finalYear int) (map[int]Vehicle, error)
+	FindBetweenBrandAndYearRate(ctx context.Context, brand string, initialYear int, finalYear int) (map[int]Vehicle, error)
 	// FindVelocityAverageByBrand finds an average of a specific brand - requirement 4
-	FindVelocityAverageByBrand(brand string) (float64, error)
+	FindVelocityAverageByBrand(ctx context.Context, brand string) (float64, error)
 	// CreateVehicules creates many vehicules - requirement 5
-	CreateVehicules(newVehicles []Vehicle) error
+	CreateVehicules(ctx context.Context, newVehicles []Vehicle) error
 	// UpdateMaxSpeed update only vehicle max_speed - requirement 6
-	UpdateMaxSpeed(vehicleID int, newMaxSpeed float64) (Vehicle, error)
+	UpdateMaxSpeed(ctx context.Context, vehicleID int, newMaxSpeed float64) (Vehicle, error)
 	// FindVehiclesByFuelType finds vehicles by fuel type - requirement 7
-	FindVehiclesByFuelType(fuelType string) (v map[int]Vehicle, err error)
+	FindVehiclesByFuelType(ctx context.Context, fuelType string) (v map[int]Vehicle, err error)
 	// Delete deletes a vehicle - requirement 8
-	Delete(vehicleID int) error
+	Delete(ctx context.Context, vehicleID int) error
 	// FindVehiculesByTransmissionType finds vehicles with a specific transmission type - requirement 9
-	FindVehiculesByTransmissionType(transmissionType string) (v map[int]Vehicle, err error)
+	FindVehiculesByTransmissionType(ctx context.Context, transmissionType string) (v map[int]Vehicle, err error)
 	// UpdateFuelType updates a vehicle fuel type - requirement 10
-	UpdateFuelType(vehicleID int, newFuelType string) (Vehicle, error)
+	UpdateFuelType(ctx context.Context, vehicleID int, newFuelType string) (Vehicle, error)
 	// AverageBrandCapacity calculates the average brand capacity - requirement 11
-	AverageBrandCapacity(brand string) (float64, error)
+	AverageBrandCapacity(ctx context.Context, brand string) (float64, error)
 	// FindVehiclesByDimensions finds vehicules based on a minimal and maximum length and width - requirement 12
-	FindVehiclesByDimensions(minLength, maxLength, minWidth, maxWidth float64) (v map[int]Vehicle, err error)
-	FindVehiclesByWeightRate(minWeight, maxWeight float64) (v map[int]Vehicle, err error)
+	FindVehiclesByDimensions(ctx context.Context, minLength, maxLength, minWidth, maxWidth float64) (v map[int]Vehicle, err error)
+	FindVehiclesByWeightRate(ctx context.Context, minWeight, maxWeight float64) (v map[int]Vehicle, err error)
+	// FindOne finds a vehicle by its identifier
+	FindOne(ctx context.Context, vehicleID int) (v Vehicle, err error)
+	// FindHistory finds all the revisions of a vehicle, oldest first
+	FindHistory(ctx context.Context, vehicleID int) (h []VehicleRevision, err error)
+	// FindAllAsOf returns the vehicles as they were at the given moment
+	FindAllAsOf(ctx context.Context, asOf time.Time) (v map[int]Vehicle, err error)
+	// FindOneAsOf finds a vehicle as it was at the given moment
+	FindOneAsOf(ctx context.Context, vehicleID int, asOf time.Time) (v Vehicle, err error)
+	// FindTrash finds the soft deleted vehicles
+	FindTrash(ctx context.Context) (v map[int]Vehicle, err error)
+	// Restore takes a vehicle out of the trash
+	Restore(ctx context.Context, vehicleID int) (v Vehicle, err error)
+	// PurgeDeleted permanently removes the vehicles that have been in the trash longer than retention
+	PurgeDeleted(ctx context.Context, retention time.Duration) (purged int, err error)
+	// BulkUpdate applies a patch to every vehicle matching the filter and returns their ids
+	BulkUpdate(ctx context.Context, filter VehicleFilter, patch VehiclePatch, dryRun bool) (ids []int, err error)
+	// BulkDelete deletes every vehicle matching the filter and returns their ids
+	BulkDelete(ctx context.Context, filter VehicleFilter, dryRun bool) (ids []int, err error)
+	// Stats aggregates the vehicles matching the filter
+	Stats(ctx context.Context, filter VehicleFilter, query StatsQuery) (stats []StatsGroup, err error)
+	// Histogram returns the distribution of a numeric attribute of the vehicles matching the filter
+	Histogram(ctx context.Context, filter VehicleFilter, query HistogramQuery) (histogram []HistogramGroup, err error)
+	// Search finds the vehicles matching the terms of a query, best matches first
+	Search(ctx context.Context, query string, limit int) (r []VehicleSearchResult, err error)
+	// FindByRegistration finds a vehicle by its registration
+	FindByRegistration(ctx context.Context, plate string) (v Vehicle, err error)
+	// FindByVIN finds a vehicle by its VIN
+	FindByVIN(ctx context.Context, vin string) (v Vehicle, err error)
+	// DecodeVIN decodes the manufacturer and the model year of a VIN
+	DecodeVIN(ctx context.Context, vin string) (info VINInfo, err error)
+	// UpsertByRegistration replaces the vehicles whose registration is in use and creates the others
+	UpsertByRegistration(ctx context.Context, vehicles []Vehicle) (created []Vehicle, updated []Vehicle, err error)
+	// FindWhere finds the vehicles matching the filter, sorted by id
+	FindWhere(ctx context.Context, filter VehicleFilter) (v []Vehicle, err error)
+	// Transition moves a vehicle to another status for a reason
+	Transition(ctx context.Context, transition VehicleTransition) (change VehicleChange, err error)
 }
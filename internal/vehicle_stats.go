@@ -0,0 +1,156 @@
+package internal
+
+import (
+	"errors"
+	"strconv"
+)
+
+var (
+	ErrInvalidStatsField = errors.New("invalid numeric field")
+	ErrInvalidGroupBy    = errors.New("invalid group_by attribute")
+	ErrInvalidPercentile = errors.New("invalid percentile, it must be between 0 and 100")
+)
+
+// NumericFields are the numeric attributes of a vehicle that can be aggregated
+var NumericFields = []string{"max_speed", "passengers", "weight", "height", "length", "width"}
+
+// CategoricalFields are the attributes of a vehicle that can be used to group
+var CategoricalFields = []string{"brand", "model", "color", "fuel_type", "transmission", "year", "status"}
+
+// NumericField is a function that returns the value of a numeric attribute by its API name
+func NumericField(v Vehicle, field string) (value float64, ok bool) {
+	switch field {
+	case "max_speed":
+		return v.MaxSpeed, true
+	case "passengers":
+		return float64(v.Capacity), true
+	case "weight":
+		return v.Weight, true
+	case "height":
+		return v.Height, true
+	case "length":
+		return v.Length, true
+	case "width":
+		return v.Width, true
+	}
+	return 0, false
+}
+
+// CategoricalField is a function that returns the value of a categorical attribute by its API name
+func CategoricalField(v Vehicle, field string) (value string, ok bool) {
+	switch field {
+	case "brand":
+		return v.Brand, true
+	case "model":
+		return v.Model, true
+	case "color":
+		return v.Color, true
+	case "fuel_type":
+		return v.FuelType, true
+	case "transmission":
+		return v.Transmission, true
+	case "year":
+		return strconv.Itoa(v.FabricationYear), true
+	case "status":
+		return string(v.Status), true
+	}
+	return "", false
+}
+
+// StatsQuery is a struct that represents what to compute over the vehicles
+type StatsQuery struct {
+	// GroupBy is the categorical attribute to group by (empty means a single group)
+	GroupBy string
+	// Fields are the numeric attributes to aggregate (empty means all)
+	Fields []string
+	// Median requests the median, which needs all the values
+	Median bool
+	// Percentiles are the percentiles to compute, between 0 and 100
+	Percentiles []float64
+}
+
+// FieldStats is a struct that represents the aggregates of a numeric attribute
+type FieldStats struct {
+	Min    float64
+	Max    float64
+	Sum    float64
+	Avg    float64
+	Median float64
+	// Percentiles is a map of values by percentile
+	Percentiles map[float64]float64
+}
+
+// In is a method that converts the metric aggregates of a field to a unit system
+func (s FieldStats) In(u UnitSystem, field string) FieldStats {
+	percentiles := make(map[float64]float64, len(s.Percentiles))
+	for p, value := range s.Percentiles {
+		percentiles[p] = u.FromCanonical(field, value)
+	}
+	return FieldStats{
+		Min:         u.FromCanonical(field, s.Min),
+		Max:         u.FromCanonical(field, s.Max),
+		Sum:         u.FromCanonical(field, s.Sum),
+		Avg:         u.FromCanonical(field, s.Avg),
+		Median:      u.FromCanonical(field, s.Median),
+		Percentiles: percentiles,
+	}
+}
+
+// StatsGroup is a struct that represents the aggregates of a group of vehicles
+type StatsGroup struct {
+	// Key is the value of the group_by attribute (empty without group_by)
+	Key string
+	// Count is the number of vehicles in the group
+	Count int
+	// Fields is a map of aggregates by numeric attribute
+	Fields map[string]FieldStats
+}
+
+var (
+	ErrInvalidHistogram = errors.New("invalid histogram buckets")
+)
+
+// Histogram bucketing modes
+const (
+	// HistogramFixed splits the range of values in Buckets buckets of the same width, or buckets of Width
+	HistogramFixed = "fixed"
+	// HistogramExplicit uses the given Boundaries
+	HistogramExplicit = "explicit"
+	// HistogramQuantile splits the values in Buckets buckets with the same number of vehicles
+	HistogramQuantile = "quantile"
+)
+
+// HistogramQuery is a struct that represents how to build the distribution of a numeric attribute
+type HistogramQuery struct {
+	// Field is the numeric attribute
+	Field string
+	// GroupBy is the categorical attribute to group by (empty means a single group)
+	GroupBy string
+	// Mode is the bucketing mode
+	Mode string
+	// Buckets is the number of buckets for the fixed and quantile modes
+	Buckets int
+	// Width is the width of the buckets for the fixed mode (overrides Buckets)
+	Width float64
+	// Boundaries are the sorted boundaries for the explicit mode
+	Boundaries []float64
+}
+
+// HistogramBucket is a struct that represents the vehicles in [Lower, Upper), the last bucket includes Upper
+type HistogramBucket struct {
+	Lower float64
+	Upper float64
+	Count int
+}
+
+// HistogramGroup is a struct that represents the distribution of a group of vehicles
+type HistogramGroup struct {
+	// Key is the value of the group_by attribute (empty without group_by)
+	Key string
+	// Count is the number of vehicles in the group
+	Count int
+	// Buckets are the buckets in ascending order
+	Buckets []HistogramBucket
+	// Below and Above are the vehicles out of the boundaries
+	Below, Above int
+}
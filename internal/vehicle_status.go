@@ -0,0 +1,80 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var (
+	ErrInvalidVehicleStatus = errors.New("invalid status, it must be in_service, in_maintenance, reserved, retired or sold")
+	ErrInvalidTransition    = errors.New("invalid status transition")
+)
+
+// VehicleStatus is the stage of the lifecycle of a vehicle
+type VehicleStatus string
+
+const (
+	// VehicleStatusInService is the status of the vehicles in use, the one of new vehicles
+	VehicleStatusInService VehicleStatus = "in_service"
+	// VehicleStatusInMaintenance is the status of the vehicles out of use while serviced
+	VehicleStatusInMaintenance VehicleStatus = "in_maintenance"
+	// VehicleStatusReserved is the status of the vehicles held for a single use
+	VehicleStatusReserved VehicleStatus = "reserved"
+	// VehicleStatusRetired is the status of the vehicles taken out of the fleet, they may come back into service
+	VehicleStatusRetired VehicleStatus = "retired"
+	// VehicleStatusSold is the final status of the vehicles sold
+	VehicleStatusSold VehicleStatus = "sold"
+)
+
+// VehicleStatuses are the statuses of a vehicle in lifecycle order
+var VehicleStatuses = []VehicleStatus{
+	VehicleStatusInService,
+	VehicleStatusInMaintenance,
+	VehicleStatusReserved,
+	VehicleStatusRetired,
+	VehicleStatusSold,
+}
+
+// transitions are the statuses a vehicle can move to from each status
+var transitions = map[VehicleStatus][]VehicleStatus{
+	VehicleStatusInService:     {VehicleStatusInMaintenance, VehicleStatusReserved, VehicleStatusRetired, VehicleStatusSold},
+	VehicleStatusInMaintenance: {VehicleStatusInService, VehicleStatusRetired, VehicleStatusSold},
+	VehicleStatusReserved:      {VehicleStatusInService, VehicleStatusInMaintenance},
+	VehicleStatusRetired:       {VehicleStatusInService, VehicleStatusSold},
+	VehicleStatusSold:          {},
+}
+
+// ParseVehicleStatus is a function that parses a status by its normalized key
+func ParseVehicleStatus(s string) (VehicleStatus, error) {
+	status := VehicleStatus(NormalizeKey(s))
+	if _, ok := transitions[status]; !ok {
+		return "", fmt.Errorf("%w: %q", ErrInvalidVehicleStatus, s)
+	}
+	return status, nil
+}
+
+// Transitions is a method that returns the statuses a vehicle can move to from the status
+func (s VehicleStatus) Transitions() []VehicleStatus {
+	return transitions[s]
+}
+
+// CanTransition is a method that checks if a vehicle can move from the status to another one
+func (s VehicleStatus) CanTransition(to VehicleStatus) bool {
+	return slices.Contains(transitions[s], to)
+}
+
+// Operational is a method that checks if a vehicle with the status still belongs to the fleet
+func (s VehicleStatus) Operational() bool {
+	return s != VehicleStatusRetired && s != VehicleStatusSold
+}
+
+// VehicleTransition is a struct that represents a request to move a vehicle to another status
+type VehicleTransition struct {
+	// VehicleID is the id of the vehicle
+	VehicleID int
+	// To is the status to move to
+	To VehicleStatus
+	// Reason is why the vehicle moves, it is recorded in the audit log
+	Reason string
+}
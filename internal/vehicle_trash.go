@@ -0,0 +1,17 @@
+package internal
+
+import "context"
+
+// includeDeletedKey is the key used to store the include deleted flag in a context
+type includeDeletedKey struct{}
+
+// ContextWithIncludeDeleted returns a copy of ctx that makes the Find* queries return soft deleted vehicles too
+func ContextWithIncludeDeleted(ctx context.Context) context.Context {
+	return context.WithValue(ctx, includeDeletedKey{}, true)
+}
+
+// IncludeDeletedFromContext returns true when soft deleted vehicles must be included in the queries
+func IncludeDeletedFromContext(ctx context.Context) bool {
+	include, _ := ctx.Value(includeDeletedKey{}).(bool)
+	return include
+}
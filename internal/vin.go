@@ -0,0 +1,111 @@
+package internal
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrInvalidVIN       = errors.New("invalid vin")
+	ErrVINMismatch      = errors.New("vin does not match the vehicle")
+	ErrVINAlreadyExists = errors.New("vin already in use by another vehicle")
+	ErrVINAmbiguous     = errors.New("vin shared by several vehicles")
+)
+
+// vinWeights are the weights of the positions of a VIN in the check digit (ISO 3779, position 9 is the check digit itself)
+var vinWeights = [17]int{8, 7, 6, 5, 4, 3, 2, 10, 0, 9, 8, 7, 6, 5, 4, 3, 2}
+
+// vinYearCodes are the model year codes of position 10, from 1980 in cycles of 30 years
+const vinYearCodes = "ABCDEFGHJKLMNPRSTVWXY123456789"
+
+// VINInfo is a struct that represents the information decoded from a VIN
+type VINInfo struct {
+	// VIN is the normalized VIN
+	VIN string
+	// WMI is the world manufacturer identifier, positions 1 to 3
+	WMI string
+	// VDS is the vehicle descriptor section, positions 4 to 9
+	VDS string
+	// VIS is the vehicle identifier section, positions 10 to 17
+	VIS string
+	// Region is the region of the manufacturer, from position 1
+	Region string
+	// Manufacturer is the brand of the WMI (empty when unknown)
+	Manufacturer string
+	// CheckDigit is the check digit of position 9
+	CheckDigit byte
+	// CheckDigitValid is true when the check digit matches the one computed
+	CheckDigitValid bool
+	// ModelYears are the model years position 10 may stand for, oldest first
+	ModelYears []int
+	// Serial is the production sequence number, positions 12 to 17
+	Serial string
+}
+
+// VINDecoder is an interface that decodes VINs
+type VINDecoder interface {
+	// Decode returns the information of a VIN, or ErrInvalidVIN when it is malformed
+	Decode(vin string) (info VINInfo, err error)
+}
+
+// NormalizeVIN is a function that returns a VIN upper case and without spaces
+func NormalizeVIN(vin string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(vin), ""))
+}
+
+// vinValue returns the transliterated value of a character of a VIN, I, O and Q are not allowed
+func vinValue(c byte) (int, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c - '0'), true
+	case c >= 'A' && c <= 'H':
+		return int(c-'A') + 1, true
+	case c >= 'J' && c <= 'N':
+		return int(c-'J') + 1, true
+	case c == 'P':
+		return 7, true
+	case c == 'R':
+		return 9, true
+	case c >= 'S' && c <= 'Z':
+		return int(c-'S') + 2, true
+	}
+	return 0, false
+}
+
+// VINCheckDigit is a function that computes the check digit of a normalized VIN of 17 characters
+func VINCheckDigit(vin string) (byte, error) {
+	if len(vin) != 17 {
+		return 0, errors.New("it must have 17 characters")
+	}
+	sum := 0
+	for i := 0; i < len(vin); i++ {
+		value, ok := vinValue(vin[i])
+		if !ok {
+			return 0, errors.New("it contains characters other than digits and letters except I, O and Q")
+		}
+		sum += value * vinWeights[i]
+	}
+	if sum%11 == 10 {
+		return 'X', nil
+	}
+	return byte('0' + sum%11), nil
+}
+
+// VINModelYears is a function that returns the model years the code of position 10 may stand for,
+// within two cycles from 1980. North American VINs tell the cycle with position 7: digits before 2010, letters after
+func VINModelYears(vin string) (years []int) {
+	if len(vin) != 17 {
+		return nil
+	}
+	i := strings.IndexByte(vinYearCodes, vin[9])
+	if i < 0 {
+		return nil
+	}
+	if vin[0] >= '1' && vin[0] <= '5' {
+		if vin[6] >= '0' && vin[6] <= '9' {
+			return []int{1980 + i}
+		}
+		return []int{2010 + i}
+	}
+	return []int{1980 + i, 2010 + i}
+}
@@ -0,0 +1,95 @@
+package vin
+
+import (
+	"app/internal"
+	"fmt"
+)
+
+// DefaultManufacturers are the brands of the most common world manufacturer identifiers
+var DefaultManufacturers = map[string]string{
+	// North America
+	"1FA": "Ford", "1FB": "Ford", "1FC": "Ford", "1FD": "Ford", "1FM": "Ford", "1FT": "Ford",
+	"1G1": "Chevrolet", "1GC": "Chevrolet", "1GN": "Chevrolet", "2G1": "Chevrolet", "3G1": "Chevrolet",
+	"1GT": "GMC", "1GK": "GMC",
+	"1G4": "Buick", "1G6": "Cadillac", "1GY": "Cadillac", "1G2": "Pontiac", "1G3": "Oldsmobile",
+	"5GR": "Hummer", "5GT": "Hummer", "137": "Hummer",
+	"1B3": "Dodge", "2B3": "Dodge", "1D7": "Dodge", "2C3": "Chrysler", "1C3": "Chrysler",
+	"1J4": "Jeep", "1C4": "Jeep",
+	"1L1": "Lincoln", "1LN": "Lincoln", "2ME": "Mercury", "1ME": "Mercury",
+	"1HG": "Honda", "2HG": "Honda", "5FN": "Honda",
+	"4T1": "Toyota", "5TD": "Toyota", "2T1": "Toyota",
+	"1N4": "Nissan", "5N1": "Nissan",
+	"5YJ": "Tesla",
+	// Asia
+	"JHM": "Honda", "JH4": "Acura",
+	"JT2": "Toyota", "JTD": "Toyota", "JTE": "Toyota", "JTH": "Lexus",
+	"JN1": "Nissan", "JN8": "Nissan", "JNK": "Infiniti",
+	"JM1": "Mazda", "JF1": "Subaru", "JA3": "Mitsubishi", "JA4": "Mitsubishi", "JS3": "Suzuki",
+	"KMH": "Hyundai", "KNA": "Kia", "KND": "Kia",
+	// Europe
+	"WBA": "BMW", "WBS": "BMW", "WMW": "Mini",
+	"WDB": "Mercedes-Benz", "WDD": "Mercedes-Benz", "W1K": "Mercedes-Benz",
+	"WVW": "Volkswagen", "WV1": "Volkswagen", "WV2": "Volkswagen",
+	"WAU": "Audi", "WP0": "Porsche", "WP1": "Porsche",
+	"VF1": "Renault", "VF3": "Peugeot", "VF7": "Citroen",
+	"ZFA": "Fiat", "ZAR": "Alfa Romeo", "ZFF": "Ferrari", "ZHW": "Lamborghini", "ZAM": "Maserati",
+	"SAL": "Land Rover", "SAJ": "Jaguar", "SCC": "Lotus", "SCF": "Aston Martin",
+	"YV1": "Volvo", "YS3": "Saab",
+}
+
+// NewDecoder is a function that returns a new instance of Decoder
+func NewDecoder(manufacturers map[string]string) *Decoder {
+	// default manufacturers
+	if manufacturers == nil {
+		manufacturers = DefaultManufacturers
+	}
+	return &Decoder{manufacturers: manufacturers}
+}
+
+// Decoder is a struct that decodes VINs with a table of world manufacturer identifiers
+type Decoder struct {
+	// manufacturers are the brands by world manufacturer identifier
+	manufacturers map[string]string
+}
+
+// Decode is a method that returns the information of a VIN
+func (d *Decoder) Decode(vin string) (info internal.VINInfo, err error) {
+	vin = internal.NormalizeVIN(vin)
+	check, err := internal.VINCheckDigit(vin)
+	if err != nil {
+		return internal.VINInfo{}, fmt.Errorf("%w: %v", internal.ErrInvalidVIN, err)
+	}
+
+	info = internal.VINInfo{
+		VIN:             vin,
+		WMI:             vin[:3],
+		VDS:             vin[3:9],
+		VIS:             vin[9:],
+		Region:          region(vin[0]),
+		Manufacturer:    d.manufacturers[vin[:3]],
+		CheckDigit:      vin[8],
+		CheckDigitValid: vin[8] == check,
+		ModelYears:      internal.VINModelYears(vin),
+		Serial:          vin[11:],
+	}
+	return
+}
+
+// region returns the region of the manufacturer from the first character of a VIN
+func region(c byte) string {
+	switch {
+	case c >= 'A' && c <= 'H':
+		return "Africa"
+	case c >= 'J' && c <= 'R':
+		return "Asia"
+	case c >= 'S' && c <= 'Z':
+		return "Europe"
+	case c >= '1' && c <= '5':
+		return "North America"
+	case c == '6' || c == '7':
+		return "Oceania"
+	case c == '8' || c == '9':
+		return "South America"
+	}
+	return ""
+}
@@ -0,0 +1,94 @@
+package vin
+
+import (
+	"app/internal"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDecoder_Decode(t *testing.T) {
+	info, err := NewDecoder(nil).Decode(" 1hgcm82633a004352 ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := internal.VINInfo{
+		VIN:             "1HGCM82633A004352",
+		WMI:             "1HG",
+		VDS:             "CM8263",
+		VIS:             "3A004352",
+		Region:          "North America",
+		Manufacturer:    "Honda",
+		CheckDigit:      '3',
+		CheckDigitValid: true,
+		ModelYears:      []int{2003},
+		Serial:          "004352",
+	}
+	if !reflect.DeepEqual(info, want) {
+		t.Fatalf("Decode() = %+v, want %+v", info, want)
+	}
+}
+
+func TestDecoder_Decode_Cases(t *testing.T) {
+	cases := []struct {
+		name             string
+		vin              string
+		wantCheckValid   bool
+		wantRegion       string
+		wantManufacturer string
+		wantYears        []int
+	}{
+		{"all ones", "11111111111111111", true, "North America", "", []int{2001}},
+		{"wrong check digit", "1HGCM82634A004352", false, "North America", "Honda", []int{2004}},
+		{"check digit X", "1M8GDM9AXKP042788", true, "North America", "", []int{1989}},
+		// North America tells the cycle with position 7, a letter means 2010 onwards
+		{"north america after 2010", "5YJ3E1EA7KF317000", false, "North America", "Tesla", []int{2019}},
+		// elsewhere the cycle is ambiguous
+		{"europe", "WVWZZZ1JZ3W386752", false, "Europe", "Volkswagen", []int{2003, 2033}},
+		{"unknown year code", "JHMCM826XU3004352", true, "Asia", "Honda", nil},
+	}
+	d := NewDecoder(nil)
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			info, err := d.Decode(c.vin)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.CheckDigitValid != c.wantCheckValid || info.Region != c.wantRegion || info.Manufacturer != c.wantManufacturer || !reflect.DeepEqual(info.ModelYears, c.wantYears) {
+				t.Fatalf("Decode(%q) = %+v", c.vin, info)
+			}
+		})
+	}
+}
+
+func TestDecoder_Decode_Invalid(t *testing.T) {
+	cases := []struct {
+		name string
+		vin  string
+	}{
+		{"short", "1HGCM8263"},
+		{"long", "1HGCM82633A0043521"},
+		{"letter O", "1HGCM82633A00435O"},
+		{"letter I", "IHGCM82633A004352"},
+		{"symbol", "1HGCM82633A00435-"},
+		{"empty", ""},
+	}
+	d := NewDecoder(map[string]string{"1HG": "Honda"})
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if _, err := d.Decode(c.vin); !errors.Is(err, internal.ErrInvalidVIN) {
+				t.Fatalf("Decode(%q) error = %v, want %v", c.vin, err, internal.ErrInvalidVIN)
+			}
+		})
+	}
+}
+
+func TestDecoder_Manufacturers(t *testing.T) {
+	d := NewDecoder(map[string]string{"1HG": "Custom"})
+	if info, _ := d.Decode("1HGCM82633A004352"); info.Manufacturer != "Custom" {
+		t.Fatalf("Manufacturer = %q, want the configured one", info.Manufacturer)
+	}
+	if info, _ := d.Decode("WVWZZZ1JZ3W386752"); info.Manufacturer != "" {
+		t.Fatalf("Manufacturer = %q, want none out of the configured table", info.Manufacturer)
+	}
+}